@@ -0,0 +1,224 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/pool"
+)
+
+var errConcurrencyTest = errors.New("concurrency test error")
+
+func TestParallelMapKeepsOrder(t *testing.T) {
+	t.Parallel()
+
+	items := []int{5, 1, 4, 2, 3}
+
+	results, err := ParallelMap(t.Context(), items, func(_ context.Context, item int) (string, error) {
+		time.Sleep(time.Duration(item) * time.Millisecond)
+
+		return strconv.Itoa(item), nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"5", "1", "4", "2", "3"}, results)
+}
+
+func TestParallelMapRespectsLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		running    atomic.Int32
+		maxRunning atomic.Int32
+	)
+
+	items := make([]int, 20)
+
+	_, err := ParallelMap(t.Context(), items, func(_ context.Context, item int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+
+		time.Sleep(2 * time.Millisecond)
+
+		return item, nil
+	}, WithLimit(3))
+
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxRunning.Load(), int32(3))
+	require.Positive(t, maxRunning.Load())
+}
+
+func TestParallelMapStopsOnFirstError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		started  atomic.Int32
+		canceled atomic.Int32
+	)
+
+	items := []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+	results, err := ParallelMap(t.Context(), items, func(ctx context.Context, item int) (int, error) {
+		started.Add(1)
+
+		if item == 0 {
+			return 0, errConcurrencyTest
+		}
+
+		select {
+		case <-ctx.Done():
+			canceled.Add(1)
+
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return item, nil
+		}
+	}, WithLimit(2))
+
+	require.ErrorIs(t, err, errConcurrencyTest)
+	require.Nil(t, results)
+	require.Less(t, started.Load(), int32(len(items)))
+	require.Equal(t, started.Load()-1, canceled.Load())
+}
+
+func TestParallelMapKeyed(t *testing.T) {
+	t.Parallel()
+
+	items := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	results, err := ParallelMapKeyed(t.Context(), items, func(_ context.Context, key string, item int) (string, error) {
+		return key + strconv.Itoa(item*10), nil
+	}, WithLimit(2))
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "a10", "b": "b20", "c": "c30"}, results)
+}
+
+func TestParallelForEach(t *testing.T) {
+	t.Parallel()
+
+	var sum atomic.Int64
+
+	err := ParallelForEach(t.Context(), []int{1, 2, 3, 4}, func(_ context.Context, item int) error {
+		sum.Add(int64(item))
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, int64(10), sum.Load())
+}
+
+func TestGroupConvertsPanicToError(t *testing.T) {
+	t.Parallel()
+
+	group, _ := NewGroup(t.Context())
+
+	group.Go(func(_ context.Context) error {
+		panic(errConcurrencyTest)
+	})
+
+	err := group.Wait()
+
+	var panicErr *PanicError
+
+	require.ErrorAs(t, err, &panicErr)
+	require.ErrorIs(t, err, ErrPanic)
+	require.ErrorIs(t, err, errConcurrencyTest)
+	require.NotEmpty(t, panicErr.Stack)
+}
+
+func TestGroupCancelsContextOnError(t *testing.T) {
+	t.Parallel()
+
+	group, ctx := NewGroup(t.Context())
+
+	group.Go(func(_ context.Context) error {
+		return errConcurrencyTest
+	})
+
+	group.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, group.Wait(), errConcurrencyTest)
+	require.ErrorIs(t, context.Cause(ctx), errConcurrencyTest)
+}
+
+func TestGroupWaitWithoutErrors(t *testing.T) {
+	t.Parallel()
+
+	group, ctx := NewGroup(t.Context(), WithLimit(1))
+
+	for range 3 {
+		group.Go(func(_ context.Context) error {
+			return nil
+		})
+	}
+
+	require.NoError(t, group.Wait())
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestGroupParentCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	var called atomic.Bool
+
+	group, _ := NewGroup(ctx)
+
+	group.Go(func(_ context.Context) error {
+		called.Store(true)
+
+		return nil
+	})
+
+	require.ErrorIs(t, group.Wait(), context.Canceled)
+	require.False(t, called.Load())
+}
+
+func TestGroupRunsOnPool(t *testing.T) {
+	t.Parallel()
+
+	poolExecutor := pool.New("concurrency-test", 2)
+	require.NoError(t, poolExecutor.Start(t.Context()))
+
+	defer func() {
+		require.NoError(t, poolExecutor.Stop())
+	}()
+
+	results, err := ParallelMap(t.Context(), []int{1, 2, 3, 4}, func(_ context.Context, item int) (int, error) {
+		return item * item, nil
+	}, WithPoolExecutor(poolExecutor), WithLimit(2))
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 4, 9, 16}, results)
+}
+
+func TestGroupPoolNotStarted(t *testing.T) {
+	t.Parallel()
+
+	err := ParallelForEach(t.Context(), []int{1}, func(_ context.Context, _ int) error {
+		return nil
+	}, WithPoolExecutor(pool.New("not-started", 1)))
+
+	require.ErrorIs(t, err, pool.ErrNotStarted)
+}
@@ -0,0 +1,162 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+var ErrPanic = errors.New("panic recovered")
+
+// PanicError is returned by Group.Wait when a function panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrPanic.Error(), e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+
+	return []error{ErrPanic}
+}
+
+// Group runs functions concurrently with an optional concurrency limit.
+// The first error (or panic, converted to *PanicError) cancels the group context and is returned by Wait.
+type Group struct {
+	ctx       context.Context //nolint:containedctx
+	cancel    context.CancelCauseFunc
+	options   *Options
+	semaphore chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+	err       error
+}
+
+func NewGroup(ctx context.Context, options ...Option) (*Group, context.Context) {
+	opts := applyOptions(options...)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	group := &Group{
+		ctx:       ctx,
+		cancel:    cancel,
+		options:   opts,
+		semaphore: nil,
+		wg:        sync.WaitGroup{},
+		mutex:     sync.Mutex{},
+		err:       nil,
+	}
+
+	if opts.limit > 0 {
+		group.semaphore = make(chan struct{}, opts.limit)
+	}
+
+	return group, ctx
+}
+
+// Go schedules fn, blocking while the group is at its concurrency limit.
+// Once the group context is done no new functions are started.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	if !g.acquire() {
+		return
+	}
+
+	g.wg.Add(1)
+
+	task := func(ctx context.Context) error {
+		defer g.wg.Done()
+		defer g.release()
+
+		if err := g.run(ctx, fn); err != nil {
+			g.fail(err)
+		}
+
+		return nil
+	}
+
+	if g.options.poolExecutor == nil {
+		go func() {
+			_ = task(g.ctx)
+		}()
+
+		return
+	}
+
+	if err := g.options.poolExecutor.Execute(g.ctx, task); err != nil {
+		g.wg.Done()
+		g.release()
+		g.fail(err)
+	}
+}
+
+// Wait blocks until all scheduled functions return and reports the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+
+	g.cancel(nil)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.err
+}
+
+func (g *Group) acquire() bool {
+	if g.ctx.Err() != nil {
+		g.fail(context.Cause(g.ctx))
+
+		return false
+	}
+
+	if g.semaphore == nil {
+		return true
+	}
+
+	select {
+	case g.semaphore <- struct{}{}:
+		return true
+	case <-g.ctx.Done():
+		g.fail(context.Cause(g.ctx))
+
+		return false
+	}
+}
+
+func (g *Group) release() {
+	if g.semaphore != nil {
+		<-g.semaphore
+	}
+}
+
+func (g *Group) fail(err error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.err != nil {
+		return
+	}
+
+	g.err = err
+	g.cancel(err)
+}
+
+func (g *Group) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{
+				Value: value,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,42 @@
+package concurrency
+
+import "github.com/pixality-inc/golang-core/pool"
+
+type Options struct {
+	limit        int
+	poolExecutor pool.PoolExecutor
+}
+
+func NewDefaultOptions() *Options {
+	return &Options{
+		limit:        0,
+		poolExecutor: nil,
+	}
+}
+
+type Option func(options *Options)
+
+// WithLimit bounds the number of functions running at once, zero means unlimited.
+func WithLimit(limit int) Option {
+	return func(options *Options) {
+		options.limit = limit
+	}
+}
+
+// WithPoolExecutor runs functions on the given executor instead of dedicated goroutines,
+// so concurrency is shared with everything else using the same pool.
+func WithPoolExecutor(pe pool.PoolExecutor) Option {
+	return func(options *Options) {
+		options.poolExecutor = pe
+	}
+}
+
+func applyOptions(options ...Option) *Options {
+	opts := NewDefaultOptions()
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
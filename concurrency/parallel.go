@@ -0,0 +1,103 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// ParallelMap applies fn to every item and returns the results in input order.
+// It stops scheduling and cancels running calls on the first error.
+func ParallelMap[T any, R any](
+	ctx context.Context,
+	items []T,
+	fn func(ctx context.Context, item T) (R, error),
+	options ...Option,
+) ([]R, error) {
+	results := make([]R, len(items))
+
+	group, groupCtx := NewGroup(ctx, options...)
+
+	for index, item := range items {
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		group.Go(func(ctx context.Context) error {
+			result, err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+
+			results[index] = result
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ParallelMapKeyed applies fn to every entry of items and returns the results under the same keys.
+func ParallelMapKeyed[K comparable, T any, R any](
+	ctx context.Context,
+	items map[K]T,
+	fn func(ctx context.Context, key K, item T) (R, error),
+	options ...Option,
+) (map[K]R, error) {
+	results := make(map[K]R, len(items))
+	mutex := sync.Mutex{}
+
+	group, groupCtx := NewGroup(ctx, options...)
+
+	for key, item := range items {
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		group.Go(func(ctx context.Context) error {
+			result, err := fn(ctx, key, item)
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			results[key] = result
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ParallelForEach calls fn for every item, stopping on the first error.
+func ParallelForEach[T any](
+	ctx context.Context,
+	items []T,
+	fn func(ctx context.Context, item T) error,
+	options ...Option,
+) error {
+	group, groupCtx := NewGroup(ctx, options...)
+
+	for _, item := range items {
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		group.Go(func(ctx context.Context) error {
+			return fn(ctx, item)
+		})
+	}
+
+	return group.Wait()
+}
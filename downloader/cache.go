@@ -0,0 +1,427 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixality-inc/golang-core/clock"
+	httpClient "github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/json"
+)
+
+const (
+	cacheMetaSuffix = ".meta"
+	cacheBodySuffix = ".body"
+)
+
+var ErrCacheDirRequired = errors.New("cache dir is required")
+
+type CacheConfig interface {
+	Dir() string
+	MaxSize() int64
+}
+
+type CacheConfigYaml struct {
+	DirValue     string `env:"DIR"      yaml:"dir"`
+	MaxSizeValue int64  `env:"MAX_SIZE" yaml:"max_size"`
+}
+
+func (c *CacheConfigYaml) Dir() string {
+	return c.DirValue
+}
+
+// MaxSize is the total size of cached bodies in bytes, zero means unlimited.
+func (c *CacheConfigYaml) MaxSize() int64 {
+	return c.MaxSizeValue
+}
+
+// cacheEntry is the metadata stored next to every cached body.
+type cacheEntry struct {
+	Url          string        `json:"url"`
+	ETag         string        `json:"etag,omitempty"`
+	LastModified string        `json:"last_modified,omitempty"`
+	StoredAt     time.Time     `json:"stored_at"`
+	MaxAge       time.Duration `json:"max_age"`
+	Size         int64         `json:"size"`
+	AccessedAt   time.Time     `json:"accessed_at"`
+}
+
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return e.MaxAge > 0 && now.Before(e.StoredAt.Add(e.MaxAge))
+}
+
+// conditionalHeaders are the validators to send when revalidating the entry.
+func (e *cacheEntry) conditionalHeaders() httpClient.Headers {
+	headers := make(httpClient.Headers)
+
+	if e.ETag != "" {
+		headers["If-None-Match"] = []string{e.ETag}
+	}
+
+	if e.LastModified != "" {
+		headers["If-Modified-Since"] = []string{e.LastModified}
+	}
+
+	return headers
+}
+
+// DiskCache keeps downloaded bodies on disk together with their validators,
+// evicting the least recently used entries once MaxSize is exceeded.
+type DiskCache struct {
+	dir     string
+	maxSize int64
+	clock   clock.Clock
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+}
+
+func NewDiskCache(config CacheConfig) (*DiskCache, error) {
+	if config.Dir() == "" {
+		return nil, ErrCacheDirRequired
+	}
+
+	if err := os.MkdirAll(config.Dir(), 0o750); err != nil { // nolint:mnd
+		return nil, fmt.Errorf("failed to create cache dir %s: %w", config.Dir(), err)
+	}
+
+	cache := &DiskCache{
+		dir:     config.Dir(),
+		maxSize: config.MaxSize(),
+		clock:   clock.Default,
+		mutex:   sync.Mutex{},
+		entries: make(map[string]*cacheEntry),
+		size:    0,
+	}
+
+	if err := cache.loadIndex(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// Size returns the total size of cached bodies.
+func (c *DiskCache) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.size
+}
+
+// Clear removes every cached entry.
+func (c *DiskCache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var errs []error
+
+	for key := range c.entries {
+		errs = append(errs, c.remove(key))
+	}
+
+	return errors.Join(errs...)
+}
+
+// lookup returns a copy of the entry for url and its body, nil when nothing usable is cached.
+// The body is read outside of the lock, the access time only persists with the next store
+// or revalidation of the entry.
+func (c *DiskCache) lookup(url string) (*cacheEntry, []byte) {
+	key := cacheKey(url)
+
+	c.mutex.Lock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		c.mutex.Unlock()
+
+		return nil, nil
+	}
+
+	entry.AccessedAt = c.clock.Now()
+	result := *entry
+
+	c.mutex.Unlock()
+
+	body, err := os.ReadFile(c.path(key, cacheBodySuffix))
+	if err != nil || int64(len(body)) != result.Size {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		// the entry may have been stored again while the body was read
+		if c.entries[key] == entry {
+			_ = c.remove(key)
+		}
+
+		return nil, nil
+	}
+
+	return &result, body
+}
+
+// store saves body under url, or drops the entry when the response can't be reused.
+func (c *DiskCache) store(url string, headers httpClient.Headers, body []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := cacheKey(url)
+	now := c.clock.Now()
+
+	entry := &cacheEntry{
+		Url:          url,
+		ETag:         headerValue(headers, "ETag"),
+		LastModified: headerValue(headers, "Last-Modified"),
+		StoredAt:     now,
+		MaxAge:       0,
+		Size:         int64(len(body)),
+		AccessedAt:   now,
+	}
+
+	maxAge, cacheable := parseCacheControl(headerValue(headers, "Cache-Control"))
+	entry.MaxAge = maxAge
+
+	tooLarge := c.maxSize > 0 && entry.Size > c.maxSize
+	noValidators := entry.ETag == "" && entry.LastModified == "" && entry.MaxAge == 0
+
+	if !cacheable || tooLarge || noValidators {
+		return c.remove(key)
+	}
+
+	if err := writeFileAtomic(c.path(key, cacheBodySuffix), body); err != nil {
+		return err
+	}
+
+	if err := c.writeMeta(key, entry); err != nil {
+		return err
+	}
+
+	if previous, ok := c.entries[key]; ok {
+		c.size -= previous.Size
+	}
+
+	c.entries[key] = entry
+	c.size += entry.Size
+
+	return c.evict(key)
+}
+
+// revalidated refreshes the freshness of an entry after a 304 response.
+func (c *DiskCache) revalidated(url string, headers httpClient.Headers) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := cacheKey(url)
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	entry.StoredAt = c.clock.Now()
+
+	if value := headerValue(headers, "Cache-Control"); value != "" {
+		entry.MaxAge, _ = parseCacheControl(value)
+	}
+
+	if value := headerValue(headers, "ETag"); value != "" {
+		entry.ETag = value
+	}
+
+	if value := headerValue(headers, "Last-Modified"); value != "" {
+		entry.LastModified = value
+	}
+
+	_ = c.writeMeta(key, entry)
+}
+
+// evict drops least recently used entries other than keep until the cache fits.
+func (c *DiskCache) evict(keep string) error {
+	if c.maxSize <= 0 || c.size <= c.maxSize {
+		return nil
+	}
+
+	keys := make([]string, 0, len(c.entries))
+
+	for key := range c.entries {
+		if key != keep {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		return c.entries[a].AccessedAt.Compare(c.entries[b].AccessedAt)
+	})
+
+	var errs []error
+
+	for _, key := range keys {
+		if c.size <= c.maxSize {
+			break
+		}
+
+		errs = append(errs, c.remove(key))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *DiskCache) remove(key string) error {
+	if entry, ok := c.entries[key]; ok {
+		c.size -= entry.Size
+		delete(c.entries, key)
+	}
+
+	var errs []error
+
+	for _, suffix := range []string{cacheMetaSuffix, cacheBodySuffix} {
+		if err := os.Remove(c.path(key, suffix)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *DiskCache) loadIndex() error {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*"+cacheMetaSuffix))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		key := strings.TrimSuffix(filepath.Base(file), cacheMetaSuffix)
+
+		data, err := os.ReadFile(file) // nolint:gosec
+		if err != nil {
+			return fmt.Errorf("failed to read cache entry %s: %w", file, err)
+		}
+
+		var entry cacheEntry
+
+		if err := json.Unmarshal(data, &entry); err != nil || cacheKey(entry.Url) != key {
+			_ = c.remove(key)
+
+			continue
+		}
+
+		c.entries[key] = &entry
+		c.size += entry.Size
+	}
+
+	return c.evict("")
+}
+
+func (c *DiskCache) writeMeta(key string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.path(key, cacheMetaSuffix), data)
+}
+
+func (c *DiskCache) path(key string, suffix string) string {
+	return filepath.Join(c.dir, key+suffix)
+}
+
+func cacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// parseCacheControl returns the max-age and whether the response may be stored at all.
+func parseCacheControl(value string) (time.Duration, bool) {
+	maxAge := time.Duration(0)
+	noCache := false
+
+	for directive := range strings.SplitSeq(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "no-store":
+			return 0, false
+
+		case "no-cache":
+			// stored but revalidated on every use
+			noCache = true
+
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(argument, `"`))
+			if err == nil && seconds > 0 {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	if noCache {
+		return 0, true
+	}
+
+	return maxAge, true
+}
+
+func writeFileAtomic(filename string, data []byte) error {
+	tmp := filename + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o600); err != nil { // nolint:mnd
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, filename); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to rename %s: %w", tmp, err)
+	}
+
+	return nil
+}
+
+func (c *Impl) downloadCached(ctx context.Context, url string) ([]byte, error) {
+	log := c.log.GetLogger(ctx)
+
+	entry, body := c.cache.lookup(url)
+
+	if entry != nil && entry.fresh(c.cache.clock.Now()) {
+		log.Debugf("Serving '%s' from cache", url)
+
+		return body, nil
+	}
+
+	var opts []httpClient.RequestOption
+
+	if entry != nil {
+		opts = append(opts, httpClient.WithHeaders(entry.conditionalHeaders()))
+	}
+
+	response, err := c.httpClient.Get(ctx, url, opts...)
+
+	if entry != nil && err == nil && response.GetStatusCode() == http.StatusNotModified {
+		log.Debugf("'%s' not modified, serving from cache", url)
+
+		c.cache.revalidated(url, response.GetHeaders())
+
+		return body, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.cache.store(url, response.GetHeaders(), response.GetBody()); err != nil {
+		log.WithError(err).Errorf("failed to cache '%s'", url)
+	}
+
+	return response.GetBody(), nil
+}
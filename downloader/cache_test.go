@@ -0,0 +1,262 @@
+package downloader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	httpClient "github.com/pixality-inc/golang-core/http_client"
+)
+
+type manualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *manualClock) Sleep(duration time.Duration) {
+	c.advance(duration)
+}
+
+func (c *manualClock) Since(value time.Time) time.Duration {
+	return c.Now().Sub(value)
+}
+
+func (c *manualClock) After(_ time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+
+	return ch
+}
+
+func (c *manualClock) advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+type cachingServer struct {
+	*httptest.Server
+
+	requests    atomic.Int32
+	notModified atomic.Int32
+}
+
+func newCachingServer(t *testing.T, cacheControl string) *cachingServer {
+	t.Helper()
+
+	server := &cachingServer{}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+
+		etag := `"` + r.URL.Path + `-v1"`
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		if r.Header.Get("If-None-Match") == etag {
+			server.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(strings.Repeat("x", 90) + r.URL.Path))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newCachedTestDownloader(t *testing.T, maxSize int64) (Downloader, *DiskCache, *manualClock) {
+	t.Helper()
+
+	cache, err := NewDiskCache(&CacheConfigYaml{DirValue: t.TempDir(), MaxSizeValue: maxSize})
+	require.NoError(t, err)
+
+	fakeClock := &manualClock{mutex: sync.Mutex{}, now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache.clock = fakeClock
+
+	cfg := &httpClient.ConfigYaml{TimeoutValue: 5 * time.Second, MaxResponseBodySizeValue: 1024 * 1024}
+
+	testDownloader, err := NewCachedDownloader(cfg, cache)
+	require.NoError(t, err)
+
+	return testDownloader, cache, fakeClock
+}
+
+func TestDownloader_Cache_Revalidates(t *testing.T) {
+	t.Parallel()
+
+	server := newCachingServer(t, "")
+	testDownloader, cache, _ := newCachedTestDownloader(t, 0)
+
+	first, err := testDownloader.Download(t.Context(), server.URL+"/a")
+	require.NoError(t, err)
+
+	second, err := testDownloader.Download(t.Context(), server.URL+"/a")
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.Equal(t, int32(2), server.requests.Load())
+	require.Equal(t, int32(1), server.notModified.Load())
+	require.Equal(t, int64(len(first)), cache.Size())
+}
+
+func TestDownloader_Cache_HonoursMaxAge(t *testing.T) {
+	t.Parallel()
+
+	server := newCachingServer(t, "public, max-age=60")
+	testDownloader, _, fakeClock := newCachedTestDownloader(t, 0)
+
+	for range 3 {
+		_, err := testDownloader.Download(t.Context(), server.URL+"/a")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(1), server.requests.Load())
+
+	fakeClock.advance(61 * time.Second)
+
+	_, err := testDownloader.Download(t.Context(), server.URL+"/a")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), server.requests.Load())
+	require.Equal(t, int32(1), server.notModified.Load())
+
+	// the 304 renewed the max-age
+	_, err = testDownloader.Download(t.Context(), server.URL+"/a")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestDownloader_Cache_NoStore(t *testing.T) {
+	t.Parallel()
+
+	server := newCachingServer(t, "no-store")
+	testDownloader, cache, _ := newCachedTestDownloader(t, 0)
+
+	for range 2 {
+		_, err := testDownloader.Download(t.Context(), server.URL+"/a")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(0), server.notModified.Load())
+	require.Equal(t, int64(0), cache.Size())
+}
+
+func TestDownloader_Cache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	server := newCachingServer(t, "")
+	testDownloader, cache, fakeClock := newCachedTestDownloader(t, 250)
+
+	download := func(path string) {
+		fakeClock.advance(time.Second)
+
+		_, err := testDownloader.Download(t.Context(), server.URL+path)
+		require.NoError(t, err)
+	}
+
+	download("/a")
+	download("/b")
+	download("/a")
+	download("/c")
+
+	require.Equal(t, int64(184), cache.Size())
+
+	entry, _ := cache.lookup(server.URL + "/a")
+	require.NotNil(t, entry)
+
+	entry, _ = cache.lookup(server.URL + "/b")
+	require.Nil(t, entry)
+
+	entry, _ = cache.lookup(server.URL + "/c")
+	require.NotNil(t, entry)
+}
+
+func TestDiskCache_ReloadsIndex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cache, err := NewDiskCache(&CacheConfigYaml{DirValue: dir, MaxSizeValue: 0})
+	require.NoError(t, err)
+
+	headers := httpClient.Headers{"ETag": {`"v1"`}}
+	require.NoError(t, cache.store("https://example.com/a", headers, []byte("body")))
+
+	reloaded, err := NewDiskCache(&CacheConfigYaml{DirValue: dir, MaxSizeValue: 0})
+	require.NoError(t, err)
+
+	entry, body := reloaded.lookup("https://example.com/a")
+	require.NotNil(t, entry)
+	require.Equal(t, `"v1"`, entry.ETag)
+	require.Equal(t, []byte("body"), body)
+	require.Equal(t, http.Header{"If-None-Match": {`"v1"`}}, http.Header(entry.conditionalHeaders()))
+
+	require.NoError(t, reloaded.Clear())
+	require.Equal(t, int64(0), reloaded.Size())
+}
+
+func TestDiskCache_DropsTruncatedBody(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewDiskCache(&CacheConfigYaml{DirValue: t.TempDir(), MaxSizeValue: 0})
+	require.NoError(t, err)
+
+	url := "https://example.com/a"
+
+	require.NoError(t, cache.store(url, httpClient.Headers{"ETag": {`"v1"`}}, []byte("body")))
+	require.NoError(t, os.WriteFile(cache.path(cacheKey(url), cacheBodySuffix), []byte("bo"), 0o600))
+
+	entry, body := cache.lookup(url)
+	require.Nil(t, entry)
+	require.Nil(t, body)
+	require.Equal(t, int64(0), cache.Size())
+	require.NoFileExists(t, cache.path(cacheKey(url), cacheMetaSuffix))
+}
+
+func TestParseCacheControl(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value     string
+		maxAge    time.Duration
+		cacheable bool
+	}{
+		{"", 0, true},
+		{"max-age=120", 2 * time.Minute, true},
+		{"public, max-age=\"30\"", 30 * time.Second, true},
+		{"no-cache, max-age=60", 0, true},
+		{"private, no-store", 0, false},
+		{"no-cache, no-store", 0, false},
+		{"max-age=60, no-cache", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		maxAge, cacheable := parseCacheControl(testCase.value)
+
+		require.Equal(t, testCase.maxAge, maxAge, testCase.value)
+		require.Equal(t, testCase.cacheable, cacheable, testCase.value)
+	}
+}
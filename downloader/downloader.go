@@ -6,17 +6,21 @@ import (
 
 	http "github.com/pixality-inc/golang-core/http_client"
 	"github.com/pixality-inc/golang-core/logger"
+	"github.com/pixality-inc/golang-core/storage"
 )
 
 //go:generate mockgen -destination mocks/downloader_gen.go -source downloader.go
 type Downloader interface {
 	Download(ctx context.Context, url string) ([]byte, error)
 	DownloadStream(ctx context.Context, url string) (io.ReadCloser, error)
+	DownloadToFile(ctx context.Context, url string, filename string, options ...Option) (*Result, error)
+	DownloadToStorage(ctx context.Context, url string, store storage.Storage, path string, options ...Option) (*Result, error)
 }
 
 type Impl struct {
 	log        logger.Loggable
 	httpClient http.Client
+	cache      *DiskCache
 }
 
 const defaultMaxResponseBodySize = 1
@@ -44,14 +48,33 @@ func NewDownloader(config http.Config) (Downloader, error) {
 	return &Impl{
 		log:        log,
 		httpClient: httpClient,
+		cache:      nil,
 	}, nil
 }
 
+// NewCachedDownloader returns a downloader whose Download revalidates responses
+// kept in cache with conditional requests instead of fetching full bodies again.
+func NewCachedDownloader(config http.Config, cache *DiskCache) (Downloader, error) {
+	downloader, err := NewDownloader(config)
+	if err != nil {
+		return nil, err
+	}
+
+	impl, _ := downloader.(*Impl)
+	impl.cache = cache
+
+	return impl, nil
+}
+
 func (c *Impl) Download(ctx context.Context, url string) ([]byte, error) {
 	log := c.log.GetLogger(ctx)
 
 	log.Infof("Downloading from '%s'", url)
 
+	if c.cache != nil {
+		return c.downloadCached(ctx, url)
+	}
+
 	response, err := c.httpClient.Get(ctx, url)
 	if err != nil {
 		return nil, err
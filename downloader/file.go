@@ -0,0 +1,282 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pixality-inc/golang-core/concurrency"
+)
+
+// Result describes a finished download.
+type Result struct {
+	Size    int64
+	Sha256  string
+	Resumed bool
+	Chunks  int
+}
+
+const (
+	partSuffix      = ".part"
+	validatorSuffix = ".part.validator"
+	chunksSuffix    = ".chunks"
+
+	// maxRestarts bounds how often a download starts over because the resource changed
+	maxRestarts = 1
+)
+
+// DownloadToFile downloads url into filename. The content goes to a temporary file next
+// to it that is renamed once verified. A sequential download keeps its filename.part when
+// it fails and the next attempt resumes it with a Range request, guarded by If-Range with
+// the validator stored along, large files are fetched in parallel chunks when the server
+// advertises byte ranges, and the result is verified against the expected size and checksum.
+func (c *Impl) DownloadToFile(ctx context.Context, url string, filename string, options ...Option) (*Result, error) {
+	opts := applyOptions(options...)
+	log := c.log.GetLogger(ctx)
+
+	log.Infof("Downloading from '%s' to '%s'", url, filename)
+
+	for restarts := 0; ; restarts++ {
+		result, err := c.downloadToFile(ctx, url, filename, opts)
+		if errors.Is(err, ErrResourceChanged) && restarts < maxRestarts {
+			log.WithError(err).Warnf("Restarting download of '%s'", url)
+
+			continue
+		}
+
+		return result, err
+	}
+}
+
+func (c *Impl) downloadToFile(ctx context.Context, url string, filename string, opts *Options) (*Result, error) {
+	info := c.probe(ctx, url)
+
+	partFilename := filename + partSuffix
+	validatorFilename := filename + validatorSuffix
+	offset := resumableOffset(partFilename, validatorFilename, info)
+
+	var (
+		tempFilename string
+		result       *Result
+		err          error
+	)
+
+	if offset == 0 && opts.parallelism > 1 && info.acceptRanges && info.size > opts.chunkSize {
+		removeFiles(partFilename, validatorFilename)
+
+		tempFilename = filename + chunksSuffix
+		result, err = c.downloadChunks(ctx, url, tempFilename, info, opts)
+	} else {
+		tempFilename = partFilename
+		result, err = c.downloadPart(ctx, url, partFilename, validatorFilename, offset, info, opts)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := fileSha256(tempFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Sha256 = checksum
+
+	if err := verify(opts, result.Size, checksum); err != nil {
+		removeFiles(tempFilename, validatorFilename)
+
+		return nil, err
+	}
+
+	if err := os.Rename(tempFilename, filename); err != nil {
+		return nil, fmt.Errorf("failed to rename %s to %s: %w", tempFilename, filename, err)
+	}
+
+	removeFiles(validatorFilename)
+
+	return result, nil
+}
+
+// resumableOffset is the size of a partial file left by a previous attempt, 0 when it
+// can't be continued: the server must serve ranges of the same content it was started from.
+func resumableOffset(partFilename string, validatorFilename string, info remoteInfo) int64 {
+	stat, err := os.Stat(partFilename)
+	if err != nil {
+		return 0
+	}
+
+	offset := stat.Size()
+
+	if offset == 0 || !info.acceptRanges || info.size < 0 || offset > info.size || info.validator == "" {
+		return 0
+	}
+
+	validator, err := os.ReadFile(validatorFilename) // nolint:gosec
+	if err != nil || string(validator) != info.validator {
+		return 0
+	}
+
+	return offset
+}
+
+func (c *Impl) downloadPart(
+	ctx context.Context,
+	url string,
+	partFilename string,
+	validatorFilename string,
+	offset int64,
+	info remoteInfo,
+	opts *Options,
+) (*Result, error) {
+	if offset == 0 {
+		removeFiles(validatorFilename)
+
+		if info.validator != "" {
+			if err := os.WriteFile(validatorFilename, []byte(info.validator), 0o644); err != nil { // nolint:gosec,mnd
+				return nil, fmt.Errorf("failed to write file %s: %w", validatorFilename, err)
+			}
+		}
+	}
+
+	file, err := os.OpenFile(partFilename, os.O_CREATE|os.O_WRONLY, 0o644) // nolint:gosec,mnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", partFilename, err)
+	}
+
+	result, err := c.writePart(ctx, url, file, offset, info, opts)
+	if closeErr := file.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("failed to close file %s: %w", partFilename, closeErr)
+	}
+
+	// the partial content belongs to an older version, the restart begins from scratch
+	if errors.Is(err, ErrResourceChanged) {
+		removeFiles(partFilename, validatorFilename)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Impl) writePart(ctx context.Context, url string, file *os.File, offset int64, info remoteInfo, opts *Options) (*Result, error) {
+	// the part file is only ever written sequentially, so it has no holes
+	if offset > 0 && offset == info.size {
+		return &Result{Size: offset, Sha256: "", Resumed: true, Chunks: 0}, nil
+	}
+
+	if err := file.Truncate(offset); err != nil {
+		return nil, fmt.Errorf("failed to truncate file %s: %w", file.Name(), err)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek file %s: %w", file.Name(), err)
+	}
+
+	reader := newOpenRangeReader(ctx, c, url, offset, info.lastByte(), info.validator, info.attempts(opts))
+	defer func() { _ = reader.Close() }()
+
+	tracker := newProgressTracker(opts.progress, offset, info.size)
+
+	written, err := io.Copy(&progressWriter{w: file, tracker: tracker}, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	size := offset + written
+
+	if info.size >= 0 && size != info.size {
+		return nil, fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteDownload, size, info.size)
+	}
+
+	return &Result{Size: size, Sha256: "", Resumed: reader.resumed, Chunks: 1}, nil
+}
+
+type chunk struct {
+	start int64
+	end   int64
+}
+
+// downloadChunks writes the chunks into a file that is never resumed, a failed attempt
+// leaves holes, so the file is removed.
+func (c *Impl) downloadChunks(ctx context.Context, url string, filename string, info remoteInfo, opts *Options) (*Result, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644) // nolint:gosec,mnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	result, err := c.writeChunks(ctx, url, file, info, opts)
+	if closeErr := file.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("failed to close file %s: %w", filename, closeErr)
+	}
+
+	if err != nil {
+		removeFiles(filename)
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Impl) writeChunks(ctx context.Context, url string, file *os.File, info remoteInfo, opts *Options) (*Result, error) {
+	if err := file.Truncate(info.size); err != nil {
+		return nil, fmt.Errorf("failed to allocate file %s: %w", file.Name(), err)
+	}
+
+	chunks := make([]chunk, 0, info.size/opts.chunkSize+1)
+
+	for start := int64(0); start < info.size; start += opts.chunkSize {
+		chunks = append(chunks, chunk{start: start, end: min(start+opts.chunkSize, info.size) - 1})
+	}
+
+	c.log.GetLogger(ctx).Debugf("Downloading '%s' in %d chunks", url, len(chunks))
+
+	tracker := newProgressTracker(opts.progress, 0, info.size)
+
+	err := concurrency.ParallelForEach(ctx, chunks, func(ctx context.Context, item chunk) error {
+		reader := newRangeReader(ctx, c, url, item.start, item.end, info.validator, opts.maxAttempts)
+		defer func() { _ = reader.Close() }()
+
+		writer := &progressWriter{w: io.NewOffsetWriter(file, item.start), tracker: tracker}
+
+		if _, err := io.Copy(writer, reader); err != nil {
+			return fmt.Errorf("failed to download bytes %d-%d of %s: %w", item.start, item.end, url, err)
+		}
+
+		return nil
+	}, concurrency.WithLimit(opts.parallelism))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Size: info.size, Sha256: "", Resumed: false, Chunks: len(chunks)}, nil
+}
+
+func removeFiles(filenames ...string) {
+	for _, filename := range filenames {
+		_ = os.Remove(filename)
+	}
+}
+
+func fileSha256(filename string) (string, error) {
+	file, err := os.Open(filename) // nolint:gosec
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash file %s: %w", filename, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,366 @@
+package downloader_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/downloader"
+	"github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/storage"
+	"github.com/pixality-inc/golang-core/storage/providers"
+)
+
+func testContent(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	return content
+}
+
+func checksumOf(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+const testETag = `"v1"`
+
+type rangeServer struct {
+	*httptest.Server
+
+	mutex  sync.Mutex
+	ranges []string
+	gets   atomic.Int32
+}
+
+func newRangeServer(t *testing.T, content []byte, handler func(w http.ResponseWriter, r *http.Request) bool) *rangeServer {
+	t.Helper()
+
+	server := &rangeServer{}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			server.gets.Add(1)
+
+			server.mutex.Lock()
+			server.ranges = append(server.ranges, r.Header.Get("Range"))
+			server.mutex.Unlock()
+		}
+
+		w.Header().Set("ETag", testETag)
+
+		if handler != nil && handler(w, r) {
+			return
+		}
+
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestDownloader(t *testing.T) downloader.Downloader {
+	t.Helper()
+
+	// keep the default body size limit, fasthttp only streams bodies above it
+	cfg := &http_client.ConfigYaml{TimeoutValue: 5 * time.Second}
+
+	testDownloader, err := downloader.NewDownloader(cfg)
+	require.NoError(t, err)
+
+	return testDownloader
+}
+
+func TestDownloader_DownloadToFile(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+	server := newRangeServer(t, content, nil)
+	filename := filepath.Join(t.TempDir(), "file")
+
+	var lastDownloaded, lastTotal int64
+
+	result, err := newTestDownloader(t).DownloadToFile(
+		t.Context(),
+		server.URL,
+		filename,
+		downloader.WithExpectedSize(int64(len(content))),
+		downloader.WithSha256(checksumOf(content)),
+		downloader.WithProgress(func(downloaded int64, total int64) {
+			lastDownloaded, lastTotal = downloaded, total
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(content)), result.Size)
+	require.Equal(t, checksumOf(content), result.Sha256)
+	require.False(t, result.Resumed)
+	require.Equal(t, int64(len(content)), lastDownloaded)
+	require.Equal(t, int64(len(content)), lastTotal)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToFile_ResumesPartialFile(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+	server := newRangeServer(t, content, nil)
+	filename := filepath.Join(t.TempDir(), "file")
+
+	require.NoError(t, os.WriteFile(filename+".part", content[:4_000], 0o600))
+	require.NoError(t, os.WriteFile(filename+".part.validator", []byte(testETag), 0o600))
+
+	result, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename)
+	require.NoError(t, err)
+	require.True(t, result.Resumed)
+	require.Equal(t, []string{"bytes=4000-"}, server.ranges)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	require.NoFileExists(t, filename+".part")
+	require.NoFileExists(t, filename+".part.validator")
+}
+
+func TestDownloader_DownloadToFile_RestartsChangedPartialFile(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+	server := newRangeServer(t, content, nil)
+	filename := filepath.Join(t.TempDir(), "file")
+
+	require.NoError(t, os.WriteFile(filename+".part", bytes.Repeat([]byte{0}, 4_000), 0o600))
+	require.NoError(t, os.WriteFile(filename+".part.validator", []byte(`"v0"`), 0o600))
+
+	result, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename)
+	require.NoError(t, err)
+	require.False(t, result.Resumed)
+	require.Equal(t, []string{""}, server.ranges)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToFile_RestartsWhenIfRangeFails(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+
+	var heads atomic.Int32
+
+	// the resource changes right after the first HEAD, so the resumed GET gets all of it
+	server := newRangeServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodHead && heads.Add(1) == 1 {
+			return false
+		}
+
+		w.Header().Set("ETag", `"v2"`)
+
+		return false
+	})
+
+	filename := filepath.Join(t.TempDir(), "file")
+
+	require.NoError(t, os.WriteFile(filename+".part", bytes.Repeat([]byte{0}, 4_000), 0o600))
+	require.NoError(t, os.WriteFile(filename+".part.validator", []byte(testETag), 0o600))
+
+	result, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename, downloader.WithSha256(checksumOf(content)))
+	require.NoError(t, err)
+	require.False(t, result.Resumed)
+	require.Equal(t, []string{"bytes=4000-", ""}, server.ranges)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToFile_ResumesBrokenConnection(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(1024 * 1024)
+
+	server := newRangeServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodGet || r.Header.Get("Range") != "" {
+			return false
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write(content[:512*1024])
+		w.(http.Flusher).Flush()
+
+		panic(http.ErrAbortHandler)
+	})
+
+	filename := filepath.Join(t.TempDir(), "file")
+
+	result, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename, downloader.WithSha256(checksumOf(content)))
+	require.NoError(t, err)
+	require.True(t, result.Resumed)
+	require.Equal(t, int32(2), server.gets.Load())
+	require.Equal(t, "bytes=524288-", server.ranges[1])
+}
+
+func TestDownloader_DownloadToFile_Parallel(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+	server := newRangeServer(t, content, nil)
+	filename := filepath.Join(t.TempDir(), "file")
+
+	var progressCalls atomic.Int32
+
+	result, err := newTestDownloader(t).DownloadToFile(
+		t.Context(),
+		server.URL,
+		filename,
+		downloader.WithParallelism(4),
+		downloader.WithChunkSize(1_024),
+		downloader.WithSha256(checksumOf(content)),
+		downloader.WithProgress(func(_ int64, _ int64) { progressCalls.Add(1) }),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 10, result.Chunks)
+	require.Equal(t, int32(10), server.gets.Load())
+	require.Positive(t, progressCalls.Load())
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToFile_ParallelFailureLeavesNoFile(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+
+	var failing atomic.Bool
+
+	failing.Store(true)
+
+	server := newRangeServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		if failing.Load() && r.Header.Get("Range") == "bytes=5120-6143" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return true
+		}
+
+		return false
+	})
+
+	filename := filepath.Join(t.TempDir(), "file")
+	options := []downloader.Option{downloader.WithParallelism(4), downloader.WithChunkSize(1_024)}
+
+	_, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename, options...)
+	require.Error(t, err)
+
+	for _, name := range []string{filename, filename + ".part", filename + ".chunks"} {
+		require.NoFileExists(t, name)
+	}
+
+	failing.Store(false)
+
+	result, err := newTestDownloader(t).DownloadToFile(t.Context(), server.URL, filename, options...)
+	require.NoError(t, err)
+	require.False(t, result.Resumed)
+	require.Equal(t, 10, result.Chunks)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToFile_Verification(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(1_000)
+	server := newRangeServer(t, content, nil)
+
+	_, err := newTestDownloader(t).DownloadToFile(
+		t.Context(),
+		server.URL,
+		filepath.Join(t.TempDir(), "file"),
+		downloader.WithSha256(checksumOf([]byte("other"))),
+	)
+	require.ErrorIs(t, err, downloader.ErrChecksumMismatch)
+
+	_, err = newTestDownloader(t).DownloadToFile(
+		t.Context(),
+		server.URL,
+		filepath.Join(t.TempDir(), "file"),
+		downloader.WithExpectedSize(10),
+	)
+	require.ErrorIs(t, err, downloader.ErrSizeMismatch)
+}
+
+func TestDownloader_DownloadToStorage(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(10_000)
+	server := newRangeServer(t, content, nil)
+	store := storage.NewStorage(providers.NewOsProvider(t.TempDir()), nil)
+
+	result, err := newTestDownloader(t).DownloadToStorage(
+		t.Context(),
+		server.URL,
+		store,
+		"dir/file",
+		downloader.WithChunkSize(4_096),
+		downloader.WithSha256(checksumOf(content)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Chunks)
+	require.Equal(t, checksumOf(content), result.Sha256)
+
+	file, err := store.ReadFile(t.Context(), "dir/file")
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestDownloader_DownloadToStorage_AbortsOnChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	content := testContent(1_000)
+	server := newRangeServer(t, content, nil)
+	store := storage.NewStorage(providers.NewOsProvider(t.TempDir()), nil)
+
+	_, err := newTestDownloader(t).DownloadToStorage(
+		t.Context(),
+		server.URL,
+		store,
+		"file",
+		downloader.WithSha256(checksumOf([]byte("other"))),
+	)
+	require.ErrorIs(t, err, downloader.ErrChecksumMismatch)
+
+	exists, err := store.FileExists(t.Context(), "file")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
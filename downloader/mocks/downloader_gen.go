@@ -14,6 +14,8 @@ import (
 	io "io"
 	reflect "reflect"
 
+	downloader "github.com/pixality-inc/golang-core/downloader"
+	storage "github.com/pixality-inc/golang-core/storage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -70,3 +72,43 @@ func (mr *MockDownloaderMockRecorder) DownloadStream(ctx, url any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadStream", reflect.TypeOf((*MockDownloader)(nil).DownloadStream), ctx, url)
 }
+
+// DownloadToFile mocks base method.
+func (m *MockDownloader) DownloadToFile(ctx context.Context, url, filename string, options ...downloader.Option) (*downloader.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, url, filename}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadToFile", varargs...)
+	ret0, _ := ret[0].(*downloader.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadToFile indicates an expected call of DownloadToFile.
+func (mr *MockDownloaderMockRecorder) DownloadToFile(ctx, url, filename any, options ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, url, filename}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadToFile", reflect.TypeOf((*MockDownloader)(nil).DownloadToFile), varargs...)
+}
+
+// DownloadToStorage mocks base method.
+func (m *MockDownloader) DownloadToStorage(ctx context.Context, url string, store storage.Storage, path string, options ...downloader.Option) (*downloader.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, url, store, path}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadToStorage", varargs...)
+	ret0, _ := ret[0].(*downloader.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadToStorage indicates an expected call of DownloadToStorage.
+func (mr *MockDownloaderMockRecorder) DownloadToStorage(ctx, url, store, path any, options ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, url, store, path}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadToStorage", reflect.TypeOf((*MockDownloader)(nil).DownloadToStorage), varargs...)
+}
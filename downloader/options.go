@@ -0,0 +1,95 @@
+package downloader
+
+const (
+	defaultChunkSize   = 8 * 1024 * 1024
+	defaultMaxAttempts = 3
+)
+
+// ProgressFunc receives the number of downloaded bytes and the total size, total is -1 when unknown.
+type ProgressFunc func(downloaded int64, total int64)
+
+type Options struct {
+	expectedSize int64
+	sha256       string
+	progress     ProgressFunc
+	parallelism  int
+	chunkSize    int64
+	maxAttempts  int
+}
+
+func NewDefaultOptions() *Options {
+	return &Options{
+		expectedSize: -1,
+		sha256:       "",
+		progress:     nil,
+		parallelism:  1,
+		chunkSize:    defaultChunkSize,
+		maxAttempts:  defaultMaxAttempts,
+	}
+}
+
+type Option func(options *Options)
+
+// WithExpectedSize fails the download when the resulting size differs from size.
+func WithExpectedSize(size int64) Option {
+	return func(options *Options) {
+		options.expectedSize = size
+	}
+}
+
+// WithSha256 fails the download when the hex encoded SHA-256 of the content differs from checksum.
+func WithSha256(checksum string) Option {
+	return func(options *Options) {
+		options.sha256 = checksum
+	}
+}
+
+// WithProgress reports progress after every written block, calls are serialized.
+func WithProgress(fn ProgressFunc) Option {
+	return func(options *Options) {
+		options.progress = fn
+	}
+}
+
+// WithParallelism downloads files in up to n ranged chunks at once when the server supports it.
+func WithParallelism(n int) Option {
+	return func(options *Options) {
+		options.parallelism = n
+	}
+}
+
+// WithChunkSize sets the size of parallel chunks and of multipart upload parts.
+func WithChunkSize(size int64) Option {
+	return func(options *Options) {
+		options.chunkSize = size
+	}
+}
+
+// WithMaxAttempts sets how many times a broken transfer is resumed before giving up.
+func WithMaxAttempts(attempts int) Option {
+	return func(options *Options) {
+		options.maxAttempts = attempts
+	}
+}
+
+func applyOptions(options ...Option) *Options {
+	opts := NewDefaultOptions()
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	if opts.parallelism < 1 {
+		opts.parallelism = 1
+	}
+
+	if opts.chunkSize <= 0 {
+		opts.chunkSize = defaultChunkSize
+	}
+
+	if opts.maxAttempts < 1 {
+		opts.maxAttempts = 1
+	}
+
+	return opts
+}
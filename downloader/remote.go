@@ -0,0 +1,291 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	httpClient "github.com/pixality-inc/golang-core/http_client"
+)
+
+var (
+	ErrSizeMismatch       = errors.New("downloaded size mismatch")
+	ErrChecksumMismatch   = errors.New("downloaded checksum mismatch")
+	ErrRangeNotSupported  = errors.New("server ignored range request")
+	ErrIncompleteDownload = errors.New("incomplete download")
+	ErrResourceChanged    = errors.New("resource changed during download")
+)
+
+// remoteInfo is what a HEAD request tells about the resource, size is -1 when unknown.
+// validator is the strong ETag or else the Last-Modified date, empty when there is none.
+type remoteInfo struct {
+	size         int64
+	acceptRanges bool
+	validator    string
+}
+
+func (c *Impl) probe(ctx context.Context, url string) remoteInfo {
+	info := remoteInfo{size: -1, acceptRanges: false, validator: ""}
+
+	response, err := c.httpClient.Head(ctx, url)
+	if err != nil {
+		c.log.GetLogger(ctx).WithError(err).Debugf("HEAD '%s' failed, downloading without ranges", url)
+
+		return info
+	}
+
+	headers := response.GetHeaders()
+
+	if value := headerValue(headers, "Content-Length"); value != "" {
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil && size >= 0 {
+			info.size = size
+		}
+	}
+
+	info.acceptRanges = strings.EqualFold(headerValue(headers, "Accept-Ranges"), "bytes")
+
+	// If-Range only accepts strong entity tags
+	if etag := headerValue(headers, "ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		info.validator = etag
+	} else {
+		info.validator = headerValue(headers, "Last-Modified")
+	}
+
+	return info
+}
+
+// attempts limits retries to one when a broken transfer could not be continued anyway.
+func (i remoteInfo) attempts(opts *Options) int {
+	if !i.acceptRanges {
+		return 1
+	}
+
+	return opts.maxAttempts
+}
+
+// lastByte is the end of an open range over the whole resource, -1 when the size is unknown.
+func (i remoteInfo) lastByte() int64 {
+	if i.size <= 0 {
+		return -1
+	}
+
+	return i.size - 1
+}
+
+func headerValue(headers httpClient.Headers, name string) string {
+	for key, values := range headers {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// rangeReader streams [offset, end] of a resource and transparently re-requests
+// the remainder with a Range header when the connection breaks. Open readers ask for
+// everything from offset and only use end, -1 when unknown, to detect truncated bodies.
+// Ranged requests carry ifRange, when set, so a changed resource is never spliced.
+type rangeReader struct {
+	ctx         context.Context // nolint:containedctx
+	client      *Impl
+	url         string
+	offset      int64
+	end         int64
+	ifRange     string
+	open        bool
+	maxAttempts int
+	attempts    int
+	resumed     bool
+	body        io.ReadCloser
+}
+
+func newRangeReader(ctx context.Context, client *Impl, url string, offset, end int64, ifRange string, maxAttempts int) *rangeReader {
+	return &rangeReader{
+		ctx:         ctx,
+		client:      client,
+		url:         url,
+		offset:      offset,
+		end:         end,
+		ifRange:     ifRange,
+		open:        false,
+		maxAttempts: maxAttempts,
+		attempts:    0,
+		resumed:     offset > 0,
+		body:        nil,
+	}
+}
+
+func newOpenRangeReader(ctx context.Context, client *Impl, url string, offset, end int64, ifRange string, maxAttempts int) *rangeReader {
+	reader := newRangeReader(ctx, client, url, offset, end, ifRange, maxAttempts)
+	reader.open = true
+
+	return reader
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	for {
+		if r.end >= 0 && r.offset > r.end {
+			return 0, io.EOF
+		}
+
+		n, err := r.read(p)
+		if err == nil || n > 0 || errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		if !r.canResume(err) {
+			return 0, err
+		}
+
+		r.client.log.GetLogger(r.ctx).WithError(err).Warnf("Download of '%s' interrupted at byte %d, resuming", r.url, r.offset)
+
+		r.resumed = true
+	}
+}
+
+func (r *rangeReader) read(p []byte) (int, error) {
+	if r.body == nil {
+		if err := r.request(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+
+	if err == nil || n > 0 {
+		return n, nil
+	}
+
+	_ = r.body.Close()
+	r.body = nil
+
+	if errors.Is(err, io.EOF) && r.end >= 0 && r.offset <= r.end {
+		return 0, fmt.Errorf("%w: stream ended at byte %d, expected %d", ErrIncompleteDownload, r.offset, r.end+1)
+	}
+
+	return 0, err
+}
+
+// canResume reports whether err is a transport failure worth another ranged request,
+// http status errors are left to the retry policy of the http client.
+func (r *rangeReader) canResume(err error) bool {
+	if r.ctx.Err() != nil || r.attempts >= r.maxAttempts {
+		return false
+	}
+
+	return !errors.Is(err, ErrRangeNotSupported) &&
+		!errors.Is(err, ErrResourceChanged) &&
+		!errors.Is(err, httpClient.ErrNon200HttpCode) &&
+		!errors.Is(err, httpClient.ErrNotFound) &&
+		!errors.Is(err, httpClient.ErrBadRequest)
+}
+
+func (r *rangeReader) request() error {
+	r.attempts++
+
+	var opts []httpClient.RequestOption
+
+	ranged := r.offset > 0 || !r.open
+	if ranged {
+		rangeValue := fmt.Sprintf("bytes=%d-", r.offset)
+		if !r.open {
+			rangeValue = fmt.Sprintf("bytes=%d-%d", r.offset, r.end)
+		}
+
+		opts = append(opts, httpClient.WithHeader("Range", rangeValue))
+
+		if r.ifRange != "" {
+			opts = append(opts, httpClient.WithHeader("If-Range", r.ifRange))
+		}
+	}
+
+	response, err := r.client.httpClient.GetStream(r.ctx, r.url, opts...)
+	if err != nil {
+		return err
+	}
+
+	if ranged && response.GetStatusCode() != http.StatusPartialContent {
+		_ = response.GetBody().Close()
+
+		// the server answers If-Range with the whole resource when it no longer matches
+		if r.ifRange != "" && response.GetStatusCode() == http.StatusOK {
+			return fmt.Errorf("%w: %s no longer matches %s", ErrResourceChanged, r.url, r.ifRange)
+		}
+
+		return fmt.Errorf("%w: got status %d", ErrRangeNotSupported, response.GetStatusCode())
+	}
+
+	r.body = response.GetBody()
+
+	return nil
+}
+
+func (r *rangeReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	err := r.body.Close()
+	r.body = nil
+
+	return err
+}
+
+type progressTracker struct {
+	mutex      sync.Mutex
+	fn         ProgressFunc
+	downloaded int64
+	total      int64
+}
+
+func newProgressTracker(fn ProgressFunc, downloaded int64, total int64) *progressTracker {
+	return &progressTracker{
+		mutex:      sync.Mutex{},
+		fn:         fn,
+		downloaded: downloaded,
+		total:      total,
+	}
+}
+
+func (t *progressTracker) add(n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.downloaded += n
+
+	if t.fn != nil {
+		t.fn(t.downloaded, t.total)
+	}
+}
+
+// progressWriter forwards writes to w and reports every written block.
+type progressWriter struct {
+	w       io.Writer
+	tracker *progressTracker
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.tracker.add(int64(n))
+
+	return n, err
+}
+
+func verify(opts *Options, size int64, checksum string) error {
+	if opts.expectedSize >= 0 && size != opts.expectedSize {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrSizeMismatch, opts.expectedSize, size)
+	}
+
+	if opts.sha256 != "" && !strings.EqualFold(opts.sha256, checksum) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, opts.sha256, checksum)
+	}
+
+	return nil
+}
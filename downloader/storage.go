@@ -0,0 +1,103 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pixality-inc/golang-core/storage"
+)
+
+// DownloadToStorage streams url into path of the given storage using a multipart upload,
+// one part per chunk, so the content never has to be kept on local disk. Broken transfers
+// are resumed with Range requests and the upload is aborted when verification fails.
+func (c *Impl) DownloadToStorage(ctx context.Context, url string, store storage.Storage, path string, options ...Option) (*Result, error) {
+	opts := applyOptions(options...)
+	log := c.log.GetLogger(ctx)
+
+	log.Infof("Downloading from '%s' to storage path '%s'", url, path)
+
+	info := c.probe(ctx, url)
+
+	reader := newOpenRangeReader(ctx, c, url, 0, info.lastByte(), info.validator, info.attempts(opts))
+	defer func() { _ = reader.Close() }()
+
+	upload, err := store.CreateMultipartUpload(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.uploadParts(ctx, reader, store, path, upload, info, opts)
+	if err != nil {
+		if abortErr := store.AbortMultipartUpload(context.WithoutCancel(ctx), path, upload); abortErr != nil {
+			log.WithError(abortErr).Errorf("failed to abort multipart upload of '%s'", path)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Impl) uploadParts(
+	ctx context.Context,
+	reader *rangeReader,
+	store storage.Storage,
+	path string,
+	upload storage.MultipartUpload,
+	info remoteInfo,
+	opts *Options,
+) (*Result, error) {
+	hash := sha256.New()
+	tracker := newProgressTracker(opts.progress, 0, info.size)
+	buffer := make([]byte, opts.chunkSize)
+	chunks := make([]storage.MultipartChunk, 0)
+	size := int64(0)
+
+	for {
+		n, readErr := io.ReadFull(reader, buffer)
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("failed to download %s: %w", reader.url, readErr)
+		}
+
+		// an empty body still needs a single empty part to produce the object
+		if n > 0 || len(chunks) == 0 {
+			part := buffer[:n]
+
+			hash.Write(part)
+
+			uploaded, err := store.UploadMultipartChunk(ctx, path, upload, len(chunks)+1, bytes.NewReader(part), int64(n))
+			if err != nil {
+				return nil, err
+			}
+
+			chunks = append(chunks, uploaded)
+			size += int64(n)
+			tracker.add(int64(n))
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	if info.size >= 0 && size != info.size {
+		return nil, fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteDownload, size, info.size)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	if err := verify(opts, size, checksum); err != nil {
+		return nil, err
+	}
+
+	if err := store.CompleteMultipartUpload(ctx, path, upload, chunks); err != nil {
+		return nil, err
+	}
+
+	return &Result{Size: size, Sha256: checksum, Resumed: reader.resumed, Chunks: len(chunks)}, nil
+}
@@ -0,0 +1,174 @@
+package encoder
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pixality-inc/golang-core/errors"
+)
+
+const (
+	aesGcmVersion  byte = 1
+	aesGcmKeySize       = 32
+	maxKeyIdLength      = 255
+)
+
+// aesGcmMagic starts every AES-GCM value, so legacy XOR values can't pass for one
+// the way they could with the single version byte.
+var aesGcmMagic = []byte{0xae, 0x5c, 0x9c, 0xe1}
+
+var (
+	ErrInvalidKey        = errors.New("encoder.invalid_key", "invalid encryption key")
+	ErrNoPrimaryKey      = errors.New("encoder.no_primary_key", "primary key not found")
+	ErrUnknownKey        = errors.New("encoder.unknown_key", "unknown key id")
+	ErrInvalidCiphertext = errors.New("encoder.invalid_ciphertext", "invalid ciphertext")
+	ErrDecrypt           = errors.New("encoder.decrypt", "decrypt error")
+)
+
+// AesGcmImpl encrypts with AES-256-GCM using a random nonce per message.
+// The output layout is: magic (4 bytes) | version (1 byte) | key id length (1 byte) | key id | nonce |
+// ciphertext with tag. The header is authenticated as additional data, so it can't be swapped.
+// All keys of the keyring can decrypt, only the primary one encrypts, which allows key rotation.
+type AesGcmImpl struct {
+	primaryKeyId string
+	aeads        map[string]cipher.AEAD
+}
+
+// NewAesGcm creates an encoder from 32-byte keys indexed by key id.
+func NewAesGcm(primaryKeyId string, keys map[string][]byte) (*AesGcmImpl, error) {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+
+	for keyId, key := range keys {
+		if keyId == "" || len(keyId) > maxKeyIdLength {
+			return nil, fmt.Errorf("%w: key id must be 1..%d bytes", ErrInvalidKey, maxKeyIdLength)
+		}
+
+		if len(key) != aesGcmKeySize {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes", ErrInvalidKey, keyId, aesGcmKeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidKey, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidKey, err)
+		}
+
+		aeads[keyId] = aead
+	}
+
+	if _, ok := aeads[primaryKeyId]; !ok {
+		return nil, ErrNoPrimaryKey
+	}
+
+	return &AesGcmImpl{
+		primaryKeyId: primaryKeyId,
+		aeads:        aeads,
+	}, nil
+}
+
+func (e *AesGcmImpl) PrimaryKeyId() string {
+	return e.primaryKeyId
+}
+
+func (e *AesGcmImpl) Encode(data []byte) []byte {
+	aead := e.aeads[e.primaryKeyId]
+	header := aesGcmHeader(e.primaryKeyId)
+
+	nonce := make([]byte, aead.NonceSize())
+
+	// crypto/rand.Read never returns an error
+	rand.Read(nonce) //nolint:errcheck,gosec
+
+	result := make([]byte, 0, len(header)+len(nonce)+len(data)+aead.Overhead())
+	result = append(result, header...)
+	result = append(result, nonce...)
+
+	return aead.Seal(result, nonce, data, header)
+}
+
+func (e *AesGcmImpl) Decode(data []byte) ([]byte, error) {
+	keyId, err := ParseKeyId(data)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, ok := e.aeads[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyId)
+	}
+
+	header := data[:aesGcmHeaderLength(keyId)]
+	payload := data[len(header):]
+
+	if len(payload) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce := payload[:aead.NonceSize()]
+
+	plaintext, err := aead.Open(nil, nonce, payload[aead.NonceSize():], header)
+	if err != nil {
+		return nil, errors.Join(ErrDecrypt, err)
+	}
+
+	return plaintext, nil
+}
+
+// EncodeString encrypts data and returns it as unpadded URL-safe base64.
+func (e *AesGcmImpl) EncodeString(data string) string {
+	return base64.RawURLEncoding.EncodeToString(e.Encode([]byte(data)))
+}
+
+func (e *AesGcmImpl) DecodeString(data string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return "", errors.Join(ErrBase64Decode, err)
+	}
+
+	plaintext, err := e.Decode(decoded)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// ParseKeyId returns the key id from the header of an AES-GCM ciphertext.
+func ParseKeyId(data []byte) (string, error) {
+	prefixLength := len(aesGcmMagic) + 2
+
+	if len(data) < prefixLength || !bytes.HasPrefix(data, aesGcmMagic) || data[len(aesGcmMagic)] != aesGcmVersion {
+		return "", ErrInvalidCiphertext
+	}
+
+	keyIdLength := int(data[prefixLength-1])
+
+	if keyIdLength == 0 || len(data) < prefixLength+keyIdLength {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(data[prefixLength : prefixLength+keyIdLength]), nil
+}
+
+func aesGcmHeaderLength(keyId string) int {
+	return len(aesGcmMagic) + 2 + len(keyId)
+}
+
+func aesGcmHeader(keyId string) []byte {
+	header := bytes.NewBuffer(make([]byte, 0, aesGcmHeaderLength(keyId)))
+
+	header.Write(aesGcmMagic)
+	header.WriteByte(aesGcmVersion)
+	header.WriteByte(byte(len(keyId)))
+	header.WriteString(keyId)
+
+	return header.Bytes()
+}
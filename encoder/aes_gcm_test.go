@@ -0,0 +1,229 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ Encoder = (*Impl)(nil)
+	_ Encoder = (*AesGcmImpl)(nil)
+	_ Encoder = (*MigratingImpl)(nil)
+)
+
+var (
+	testKeyV1 = bytes.Repeat([]byte{1}, 32)
+	testKeyV2 = bytes.Repeat([]byte{2}, 32)
+)
+
+func newTestAesGcm(t *testing.T, primaryKeyId string, keys map[string][]byte) *AesGcmImpl {
+	t.Helper()
+
+	encoder, err := NewAesGcm(primaryKeyId, keys)
+	require.NoError(t, err)
+
+	return encoder
+}
+
+func TestAesGcmRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	encoder := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+
+	encoded := encoder.EncodeString("Привет ❤️")
+	require.NotContains(t, encoded, "+")
+	require.NotContains(t, encoded, "/")
+	require.NotContains(t, encoded, "=")
+
+	decoded, err := encoder.DecodeString(encoded)
+	require.NoError(t, err)
+	require.Equal(t, "Привет ❤️", decoded)
+
+	raw, err := encoder.Decode(encoder.Encode([]byte("Hello")))
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello"), raw)
+}
+
+func TestAesGcmRandomNonce(t *testing.T) {
+	t.Parallel()
+
+	encoder := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+
+	require.NotEqual(t, encoder.EncodeString("Hello"), encoder.EncodeString("Hello"))
+}
+
+func TestAesGcmKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	oldEncoder := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+	newEncoder := newTestAesGcm(t, "v2", map[string][]byte{"v1": testKeyV1, "v2": testKeyV2})
+
+	oldEncoded := oldEncoder.EncodeString("Hello")
+
+	decoded, err := newEncoder.DecodeString(oldEncoded)
+	require.NoError(t, err)
+	require.Equal(t, "Hello", decoded)
+
+	newEncoded := newEncoder.Encode([]byte("Hello"))
+
+	keyId, err := ParseKeyId(newEncoded)
+	require.NoError(t, err)
+	require.Equal(t, "v2", keyId)
+
+	_, err = oldEncoder.Decode(newEncoded)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestAesGcmTampered(t *testing.T) {
+	t.Parallel()
+
+	encoder := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+
+	encoded := encoder.Encode([]byte("Hello"))
+	encoded[len(encoded)-1] ^= 0xff
+
+	_, err := encoder.Decode(encoded)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = encoder.Decode(append(aesGcmHeader("v1"), 0))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = encoder.Decode([]byte("garbage"))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = encoder.DecodeString("!!!")
+	require.ErrorIs(t, err, ErrBase64Decode)
+}
+
+func TestAesGcmHeaderIsAuthenticated(t *testing.T) {
+	t.Parallel()
+
+	encoder := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1, "v2": testKeyV1})
+
+	encoded := encoder.Encode([]byte("Hello"))
+	encoded[aesGcmHeaderLength("v1")-1] = '2'
+
+	_, err := encoder.Decode(encoded)
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestNewAesGcmErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAesGcm("v1", map[string][]byte{"v1": []byte("short")})
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewAesGcm("", map[string][]byte{"": testKeyV1})
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewAesGcm("v2", map[string][]byte{"v1": testKeyV1})
+	require.ErrorIs(t, err, ErrNoPrimaryKey)
+}
+
+func TestMigratingDecodesLegacyAndCurrent(t *testing.T) {
+	t.Parallel()
+
+	legacy := New([]byte("iddqd"))
+	current := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+	migrating := NewMigrating(current, legacy)
+
+	decoded, err := migrating.DecodeString(legacy.EncodeString("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", decoded)
+
+	decoded, err = migrating.DecodeString(migrating.EncodeString("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", decoded)
+
+	raw, err := migrating.Decode(legacy.Encode([]byte("Hello")))
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello"), raw)
+}
+
+func TestMigrateString(t *testing.T) {
+	t.Parallel()
+
+	legacy := New([]byte("iddqd"))
+	current := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+	migrating := NewMigrating(current, legacy)
+
+	migrated, wasLegacy, err := migrating.MigrateString(legacy.EncodeString("Hello"))
+	require.NoError(t, err)
+	require.True(t, wasLegacy)
+
+	decoded, err := current.DecodeString(migrated)
+	require.NoError(t, err)
+	require.Equal(t, "Hello", decoded)
+
+	unchanged, wasLegacy, err := migrating.MigrateString(migrated)
+	require.NoError(t, err)
+	require.False(t, wasLegacy)
+	require.Equal(t, migrated, unchanged)
+
+	_, _, err = migrating.MigrateString("%%%")
+	require.ErrorIs(t, err, ErrBase64Decode)
+}
+
+func TestMigratingRejectsTamperedCurrent(t *testing.T) {
+	t.Parallel()
+
+	legacy := New([]byte("iddqd"))
+	current := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+	migrating := NewMigrating(current, legacy)
+
+	tampered := current.Encode([]byte("Hello"))
+	tampered[len(tampered)-1] ^= 1
+
+	_, err := migrating.Decode(tampered)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	tamperedString := base64.RawURLEncoding.EncodeToString(tampered)
+
+	_, err = migrating.DecodeString(tamperedString)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, _, err = migrating.MigrateString(tamperedString)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	other := newTestAesGcm(t, "v2", map[string][]byte{"v2": testKeyV2})
+
+	_, err = migrating.DecodeString(other.EncodeString("Hello"))
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, _, err = migrating.MigrateString(other.EncodeString("Hello"))
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestMigratingDecodesLegacyWithVersionByte(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("iddqd")
+	legacy := New(key)
+	current := newTestAesGcm(t, "v1", map[string][]byte{"v1": testKeyV1})
+	migrating := NewMigrating(current, legacy)
+
+	// the legacy value starts like the old single byte AES-GCM header: version 1, key id "v1"
+	plaintext := []byte{aesGcmVersion ^ key[0], 2 ^ key[1], 'v' ^ key[2], '1' ^ key[3], 'x', 'y', 'z'}
+
+	encoded := legacy.Encode(plaintext)
+	require.Equal(t, aesGcmVersion, encoded[0])
+
+	decoded, err := migrating.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decoded)
+
+	decodedString, err := migrating.DecodeString(legacy.EncodeString(string(plaintext)))
+	require.NoError(t, err)
+	require.Equal(t, string(plaintext), decodedString)
+
+	migrated, wasLegacy, err := migrating.MigrateString(legacy.EncodeString(string(plaintext)))
+	require.NoError(t, err)
+	require.True(t, wasLegacy)
+
+	decodedString, err = current.DecodeString(migrated)
+	require.NoError(t, err)
+	require.Equal(t, string(plaintext), decodedString)
+}
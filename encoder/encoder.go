@@ -12,7 +12,7 @@ type Encoder interface {
 	Encode(data []byte) []byte
 	Decode(data []byte) ([]byte, error)
 	EncodeString(data string) string
-	DecodeString(data string) ([]byte, error)
+	DecodeString(data string) (string, error)
 }
 
 type Impl struct {
@@ -0,0 +1,73 @@
+package encoder
+
+import (
+	"github.com/pixality-inc/golang-core/errors"
+)
+
+// MigratingImpl encodes with the current encoder and decodes values produced by either
+// the current or the legacy XOR encoder, so stored legacy values keep working during migration.
+// Only values without the AES-GCM magic header fall back to the legacy encoder, values failing
+// authentication or encrypted with an unknown key are errors.
+type MigratingImpl struct {
+	current *AesGcmImpl
+	legacy  *Impl
+}
+
+func NewMigrating(current *AesGcmImpl, legacy *Impl) *MigratingImpl {
+	return &MigratingImpl{
+		current: current,
+		legacy:  legacy,
+	}
+}
+
+func (e *MigratingImpl) Encode(data []byte) []byte {
+	return e.current.Encode(data)
+}
+
+func (e *MigratingImpl) Decode(data []byte) ([]byte, error) {
+	decoded, err := e.current.Decode(data)
+	if !isLegacyValue(err) {
+		return decoded, err
+	}
+
+	return e.legacy.Decode(data)
+}
+
+func (e *MigratingImpl) EncodeString(data string) string {
+	return e.current.EncodeString(data)
+}
+
+func (e *MigratingImpl) DecodeString(data string) (string, error) {
+	decoded, err := e.current.DecodeString(data)
+	if !isLegacyValue(err) {
+		return decoded, err
+	}
+
+	return e.legacy.DecodeString(data)
+}
+
+// MigrateString re-encodes a value with the current encoder.
+// It reports whether the value was a legacy one, current values are returned unchanged.
+func (e *MigratingImpl) MigrateString(data string) (string, bool, error) {
+	_, err := e.current.DecodeString(data)
+
+	switch {
+	case err == nil:
+		return data, false, nil
+	case !isLegacyValue(err):
+		return "", false, err
+	}
+
+	decoded, err := e.legacy.DecodeString(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	return e.current.EncodeString(decoded), true, nil
+}
+
+// isLegacyValue tells whether the current encoder failed because the value is not an
+// AES-GCM one at all.
+func isLegacyValue(err error) bool {
+	return errors.Is(err, ErrInvalidCiphertext) || errors.Is(err, ErrBase64Decode)
+}
@@ -0,0 +1,165 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testMasterKeyV1 = bytes.Repeat([]byte{1}, KeySize)
+	testMasterKeyV2 = bytes.Repeat([]byte{2}, KeySize)
+)
+
+func newTestEnvelope(t *testing.T, primaryKeyId string) *Envelope {
+	t.Helper()
+
+	provider, err := NewStaticKeyProvider(primaryKeyId, map[string][]byte{
+		"v1": testMasterKeyV1,
+		"v2": testMasterKeyV2,
+	})
+	require.NoError(t, err)
+
+	return NewEnvelope(provider)
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t, "v1")
+
+	ciphertext, err := envelope.Encrypt(t.Context(), []byte("+1 555 0100"), []byte("users.phone"))
+	require.NoError(t, err)
+	require.NotContains(t, string(ciphertext), "555")
+
+	plaintext, err := envelope.Decrypt(t.Context(), ciphertext, []byte("users.phone"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("+1 555 0100"), plaintext)
+
+	_, err = envelope.Decrypt(t.Context(), ciphertext, []byte("users.email"))
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestEnvelopeRandomizedIsNotDeterministic(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t, "v1")
+
+	first, err := envelope.Encrypt(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	second, err := envelope.Encrypt(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	require.NotEqual(t, first, second)
+}
+
+func TestEnvelopeDeterministic(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t, "v1")
+
+	first, err := envelope.EncryptDeterministic(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	second, err := envelope.EncryptDeterministic(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	other, err := envelope.EncryptDeterministic(t.Context(), []byte("other"), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.NotEqual(t, first, other)
+
+	plaintext, err := envelope.Decrypt(t.Context(), first, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), plaintext)
+}
+
+func TestEnvelopeKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	oldEnvelope := newTestEnvelope(t, "v1")
+	newEnvelope := newTestEnvelope(t, "v2")
+
+	ciphertext, err := oldEnvelope.Encrypt(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	plaintext, err := newEnvelope.Decrypt(t.Context(), ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), plaintext)
+
+	provider, err := NewStaticKeyProvider("v2", map[string][]byte{"v2": testMasterKeyV2})
+	require.NoError(t, err)
+
+	_, err = NewEnvelope(provider).Decrypt(t.Context(), ciphertext, nil)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestEnvelopeInvalidCiphertext(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t, "v1")
+
+	for _, data := range [][]byte{
+		nil,
+		[]byte("garbage"),
+		{envelopeVersion, byte(ModeRandomized), 2, 'v', '1'},
+		{envelopeVersion, byte(ModeRandomized), 2, 'v', '1', 0, 200, 1},
+		{envelopeVersion, 9, 2, 'v', '1', 0},
+	} {
+		_, err := envelope.Decrypt(t.Context(), data, nil)
+		require.ErrorIs(t, err, ErrInvalidCiphertext)
+	}
+
+	ciphertext, err := envelope.Encrypt(t.Context(), []byte("value"), nil)
+	require.NoError(t, err)
+
+	ciphertext[len(ciphertext)-1] ^= 0xff
+
+	_, err = envelope.Decrypt(t.Context(), ciphertext, nil)
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestNewStaticKeyProviderErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewStaticKeyProvider("v1", map[string][]byte{"v1": []byte("short")})
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewStaticKeyProvider("v2", map[string][]byte{"v1": testMasterKeyV1})
+	require.ErrorIs(t, err, ErrNoPrimaryKey)
+}
+
+func TestNewFileKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "master.key")
+
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(testMasterKeyV1)+"\n"), 0o600))
+
+	provider, err := NewFileKeyProvider("v1", map[string]string{"v1": path})
+	require.NoError(t, err)
+
+	key, err := provider.Key(t.Context(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, testMasterKeyV1, key)
+
+	_, err = NewFileKeyProvider("v1", map[string]string{"v1": filepath.Join(t.TempDir(), "missing")})
+	require.ErrorIs(t, err, ErrReadKey)
+}
+
+func TestNewEnvKeyProvider(t *testing.T) {
+	t.Setenv("GOLANG_CORE_TEST_MASTER_KEY", base64.StdEncoding.EncodeToString(testMasterKeyV2))
+
+	provider, err := NewEnvKeyProvider("v2", map[string]string{"v2": "GOLANG_CORE_TEST_MASTER_KEY"})
+	require.NoError(t, err)
+	require.Equal(t, "v2", provider.PrimaryKeyId())
+
+	_, err = NewEnvKeyProvider("v2", map[string]string{"v2": "GOLANG_CORE_TEST_MISSING_KEY"})
+	require.ErrorIs(t, err, ErrReadKey)
+}
@@ -0,0 +1,245 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/pixality-inc/golang-core/errors"
+)
+
+type Mode byte
+
+const (
+	ModeRandomized    Mode = 1
+	ModeDeterministic Mode = 2
+)
+
+const (
+	envelopeVersion byte = 1
+	maxKeyIdLength       = 255
+	nonceSize            = 12
+
+	deterministicEncryptionInfo = "golang-core/encryption/deterministic/encryption"
+	deterministicMacInfo        = "golang-core/encryption/deterministic/mac"
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("encryption.invalid_ciphertext", "invalid ciphertext")
+	ErrDecrypt           = errors.New("encryption.decrypt", "decrypt error")
+)
+
+// Envelope implements envelope encryption.
+//
+// Randomized mode generates a data key per record, encrypts the data with it
+// and stores the data key wrapped by the primary master key next to the ciphertext:
+//
+//	version | mode | key id length | key id | wrapped key length (2 bytes) | wrapped key | nonce | ciphertext
+//
+// Deterministic mode uses keys derived from the master key and a synthetic nonce
+// (HMAC of the plaintext), so equal plaintexts produce equal ciphertexts and can be looked up by equality:
+//
+//	version | mode | key id length | key id | nonce | ciphertext
+//
+// The header is authenticated as additional data in both modes.
+type Envelope struct {
+	provider KeyProvider
+}
+
+func NewEnvelope(provider KeyProvider) *Envelope {
+	return &Envelope{
+		provider: provider,
+	}
+}
+
+func (e *Envelope) Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	keyId := e.provider.PrimaryKeyId()
+
+	masterKey, err := e.provider.Key(ctx, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	header := newHeader(ModeRandomized, keyId)
+
+	dataKey := make([]byte, KeySize)
+	rand.Read(dataKey) //nolint:errcheck,gosec // crypto/rand.Read never returns an error
+
+	wrappedKey, err := seal(masterKey, randomNonce(), dataKey, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(header)+2+len(wrappedKey)+nonceSize+len(plaintext)+16)
+	result = append(result, header...)
+	result = binary.BigEndian.AppendUint16(result, uint16(len(wrappedKey))) //nolint:gosec
+	result = append(result, wrappedKey...)
+
+	ciphertext, err := seal(dataKey, randomNonce(), plaintext, slices.Concat(header, associatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, ciphertext...), nil
+}
+
+func (e *Envelope) EncryptDeterministic(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	keyId := e.provider.PrimaryKeyId()
+
+	encryptionKey, macKey, err := e.deterministicKeys(ctx, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	header := newHeader(ModeDeterministic, keyId)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(associatedData))))
+	mac.Write(associatedData)
+	mac.Write(plaintext)
+
+	ciphertext, err := seal(encryptionKey, mac.Sum(nil)[:nonceSize], plaintext, slices.Concat(header, associatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, ciphertext...), nil
+}
+
+// Decrypt decrypts data produced by Encrypt or EncryptDeterministic.
+func (e *Envelope) Decrypt(ctx context.Context, data []byte, associatedData []byte) ([]byte, error) {
+	mode, keyId, rest, err := parseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header := data[:len(data)-len(rest)]
+
+	switch mode {
+	case ModeRandomized:
+		masterKey, err := e.provider.Key(ctx, keyId)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) < 2 {
+			return nil, ErrInvalidCiphertext
+		}
+
+		wrappedKeyLength := int(binary.BigEndian.Uint16(rest))
+		rest = rest[2:]
+
+		if len(rest) < wrappedKeyLength {
+			return nil, ErrInvalidCiphertext
+		}
+
+		dataKey, err := open(masterKey, rest[:wrappedKeyLength], header)
+		if err != nil {
+			return nil, err
+		}
+
+		return open(dataKey, rest[wrappedKeyLength:], slices.Concat(header, associatedData))
+
+	case ModeDeterministic:
+		encryptionKey, _, err := e.deterministicKeys(ctx, keyId)
+		if err != nil {
+			return nil, err
+		}
+
+		return open(encryptionKey, rest, slices.Concat(header, associatedData))
+
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %d", ErrInvalidCiphertext, mode)
+	}
+}
+
+func (e *Envelope) deterministicKeys(ctx context.Context, keyId string) ([]byte, []byte, error) {
+	masterKey, err := e.provider.Key(ctx, keyId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptionKey, err := hkdf.Key(sha256.New, masterKey, nil, deterministicEncryptionInfo, KeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	macKey, err := hkdf.Key(sha256.New, masterKey, nil, deterministicMacInfo, KeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encryptionKey, macKey, nil
+}
+
+func newHeader(mode Mode, keyId string) []byte {
+	header := make([]byte, 0, 3+len(keyId))
+	header = append(header, envelopeVersion, byte(mode), byte(len(keyId)))
+
+	return append(header, keyId...)
+}
+
+func parseHeader(data []byte) (Mode, string, []byte, error) {
+	if len(data) < 3 || data[0] != envelopeVersion {
+		return 0, "", nil, ErrInvalidCiphertext
+	}
+
+	keyIdLength := int(data[2])
+
+	if keyIdLength == 0 || len(data) < 3+keyIdLength {
+		return 0, "", nil, ErrInvalidCiphertext
+	}
+
+	return Mode(data[1]), string(data[3 : 3+keyIdLength]), data[3+keyIdLength:], nil
+}
+
+// seal returns nonce | ciphertext.
+func seal(key []byte, nonce []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(append([]byte{}, nonce...), nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, data []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < nonceSize+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, data[:nonceSize], data[nonceSize:], additionalData)
+	if err != nil {
+		return nil, errors.Join(ErrDecrypt, err)
+	}
+
+	return plaintext, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func randomNonce() []byte {
+	nonce := make([]byte, nonceSize)
+	rand.Read(nonce) //nolint:errcheck,gosec // crypto/rand.Read never returns an error
+
+	return nonce
+}
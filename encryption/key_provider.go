@@ -0,0 +1,112 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/errors"
+)
+
+const KeySize = 32
+
+var (
+	ErrInvalidKey   = errors.New("encryption.invalid_key", "invalid master key")
+	ErrNoPrimaryKey = errors.New("encryption.no_primary_key", "primary master key not found")
+	ErrUnknownKey   = errors.New("encryption.unknown_key", "unknown master key id")
+	ErrReadKey      = errors.New("encryption.read_key", "read master key")
+)
+
+// KeyProvider supplies 32-byte master keys by id.
+// New data is always protected with the primary key, older keys stay available for decryption.
+type KeyProvider interface {
+	PrimaryKeyId() string
+	Key(ctx context.Context, keyId string) ([]byte, error)
+}
+
+type StaticKeyProvider struct {
+	primaryKeyId string
+	keys         map[string][]byte
+}
+
+func NewStaticKeyProvider(primaryKeyId string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	for keyId, key := range keys {
+		if keyId == "" || len(keyId) > maxKeyIdLength {
+			return nil, fmt.Errorf("%w: key id must be 1..%d bytes", ErrInvalidKey, maxKeyIdLength)
+		}
+
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes", ErrInvalidKey, keyId, KeySize)
+		}
+	}
+
+	if _, ok := keys[primaryKeyId]; !ok {
+		return nil, ErrNoPrimaryKey
+	}
+
+	return &StaticKeyProvider{
+		primaryKeyId: primaryKeyId,
+		keys:         keys,
+	}, nil
+}
+
+// NewEnvKeyProvider reads base64 encoded master keys from environment variables, indexed by key id.
+func NewEnvKeyProvider(primaryKeyId string, envNames map[string]string) (*StaticKeyProvider, error) {
+	keys := make(map[string][]byte, len(envNames))
+
+	for keyId, envName := range envNames {
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			return nil, fmt.Errorf("%w: env %s is not set", ErrReadKey, envName)
+		}
+
+		key, err := decodeKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: env %s: %w", ErrReadKey, envName, err)
+		}
+
+		keys[keyId] = key
+	}
+
+	return NewStaticKeyProvider(primaryKeyId, keys)
+}
+
+// NewFileKeyProvider reads base64 encoded master keys from local files, indexed by key id.
+func NewFileKeyProvider(primaryKeyId string, paths map[string]string) (*StaticKeyProvider, error) {
+	keys := make(map[string][]byte, len(paths))
+
+	for keyId, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrReadKey, path, err)
+		}
+
+		key, err := decodeKey(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrReadKey, path, err)
+		}
+
+		keys[keyId] = key
+	}
+
+	return NewStaticKeyProvider(primaryKeyId, keys)
+}
+
+func (p *StaticKeyProvider) PrimaryKeyId() string {
+	return p.primaryKeyId
+}
+
+func (p *StaticKeyProvider) Key(_ context.Context, keyId string) ([]byte, error) {
+	key, ok := p.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyId)
+	}
+
+	return key, nil
+}
+
+func decodeKey(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+}
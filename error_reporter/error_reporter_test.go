@@ -0,0 +1,230 @@
+package error_reporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/pixality-inc/golang-core/env"
+	"github.com/pixality-inc/golang-core/kafka"
+	"github.com/pixality-inc/golang-core/pool"
+)
+
+var (
+	errReporterTest = errors.New("reporter test error")
+	errSinkTest     = errors.New("sink test error")
+)
+
+type captureSink struct {
+	mutex   sync.Mutex
+	reports []*Report
+	err     error
+}
+
+func (s *captureSink) Send(_ context.Context, report *Report) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reports = append(s.reports, report)
+
+	return s.err
+}
+
+func (s *captureSink) Reports() []*Report {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.reports
+}
+
+func newTestReporter() (*Impl, *captureSink) {
+	sink := &captureSink{}
+
+	appEnv := env.New("test", "pipeline-1", "v1.2.3", "main", "0123456789abcdef", "0123456", time.Now())
+
+	return New(appEnv, sink), sink
+}
+
+func panickingFunction() {
+	panic("boom")
+}
+
+func TestRecoverReportsPanic(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	func() {
+		defer Recover(t.Context(), reporter, WithTag("key", "value"))
+
+		panickingFunction()
+	}()
+
+	reports := sink.Reports()
+	require.Len(t, reports, 1)
+
+	report := reports[0]
+
+	assert.True(t, report.IsPanic)
+	assert.Equal(t, "boom", report.PanicValue)
+	assert.Equal(t, LevelFatal, report.Level)
+	assert.Equal(t, "value", report.Tags["key"])
+	assert.ErrorIs(t, report.Error, ErrPanic)
+	assert.Len(t, report.EventId, 32)
+	assert.False(t, report.Timestamp.IsZero())
+
+	require.NotNil(t, report.Release)
+	assert.Equal(t, "test", report.Release.Environment)
+	assert.Equal(t, "v1.2.3", report.Release.Release)
+
+	require.NotEmpty(t, report.Stack)
+	assert.Equal(t, "panickingFunction", report.Stack[0].Function)
+	assert.Equal(t, "github.com/pixality-inc/golang-core/error_reporter", report.Stack[0].Module)
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	func() {
+		defer Recover(t.Context(), reporter)
+	}()
+
+	assert.Empty(t, sink.Reports())
+}
+
+func TestRecoverToError(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	run := func() (err error) {
+		defer RecoverToError(t.Context(), reporter, &err)
+
+		panic(errReporterTest)
+	}
+
+	err := run()
+
+	require.ErrorIs(t, err, ErrPanic)
+	require.ErrorIs(t, err, errReporterTest)
+	assert.Len(t, sink.Reports(), 1)
+}
+
+func TestReportErrorLogsSinkFailure(t *testing.T) {
+	t.Parallel()
+
+	sink := &captureSink{err: errSinkTest}
+	reporter := New(nil, sink, NewLoggerSink())
+
+	reporter.ReportError(t.Context(), errReporterTest, WithLevel(LevelWarning))
+
+	reports := sink.Reports()
+	require.Len(t, reports, 1)
+
+	assert.False(t, reports[0].IsPanic)
+	assert.Equal(t, LevelWarning, reports[0].Level)
+	assert.Equal(t, errReporterTest.Error(), reports[0].Message)
+	assert.Nil(t, reports[0].Release)
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	handler := NewRecoverMiddleware(reporter).Handle(func(_ *fasthttp.RequestCtx) {
+		panickingFunction()
+	})
+
+	var ctx fasthttp.RequestCtx
+
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("http://example.com/api/items?id=1")
+	ctx.Request.Header.Set("Authorization", "Bearer secret")
+	ctx.Request.Header.Set("X-Custom", "custom")
+
+	handler(&ctx)
+
+	assert.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+
+	reports := sink.Reports()
+	require.Len(t, reports, 1)
+
+	report := reports[0]
+
+	assert.Equal(t, SourceHttp, report.Source)
+	require.NotNil(t, report.Request)
+	assert.Equal(t, fasthttp.MethodPost, report.Request.Method)
+	assert.Equal(t, "http://example.com/api/items?id=1", report.Request.Url)
+	assert.Equal(t, "custom", report.Request.Headers["X-Custom"])
+	assert.NotContains(t, report.Request.Headers, "Authorization")
+}
+
+func TestWrapTask(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	task := WrapTask(reporter, pool.NewTask(func(_ context.Context) error {
+		panickingFunction()
+
+		return nil
+	}))
+
+	require.ErrorIs(t, task.Run(t.Context()), ErrPanic)
+
+	reports := sink.Reports()
+	require.Len(t, reports, 1)
+	assert.Equal(t, SourcePool, reports[0].Source)
+
+	okTask := WrapTaskFunc(reporter, func(_ context.Context) error { return errReporterTest })
+
+	require.ErrorIs(t, okTask(t.Context()), errReporterTest)
+	assert.Len(t, sink.Reports(), 1)
+}
+
+func TestWrapKafkaHandler(t *testing.T) {
+	t.Parallel()
+
+	reporter, sink := newTestReporter()
+
+	handler := WrapKafkaHandler(reporter, func(_ context.Context, _ kafka.Message[string]) error {
+		panickingFunction()
+
+		return nil
+	})
+
+	msg := kafka.NewMessage("value", nil, "events", 3, 42, time.Now(), nil, nil)
+
+	require.ErrorIs(t, handler(t.Context(), msg), ErrPanic)
+
+	reports := sink.Reports()
+	require.Len(t, reports, 1)
+
+	assert.Equal(t, SourceKafka, reports[0].Source)
+	assert.Equal(t, "events", reports[0].Tags["kafka_topic"])
+	assert.Equal(t, "3", reports[0].Tags["kafka_partition"])
+	assert.Equal(t, "42", reports[0].Tags["kafka_offset"])
+}
+
+func TestSplitFunctionName(t *testing.T) {
+	t.Parallel()
+
+	module, function := splitFunctionName("github.com/org/pkg.(*Type).Method")
+
+	assert.Equal(t, "github.com/org/pkg", module)
+	assert.Equal(t, "(*Type).Method", function)
+
+	module, function = splitFunctionName("main.main")
+
+	assert.Equal(t, "main", module)
+	assert.Equal(t, "main", function)
+}
@@ -0,0 +1,85 @@
+package error_reporter
+
+import (
+	"strings"
+
+	realip "github.com/ferluci/fast-realip"
+	"github.com/valyala/fasthttp"
+
+	"github.com/pixality-inc/golang-core/http"
+)
+
+var sensitiveHeaders = map[string]struct{}{
+	"authorization": {},
+	"cookie":        {},
+	"set-cookie":    {},
+	"x-api-key":     {},
+}
+
+type RecoverMiddleware struct {
+	reporter Reporter
+}
+
+func NewRecoverMiddleware(reporter Reporter) *RecoverMiddleware {
+	return &RecoverMiddleware{
+		reporter: reporter,
+	}
+}
+
+func (m *RecoverMiddleware) Handle(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		m.handle(ctx, next)
+	}
+}
+
+func (m *RecoverMiddleware) handle(ctx *fasthttp.RequestCtx, next fasthttp.RequestHandler) {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+
+		reportRecovered(
+			ctx,
+			m.reporter,
+			value,
+			WithSource(SourceHttp),
+			WithRequest(NewRequestInfo(ctx)),
+		)
+
+		ctx.ResetBody()
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+
+		http.InternalServerError(ctx, http.ErrInternalServerError)
+	}()
+
+	next(ctx)
+}
+
+func NewRequestInfo(ctx *fasthttp.RequestCtx) *RequestInfo {
+	headers := make(map[string]string)
+
+	for key, value := range ctx.Request.Header.All() {
+		name := string(key)
+
+		if _, ok := sensitiveHeaders[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		headers[name] = string(value)
+	}
+
+	requestInfo := &RequestInfo{
+		Method:    string(ctx.Method()),
+		Url:       ctx.URI().String(),
+		ClientIp:  realip.FromRequest(ctx),
+		UserAgent: string(ctx.UserAgent()),
+		Headers:   headers,
+	}
+
+	if requestMetadata := http.GetRequestMetadata(ctx); requestMetadata != nil {
+		requestInfo.RequestId = requestMetadata.RequestId
+	}
+
+	return requestInfo
+}
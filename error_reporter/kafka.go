@@ -0,0 +1,28 @@
+package error_reporter
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pixality-inc/golang-core/kafka"
+)
+
+// WrapKafkaHandler returns a kafka.Handler that reports panics of the wrapped handler
+// and turns them into errors, so the consumer's retry and failed message handling applies.
+func WrapKafkaHandler[T any](reporter Reporter, handler kafka.Handler[T]) kafka.Handler[T] {
+	return func(ctx context.Context, msg kafka.Message[T]) (err error) {
+		defer RecoverToError(
+			ctx,
+			reporter,
+			&err,
+			WithSource(SourceKafka),
+			WithTags(map[string]string{
+				"kafka_topic":     msg.Topic(),
+				"kafka_partition": strconv.FormatInt(int64(msg.Partition()), 10),
+				"kafka_offset":    strconv.FormatInt(msg.Offset(), 10),
+			}),
+		)
+
+		return handler(ctx, msg)
+	}
+}
@@ -0,0 +1,31 @@
+package error_reporter
+
+import (
+	"context"
+
+	"github.com/pixality-inc/golang-core/pool"
+)
+
+type recoverTask struct {
+	reporter Reporter
+	task     pool.Task
+}
+
+// WrapTask returns a pool.Task that reports panics of the wrapped task and turns them into errors.
+func WrapTask(reporter Reporter, task pool.Task) pool.Task {
+	return &recoverTask{
+		reporter: reporter,
+		task:     task,
+	}
+}
+
+// WrapTaskFunc is WrapTask for plain pool.TaskFunc values.
+func WrapTaskFunc(reporter Reporter, function pool.TaskFunc) pool.TaskFunc {
+	return WrapTask(reporter, pool.NewTask(function)).Run
+}
+
+func (t *recoverTask) Run(ctx context.Context) (err error) {
+	defer RecoverToError(ctx, t.reporter, &err, WithSource(SourcePool))
+
+	return t.task.Run(ctx)
+}
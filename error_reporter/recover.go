@@ -0,0 +1,45 @@
+package error_reporter
+
+import "context"
+
+// Recover reports an in-flight panic and swallows it.
+// It must be deferred directly: defer error_reporter.Recover(ctx, reporter).
+func Recover(ctx context.Context, reporter Reporter, options ...ReportOption) {
+	if value := recover(); value != nil {
+		reportRecovered(ctx, reporter, value, options...)
+	}
+}
+
+// RecoverToError reports an in-flight panic and stores it into err as an error wrapping ErrPanic.
+// It must be deferred directly: defer error_reporter.RecoverToError(ctx, reporter, &err).
+func RecoverToError(ctx context.Context, reporter Reporter, err *error, options ...ReportOption) {
+	if value := recover(); value != nil {
+		reportRecovered(ctx, reporter, value, options...)
+
+		if err != nil {
+			*err = PanicError(value)
+		}
+	}
+}
+
+func reportRecovered(ctx context.Context, reporter Reporter, value any, options ...ReportOption) {
+	// skip reportRecovered and the deferred Recover* helper
+	stack := trimRuntimeFrames(CaptureStack(2))
+
+	if reporter == nil {
+		return
+	}
+
+	reporter.ReportPanic(ctx, value, stack, options...)
+}
+
+// trimRuntimeFrames drops the leading runtime frames (gopanic, sigpanic, ...) so the stack starts at the panicking function.
+func trimRuntimeFrames(stack []StackFrame) []StackFrame {
+	for index, frame := range stack {
+		if frame.Module != "runtime" {
+			return stack[index:]
+		}
+	}
+
+	return stack
+}
@@ -0,0 +1,143 @@
+package error_reporter
+
+import (
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pixality-inc/golang-core/env"
+)
+
+type Level string
+
+const (
+	LevelFatal   Level = "fatal"
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+)
+
+type Source string
+
+const (
+	SourceManual Source = "manual"
+	SourceHttp   Source = "http"
+	SourcePool   Source = "pool"
+	SourceKafka  Source = "kafka"
+)
+
+type StackFrame struct {
+	Function string
+	Module   string
+	File     string
+	Line     int
+}
+
+type RequestInfo struct {
+	Method    string
+	Url       string
+	RequestId string
+	ClientIp  string
+	UserAgent string
+	Headers   map[string]string
+}
+
+type ReleaseInfo struct {
+	Environment  string
+	Release      string
+	GitBranch    string
+	GitCommit    string
+	CiPipelineId string
+	StartedAt    time.Time
+}
+
+type Report struct {
+	EventId    string
+	Timestamp  time.Time
+	Level      Level
+	Source     Source
+	Message    string
+	Error      error
+	PanicValue any
+	IsPanic    bool
+	Stack      []StackFrame
+	Request    *RequestInfo
+	Release    *ReleaseInfo
+	Tags       map[string]string
+	Extra      map[string]any
+}
+
+// CaptureStack returns the current goroutine's stack, innermost frame first,
+// skipping the given number of callers above CaptureStack itself.
+func CaptureStack(skip int) []StackFrame {
+	pcs := make([]uintptr, 64)
+
+	count := runtime.Callers(skip+2, pcs)
+	if count == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs[:count])
+	stack := make([]StackFrame, 0, count)
+
+	for {
+		frame, more := frames.Next()
+
+		stack = append(stack, newStackFrame(frame))
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+func NewReleaseInfo(appEnv env.AppEnv) *ReleaseInfo {
+	if appEnv == nil {
+		return nil
+	}
+
+	release := appEnv.GitTag()
+	if release == "" {
+		release = appEnv.GitCommitShort()
+	}
+
+	if release == "" {
+		release = appEnv.GitCommit()
+	}
+
+	return &ReleaseInfo{
+		Environment:  appEnv.EnvName(),
+		Release:      release,
+		GitBranch:    appEnv.GitBranch(),
+		GitCommit:    appEnv.GitCommit(),
+		CiPipelineId: appEnv.CiPipelineId(),
+		StartedAt:    appEnv.StartedAt(),
+	}
+}
+
+func newStackFrame(frame runtime.Frame) StackFrame {
+	module, function := splitFunctionName(frame.Function)
+
+	return StackFrame{
+		Function: function,
+		Module:   module,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
+
+// splitFunctionName splits "github.com/org/pkg.(*Type).Method" into
+// "github.com/org/pkg" and "(*Type).Method".
+func splitFunctionName(name string) (string, string) {
+	lastSlash := strings.LastIndex(name, "/")
+
+	dot := strings.Index(name[lastSlash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+
+	dot += lastSlash + 1
+
+	return name[:dot], name[dot+1:]
+}
@@ -0,0 +1,178 @@
+package error_reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pixality-inc/golang-core/clock"
+	"github.com/pixality-inc/golang-core/env"
+	"github.com/pixality-inc/golang-core/logger"
+)
+
+var ErrPanic = errors.New("panic recovered")
+
+type Reporter interface {
+	Report(ctx context.Context, report *Report)
+	ReportError(ctx context.Context, err error, options ...ReportOption)
+	ReportPanic(ctx context.Context, value any, stack []StackFrame, options ...ReportOption)
+}
+
+type Impl struct {
+	log     logger.Loggable
+	clock   clock.Clock
+	release *ReleaseInfo
+	sinks   []Sink
+}
+
+func New(appEnv env.AppEnv, sinks ...Sink) *Impl {
+	return &Impl{
+		log:     logger.NewLoggableImplWithService("error_reporter"),
+		clock:   clock.New(),
+		release: NewReleaseInfo(appEnv),
+		sinks:   sinks,
+	}
+}
+
+func (r *Impl) Report(ctx context.Context, report *Report) {
+	r.prepare(report)
+
+	for _, sink := range r.sinks {
+		if err := sink.Send(ctx, report); err != nil {
+			r.log.GetLogger(ctx).
+				WithError(err).
+				WithField("event_id", report.EventId).
+				Error("failed to send error report")
+		}
+	}
+}
+
+func (r *Impl) ReportError(ctx context.Context, err error, options ...ReportOption) {
+	report := &Report{
+		Level:   LevelError,
+		Source:  SourceManual,
+		Message: err.Error(),
+		Error:   err,
+		Stack:   CaptureStack(1),
+	}
+
+	applyReportOptions(report, options...)
+
+	r.Report(ctx, report)
+}
+
+func (r *Impl) ReportPanic(ctx context.Context, value any, stack []StackFrame, options ...ReportOption) {
+	report := &Report{
+		Level:      LevelFatal,
+		Source:     SourceManual,
+		Message:    fmt.Sprintf("panic: %v", value),
+		Error:      PanicError(value),
+		PanicValue: value,
+		IsPanic:    true,
+		Stack:      stack,
+	}
+
+	applyReportOptions(report, options...)
+
+	r.Report(ctx, report)
+}
+
+func (r *Impl) prepare(report *Report) {
+	if report.EventId == "" {
+		report.EventId = strings.ReplaceAll(uuid.New().String(), "-", "")
+	}
+
+	if report.Timestamp.IsZero() {
+		report.Timestamp = r.clock.Now()
+	}
+
+	if report.Level == "" {
+		report.Level = LevelError
+	}
+
+	if report.Release == nil {
+		report.Release = r.release
+	}
+}
+
+// PanicError converts a recovered panic value into an error wrapping ErrPanic.
+func PanicError(value any) error {
+	if err, ok := value.(error); ok {
+		return fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+
+	return fmt.Errorf("%w: %v", ErrPanic, value)
+}
+
+type ReportOption func(report *Report)
+
+func WithLevel(level Level) ReportOption {
+	return func(report *Report) {
+		report.Level = level
+	}
+}
+
+func WithSource(source Source) ReportOption {
+	return func(report *Report) {
+		report.Source = source
+	}
+}
+
+func WithMessage(message string) ReportOption {
+	return func(report *Report) {
+		report.Message = message
+	}
+}
+
+func WithRequest(request *RequestInfo) ReportOption {
+	return func(report *Report) {
+		report.Request = request
+	}
+}
+
+func WithTag(key string, value string) ReportOption {
+	return func(report *Report) {
+		if report.Tags == nil {
+			report.Tags = make(map[string]string)
+		}
+
+		report.Tags[key] = value
+	}
+}
+
+func WithTags(tags map[string]string) ReportOption {
+	return func(report *Report) {
+		if report.Tags == nil {
+			report.Tags = make(map[string]string)
+		}
+
+		maps.Copy(report.Tags, tags)
+	}
+}
+
+func WithExtra(key string, value any) ReportOption {
+	return func(report *Report) {
+		if report.Extra == nil {
+			report.Extra = make(map[string]any)
+		}
+
+		report.Extra[key] = value
+	}
+}
+
+func WithTimestamp(timestamp time.Time) ReportOption {
+	return func(report *Report) {
+		report.Timestamp = timestamp
+	}
+}
+
+func applyReportOptions(report *Report, options ...ReportOption) {
+	for _, option := range options {
+		option(report)
+	}
+}
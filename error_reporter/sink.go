@@ -0,0 +1,98 @@
+package error_reporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/logger"
+)
+
+type Sink interface {
+	Send(ctx context.Context, report *Report) error
+}
+
+type LoggerSink struct {
+	log logger.Loggable
+}
+
+func NewLoggerSink() *LoggerSink {
+	return &LoggerSink{
+		log: logger.NewLoggableImplWithService("error_reporter"),
+	}
+}
+
+func (s *LoggerSink) Send(ctx context.Context, report *Report) error {
+	fields := logger.Fields{
+		"event_id": report.EventId,
+		"level":    string(report.Level),
+		"source":   string(report.Source),
+		"panic":    report.IsPanic,
+	}
+
+	if report.IsPanic {
+		fields["panic_value"] = fmt.Sprint(report.PanicValue)
+	}
+
+	if len(report.Stack) > 0 {
+		fields["stack"] = formatStack(report.Stack)
+	}
+
+	if report.Request != nil {
+		fields["http_method"] = report.Request.Method
+		fields["url"] = report.Request.Url
+
+		if report.Request.RequestId != "" {
+			fields["request_id"] = report.Request.RequestId
+		}
+	}
+
+	if report.Release != nil {
+		fields["environment"] = report.Release.Environment
+		fields["release"] = report.Release.Release
+	}
+
+	for key, value := range report.Tags {
+		fields["tag_"+key] = value
+	}
+
+	for key, value := range report.Extra {
+		fields["extra_"+key] = value
+	}
+
+	log := s.log.GetLogger(ctx).WithFields(fields)
+
+	if report.Error != nil {
+		log = log.WithError(report.Error)
+	}
+
+	switch report.Level {
+	case LevelWarning:
+		log.Warn(report.Message)
+	default:
+		log.Error(report.Message)
+	}
+
+	return nil
+}
+
+func formatStack(stack []StackFrame) string {
+	builder := strings.Builder{}
+
+	for _, frame := range stack {
+		if frame.Module != "" {
+			builder.WriteString(frame.Module)
+			builder.WriteString(".")
+		}
+
+		builder.WriteString(frame.Function)
+		builder.WriteString("\n\t")
+		builder.WriteString(frame.File)
+		builder.WriteString(":")
+		builder.WriteString(strconv.Itoa(frame.Line))
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,33 @@
+package sentry
+
+import "time"
+
+const DefaultTimeout = 5 * time.Second
+
+type Config interface {
+	Dsn() string
+	Timeout() time.Duration
+	ServerName() string
+}
+
+type ConfigYaml struct {
+	DsnValue        string        `env:"DSN"         yaml:"dsn"`
+	TimeoutValue    time.Duration `env:"TIMEOUT"     yaml:"timeout"`
+	ServerNameValue string        `env:"SERVER_NAME" yaml:"server_name"`
+}
+
+func (c *ConfigYaml) Dsn() string {
+	return c.DsnValue
+}
+
+func (c *ConfigYaml) Timeout() time.Duration {
+	if c.TimeoutValue == 0 {
+		return DefaultTimeout
+	}
+
+	return c.TimeoutValue
+}
+
+func (c *ConfigYaml) ServerName() string {
+	return c.ServerNameValue
+}
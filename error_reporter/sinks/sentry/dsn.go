@@ -0,0 +1,71 @@
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidDsn = errors.New("invalid sentry dsn")
+
+// Dsn is a parsed Sentry DSN: {scheme}://{public_key}@{host}{/path}/{project_id}.
+type Dsn struct {
+	raw       string
+	scheme    string
+	host      string
+	path      string
+	publicKey string
+	projectId string
+}
+
+func ParseDsn(raw string) (*Dsn, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDsn, err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidDsn, parsed.Scheme)
+	}
+
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("%w: missing public key", ErrInvalidDsn)
+	}
+
+	path := strings.TrimSuffix(parsed.Path, "/")
+
+	lastSlash := strings.LastIndex(path, "/")
+	if lastSlash < 0 || lastSlash == len(path)-1 {
+		return nil, fmt.Errorf("%w: missing project id", ErrInvalidDsn)
+	}
+
+	return &Dsn{
+		raw:       raw,
+		scheme:    parsed.Scheme,
+		host:      parsed.Host,
+		path:      path[:lastSlash],
+		publicKey: parsed.User.Username(),
+		projectId: path[lastSlash+1:],
+	}, nil
+}
+
+func (d *Dsn) String() string {
+	return d.raw
+}
+
+func (d *Dsn) PublicKey() string {
+	return d.publicKey
+}
+
+func (d *Dsn) ProjectId() string {
+	return d.projectId
+}
+
+func (d *Dsn) BaseUrl() string {
+	return d.scheme + "://" + d.host
+}
+
+func (d *Dsn) EnvelopePath() string {
+	return d.path + "/api/" + d.projectId + "/envelope/"
+}
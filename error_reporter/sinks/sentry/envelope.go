@@ -0,0 +1,231 @@
+package sentry
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/pixality-inc/golang-core/error_reporter"
+	"github.com/pixality-inc/golang-core/json"
+)
+
+const (
+	platform   = "go"
+	sdkName    = "pixality.golang-core"
+	sdkVersion = "1.0.0"
+)
+
+type envelopeHeader struct {
+	EventId string    `json:"event_id"`
+	SentAt  time.Time `json:"sent_at"`
+	Dsn     string    `json:"dsn"`
+}
+
+type itemHeader struct {
+	Type   string `json:"type"`
+	Length int    `json:"length"`
+}
+
+type Event struct {
+	EventId     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Exception   *ExceptionList    `json:"exception,omitempty"`
+	Request     *Request          `json:"request,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Sdk         Sdk               `json:"sdk"`
+}
+
+type Sdk struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type ExceptionList struct {
+	Values []Exception `json:"values"`
+}
+
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Mechanism  *Mechanism  `json:"mechanism,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+type Mechanism struct {
+	Type    string `json:"type"`
+	Handled bool   `json:"handled"`
+}
+
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+type Request struct {
+	Method  string            `json:"method,omitempty"`
+	Url     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+func NewEvent(report *error_reporter.Report, serverName string) *Event {
+	event := &Event{
+		EventId:    report.EventId,
+		Timestamp:  report.Timestamp.UTC(),
+		Platform:   platform,
+		Level:      string(report.Level),
+		Logger:     string(report.Source),
+		ServerName: serverName,
+		Message:    report.Message,
+		Tags:       make(map[string]string),
+		Extra:      make(map[string]any),
+		Sdk: Sdk{
+			Name:    sdkName,
+			Version: sdkVersion,
+		},
+	}
+
+	maps.Copy(event.Tags, report.Tags)
+	maps.Copy(event.Extra, report.Extra)
+
+	if release := report.Release; release != nil {
+		event.Release = release.Release
+		event.Environment = release.Environment
+
+		if release.GitBranch != "" {
+			event.Tags["git_branch"] = release.GitBranch
+		}
+
+		if release.GitCommit != "" {
+			event.Tags["git_commit"] = release.GitCommit
+		}
+
+		if release.CiPipelineId != "" {
+			event.Tags["ci_pipeline_id"] = release.CiPipelineId
+		}
+	}
+
+	if report.Error != nil || report.IsPanic {
+		event.Exception = &ExceptionList{
+			Values: []Exception{newException(report)},
+		}
+	}
+
+	if request := report.Request; request != nil {
+		event.Request = &Request{
+			Method:  request.Method,
+			Url:     request.Url,
+			Headers: request.Headers,
+		}
+
+		if request.ClientIp != "" {
+			event.Request.Env = map[string]string{
+				"REMOTE_ADDR": request.ClientIp,
+			}
+		}
+
+		if request.RequestId != "" {
+			event.Tags["request_id"] = request.RequestId
+		}
+	}
+
+	return event
+}
+
+func newException(report *error_reporter.Report) Exception {
+	exception := Exception{
+		Type:  "error",
+		Value: report.Message,
+	}
+
+	if report.Error != nil {
+		exception.Type = fmt.Sprintf("%T", report.Error)
+		exception.Value = report.Error.Error()
+	}
+
+	if report.IsPanic {
+		exception.Type = "panic"
+		exception.Value = fmt.Sprint(report.PanicValue)
+		exception.Mechanism = &Mechanism{
+			Type:    string(report.Source),
+			Handled: false,
+		}
+	}
+
+	if len(report.Stack) > 0 {
+		frames := make([]Frame, 0, len(report.Stack))
+
+		for _, stackFrame := range report.Stack {
+			frames = append(frames, Frame{
+				Function: stackFrame.Function,
+				Module:   stackFrame.Module,
+				AbsPath:  stackFrame.File,
+				Lineno:   stackFrame.Line,
+				InApp:    stackFrame.Module != "runtime",
+			})
+		}
+
+		// sentry expects the innermost frame last
+		slices.Reverse(frames)
+
+		exception.Stacktrace = &Stacktrace{
+			Frames: frames,
+		}
+	}
+
+	return exception
+}
+
+// EncodeEnvelope serializes an event into the Sentry envelope format:
+// an envelope header, an item header and the event payload, separated by newlines.
+func EncodeEnvelope(dsn *Dsn, event *Event, sentAt time.Time) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(envelopeHeader{
+		EventId: event.EventId,
+		SentAt:  sentAt.UTC(),
+		Dsn:     dsn.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := json.Marshal(itemHeader{
+		Type:   "event",
+		Length: len(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, len(header)+len(item)+len(payload)+3))
+
+	buffer.Write(header)
+	buffer.WriteByte('\n')
+	buffer.Write(item)
+	buffer.WriteByte('\n')
+	buffer.Write(payload)
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,91 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixality-inc/golang-core/clock"
+	"github.com/pixality-inc/golang-core/error_reporter"
+	"github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/logger"
+)
+
+var ErrSend = errors.New("sentry send")
+
+const (
+	protocolVersion = "7"
+	envelopeType    = "application/x-sentry-envelope"
+)
+
+type Sink struct {
+	log        logger.Loggable
+	clock      clock.Clock
+	dsn        *Dsn
+	serverName string
+	httpClient http_client.Client
+}
+
+func New(config Config) (*Sink, error) {
+	dsn, err := ParseDsn(config.Dsn())
+	if err != nil {
+		return nil, err
+	}
+
+	log := logger.NewLoggableImplWithService("sentry")
+
+	httpClient, err := http_client.NewClientImpl(log, &http_client.ConfigYaml{
+		BaseUrlValue: dsn.BaseUrl(),
+		NameValue:    "sentry",
+		TimeoutValue: config.Timeout(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sink{
+		log:        log,
+		clock:      clock.New(),
+		dsn:        dsn,
+		serverName: config.ServerName(),
+		httpClient: httpClient,
+	}, nil
+}
+
+func (s *Sink) Send(ctx context.Context, report *error_reporter.Report) error {
+	now := s.clock.Now()
+
+	body, err := EncodeEnvelope(s.dsn, NewEvent(report, s.serverName), now)
+	if err != nil {
+		return errors.Join(ErrSend, err)
+	}
+
+	response, err := s.httpClient.Post(
+		ctx,
+		s.dsn.EnvelopePath(),
+		http_client.WithBody(body),
+		http_client.WithHeader("Content-Type", envelopeType),
+		http_client.WithHeader("X-Sentry-Auth", s.authHeader(now)),
+	)
+	if err != nil {
+		return errors.Join(ErrSend, err)
+	}
+
+	if statusCode := response.GetStatusCode(); statusCode < 200 || statusCode > 299 {
+		return fmt.Errorf("%w: unexpected status code %d", ErrSend, statusCode)
+	}
+
+	return nil
+}
+
+func (s *Sink) authHeader(now time.Time) string {
+	return fmt.Sprintf(
+		"Sentry sentry_version=%s, sentry_client=%s/%s, sentry_timestamp=%d, sentry_key=%s",
+		protocolVersion,
+		sdkName,
+		sdkVersion,
+		now.Unix(),
+		s.dsn.PublicKey(),
+	)
+}
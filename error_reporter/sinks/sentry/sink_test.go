@@ -0,0 +1,191 @@
+package sentry
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/error_reporter"
+	"github.com/pixality-inc/golang-core/json"
+)
+
+var errSinkTest = errors.New("sink test error")
+
+type capturedRequest struct {
+	path        string
+	auth        string
+	contentType string
+	body        []byte
+}
+
+func newTestSink(t *testing.T, statusCode int, requests chan<- capturedRequest) *Sink {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		requests <- capturedRequest{
+			path:        r.URL.Path,
+			auth:        r.Header.Get("X-Sentry-Auth"),
+			contentType: r.Header.Get("Content-Type"),
+			body:        body,
+		}
+
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	dsn := strings.Replace(server.URL, "://", "://public-key@", 1) + "/42"
+
+	sink, err := New(&ConfigYaml{DsnValue: dsn, ServerNameValue: "test-host"})
+	require.NoError(t, err)
+
+	return sink
+}
+
+func testReport() *error_reporter.Report {
+	return &error_reporter.Report{
+		EventId:    "0123456789abcdef0123456789abcdef",
+		Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:      error_reporter.LevelFatal,
+		Source:     error_reporter.SourceHttp,
+		Message:    "panic: boom",
+		Error:      errSinkTest,
+		PanicValue: "boom",
+		IsPanic:    true,
+		Stack: []error_reporter.StackFrame{
+			{Function: "inner", Module: "example.com/app", File: "/app/inner.go", Line: 10},
+			{Function: "outer", Module: "example.com/app", File: "/app/outer.go", Line: 20},
+		},
+		Request: &error_reporter.RequestInfo{
+			Method:    "GET",
+			Url:       "http://example.com/items",
+			RequestId: "request-1",
+			ClientIp:  "10.0.0.1",
+		},
+		Release: &error_reporter.ReleaseInfo{
+			Environment: "production",
+			Release:     "v1.0.0",
+			GitBranch:   "main",
+		},
+		Tags: map[string]string{"tag": "value"},
+	}
+}
+
+func TestParseDsn(t *testing.T) {
+	t.Parallel()
+
+	dsn, err := ParseDsn("https://key@sentry.example.com/prefix/123")
+	require.NoError(t, err)
+
+	assert.Equal(t, "key", dsn.PublicKey())
+	assert.Equal(t, "123", dsn.ProjectId())
+	assert.Equal(t, "https://sentry.example.com", dsn.BaseUrl())
+	assert.Equal(t, "/prefix/api/123/envelope/", dsn.EnvelopePath())
+}
+
+func TestParseDsnErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{
+		"ftp://key@sentry.example.com/1",
+		"https://sentry.example.com/1",
+		"https://key@sentry.example.com/",
+		"://bad",
+	} {
+		_, err := ParseDsn(raw)
+		require.ErrorIs(t, err, ErrInvalidDsn, raw)
+	}
+}
+
+func TestSinkSendsEnvelope(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan capturedRequest, 1)
+	sink := newTestSink(t, http.StatusOK, requests)
+
+	require.NoError(t, sink.Send(t.Context(), testReport()))
+
+	request := <-requests
+
+	assert.Equal(t, "/api/42/envelope/", request.path)
+	assert.Equal(t, envelopeType, request.contentType)
+	assert.Contains(t, request.auth, "sentry_version=7")
+	assert.Contains(t, request.auth, "sentry_key=public-key")
+
+	scanner := bufio.NewScanner(bytes.NewReader(request.body))
+
+	require.True(t, scanner.Scan())
+
+	var header envelopeHeader
+
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", header.EventId)
+
+	require.True(t, scanner.Scan())
+
+	var item itemHeader
+
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &item))
+	assert.Equal(t, "event", item.Type)
+
+	require.True(t, scanner.Scan())
+	assert.Len(t, scanner.Bytes(), item.Length)
+
+	var event Event
+
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+	assert.Equal(t, "fatal", event.Level)
+	assert.Equal(t, "go", event.Platform)
+	assert.Equal(t, "test-host", event.ServerName)
+	assert.Equal(t, "v1.0.0", event.Release)
+	assert.Equal(t, "production", event.Environment)
+	assert.Equal(t, "value", event.Tags["tag"])
+	assert.Equal(t, "main", event.Tags["git_branch"])
+	assert.Equal(t, "request-1", event.Tags["request_id"])
+
+	require.NotNil(t, event.Request)
+	assert.Equal(t, "http://example.com/items", event.Request.Url)
+	assert.Equal(t, "10.0.0.1", event.Request.Env["REMOTE_ADDR"])
+
+	require.NotNil(t, event.Exception)
+	require.Len(t, event.Exception.Values, 1)
+
+	exception := event.Exception.Values[0]
+
+	assert.Equal(t, "panic", exception.Type)
+	assert.Equal(t, "boom", exception.Value)
+	require.NotNil(t, exception.Mechanism)
+	assert.False(t, exception.Mechanism.Handled)
+	require.NotNil(t, exception.Stacktrace)
+	require.Len(t, exception.Stacktrace.Frames, 2)
+	assert.Equal(t, "outer", exception.Stacktrace.Frames[0].Function)
+	assert.Equal(t, "inner", exception.Stacktrace.Frames[1].Function)
+}
+
+func TestSinkReturnsErrorOnFailureStatus(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan capturedRequest, 1)
+	sink := newTestSink(t, http.StatusTooManyRequests, requests)
+
+	require.ErrorIs(t, sink.Send(t.Context(), testReport()), ErrSend)
+}
+
+func TestNewWithInvalidDsn(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&ConfigYaml{DsnValue: "not a dsn"})
+	require.ErrorIs(t, err, ErrInvalidDsn)
+}
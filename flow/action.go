@@ -1,5 +1,10 @@
 package flow
 
+import (
+	"maps"
+	"time"
+)
+
 type Action struct {
 	Name              string            `json:"name"                            yaml:"name"`
 	Trigger           *ActionTrigger    `json:"trigger,omitempty"               yaml:"trigger,omitempty"`
@@ -18,6 +23,16 @@ type Action struct {
 	ScriptFile        string            `json:"script_file,omitempty"           yaml:"script_file,omitempty"`
 	Script            string            `json:"script,omitempty"                yaml:"script,omitempty"`
 	When              string            `json:"when,omitempty"                  yaml:"when,omitempty"`
+	DependsOn         []string          `json:"depends_on,omitempty"            yaml:"depends_on,omitempty"`
+	Timeout           time.Duration     `json:"timeout,omitempty"               yaml:"timeout,omitempty"`
+	Retry             *ActionRetry      `json:"retry,omitempty"                 yaml:"retry,omitempty"`
+	ContinueOnError   bool              `json:"continue_on_error,omitempty"     yaml:"continue_on_error,omitempty"`
+	StdoutJson        bool              `json:"stdout_json,omitempty"           yaml:"stdout_json,omitempty"`
+	Outputs           map[string]string `json:"outputs,omitempty"               yaml:"outputs,omitempty"`
+	ForEach           *ActionForEach    `json:"for_each,omitempty"              yaml:"for_each,omitempty"`
+	Http              *ActionHttp       `json:"http,omitempty"                  yaml:"http,omitempty"`
+	Storage           *ActionStorage    `json:"storage,omitempty"               yaml:"storage,omitempty"`
+	Include           *ActionInclude    `json:"include,omitempty"               yaml:"include,omitempty"`
 }
 
 func NewAction(name string) Action {
@@ -129,3 +144,68 @@ func (a Action) WithWhen(when string) Action {
 
 	return a
 }
+
+func (a Action) WithDependsOn(names ...string) Action {
+	a.DependsOn = names
+
+	return a
+}
+
+func (a Action) WithTimeout(timeout time.Duration) Action {
+	a.Timeout = timeout
+
+	return a
+}
+
+func (a Action) WithRetry(retry ActionRetry) Action {
+	a.Retry = &retry
+
+	return a
+}
+
+func (a Action) WithContinueOnError(continueOnError bool) Action {
+	a.ContinueOnError = continueOnError
+
+	return a
+}
+
+func (a Action) WithStdoutJson() Action {
+	a.StdoutJson = true
+
+	return a
+}
+
+func (a Action) WithOutput(name string, template string) Action {
+	outputs := make(map[string]string, len(a.Outputs)+1)
+
+	maps.Copy(outputs, a.Outputs)
+
+	outputs[name] = template
+	a.Outputs = outputs
+
+	return a
+}
+
+func (a Action) WithHttp(request ActionHttp) Action {
+	a.Http = &request
+
+	return a
+}
+
+func (a Action) WithStorage(operation ActionStorage) Action {
+	a.Storage = &operation
+
+	return a
+}
+
+func (a Action) WithInclude(include ActionInclude) Action {
+	a.Include = &include
+
+	return a
+}
+
+func (a Action) WithForEach(forEach ActionForEach) Action {
+	a.ForEach = &forEach
+
+	return a
+}
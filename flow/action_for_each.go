@@ -0,0 +1,44 @@
+package flow
+
+// ActionForEach expands an action into one instance per item. Items come from exactly
+// one of Items, Template, Script or Matrix, the cartesian product of named lists.
+type ActionForEach struct {
+	Items       []any            `json:"items,omitempty"        yaml:"items,omitempty"`
+	Template    string           `json:"template,omitempty"     yaml:"template,omitempty"`
+	Script      string           `json:"script,omitempty"       yaml:"script,omitempty"`
+	Matrix      map[string][]any `json:"matrix,omitempty"       yaml:"matrix,omitempty"`
+	Parallel    bool             `json:"parallel,omitempty"     yaml:"parallel,omitempty"`
+	MaxParallel int              `json:"max_parallel,omitempty" yaml:"max_parallel,omitempty"`
+}
+
+func NewActionForEachItems(items ...any) ActionForEach {
+	return ActionForEach{
+		Items: append([]any{}, items...),
+	}
+}
+
+func NewActionForEachTemplate(template string) ActionForEach {
+	return ActionForEach{
+		Template: template,
+	}
+}
+
+func NewActionForEachScript(script string) ActionForEach {
+	return ActionForEach{
+		Script: script,
+	}
+}
+
+func NewActionForEachMatrix(matrix map[string][]any) ActionForEach {
+	return ActionForEach{
+		Matrix: matrix,
+	}
+}
+
+// WithParallel runs up to maxParallel instances at once, zero means no limit.
+func (e ActionForEach) WithParallel(maxParallel int) ActionForEach {
+	e.Parallel = true
+	e.MaxParallel = maxParallel
+
+	return e
+}
@@ -0,0 +1,53 @@
+package flow
+
+import "maps"
+
+// ActionHttp sends a request with the flow http client. Url, header values and
+// BodyTemplate are templates, Body is sent as json. The response body is the action
+// stdout, so it can be parsed with stdout_json and exposed through outputs.
+type ActionHttp struct {
+	Method         string            `json:"method,omitempty"          yaml:"method,omitempty"`
+	Url            string            `json:"url"                       yaml:"url"`
+	Headers        map[string]string `json:"headers,omitempty"         yaml:"headers,omitempty"`
+	Body           any               `json:"body,omitempty"            yaml:"body,omitempty"`
+	BodyTemplate   string            `json:"body_template,omitempty"   yaml:"body_template,omitempty"`
+	ExpectedStatus []int             `json:"expected_status,omitempty" yaml:"expected_status,omitempty"`
+}
+
+func NewActionHttp(method string, url string) ActionHttp {
+	return ActionHttp{
+		Method:  method,
+		Url:     url,
+		Headers: make(map[string]string),
+	}
+}
+
+func (h ActionHttp) WithHeader(name string, value string) ActionHttp {
+	headers := make(map[string]string, len(h.Headers)+1)
+
+	maps.Copy(headers, h.Headers)
+
+	headers[name] = value
+	h.Headers = headers
+
+	return h
+}
+
+func (h ActionHttp) WithBody(body any) ActionHttp {
+	h.Body = body
+
+	return h
+}
+
+func (h ActionHttp) WithBodyTemplate(bodyTemplate string) ActionHttp {
+	h.BodyTemplate = bodyTemplate
+
+	return h
+}
+
+// WithExpectedStatus fails the action on any other status, by default every 2xx status is accepted.
+func (h ActionHttp) WithExpectedStatus(statuses ...int) ActionHttp {
+	h.ExpectedStatus = statuses
+
+	return h
+}
@@ -0,0 +1,35 @@
+package flow
+
+import "maps"
+
+// ActionInclude runs another flow file as a sub-flow. A relative Path is resolved
+// against the directory of the including flow file. String inputs are templates
+// evaluated with the parent env, then converted to the type the sub-flow declares.
+type ActionInclude struct {
+	Path   string         `json:"path"             yaml:"path"`
+	Inputs map[string]any `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+}
+
+func NewActionInclude(path string) ActionInclude {
+	return ActionInclude{
+		Path:   path,
+		Inputs: make(map[string]any),
+	}
+}
+
+func (i ActionInclude) WithInput(name string, value any) ActionInclude {
+	inputs := make(map[string]any, len(i.Inputs)+1)
+
+	maps.Copy(inputs, i.Inputs)
+
+	inputs[name] = value
+	i.Inputs = inputs
+
+	return i
+}
+
+func (i ActionInclude) WithInputs(inputs map[string]any) ActionInclude {
+	i.Inputs = inputs
+
+	return i
+}
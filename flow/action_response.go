@@ -2,27 +2,52 @@ package flow
 
 import "time"
 
-type ActionResponse struct {
+// ActionAttempt is the outcome of a single try of an action.
+type ActionAttempt struct {
+	Number     int           `json:"number"`
 	ErrorCode  int           `json:"error_code"`
-	Stdout     string        `json:"stdout"`
-	Stderr     string        `json:"stderr"`
-	Skipped    bool          `json:"skipped"`
+	Error      string        `json:"error,omitempty"`
+	TimedOut   bool          `json:"timed_out"`
 	StartedAt  time.Time     `json:"started_at"`
 	FinishedAt time.Time     `json:"finished_at"`
 	Duration   time.Duration `json:"duration"`
-	Result     string        `json:"result"`
+}
+
+type ActionResponse struct {
+	ErrorCode  int               `json:"error_code"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Stdout     string            `json:"stdout"`
+	Stderr     string            `json:"stderr"`
+	Skipped    bool              `json:"skipped"`
+	Restored   bool              `json:"restored,omitempty"`
+	Failed     bool              `json:"failed"`
+	Error      string            `json:"error,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	Duration   time.Duration     `json:"duration"`
+	Result     string            `json:"result"`
+	Outputs    map[string]string `json:"outputs,omitempty"`
+	Instances  []string          `json:"instances,omitempty"`
+	Attempts   []*ActionAttempt  `json:"attempts,omitempty"`
 }
 
 func NewActionResponse() *ActionResponse {
 	return &ActionResponse{
 		ErrorCode:  0,
+		StatusCode: 0,
 		Stdout:     "",
 		Stderr:     "",
 		Skipped:    false,
+		Restored:   false,
+		Failed:     false,
+		Error:      "",
 		StartedAt:  time.Time{},
 		FinishedAt: time.Time{},
 		Duration:   0,
 		Result:     "",
+		Outputs:    nil,
+		Instances:  nil,
+		Attempts:   nil,
 	}
 }
 
@@ -32,6 +57,13 @@ func (r *ActionResponse) WithExitCode(exitCode int) *ActionResponse {
 	return r
 }
 
+// WithStatusCode sets the http status of http actions.
+func (r *ActionResponse) WithStatusCode(statusCode int) *ActionResponse {
+	r.StatusCode = statusCode
+
+	return r
+}
+
 func (r *ActionResponse) WithStdout(stdout string) *ActionResponse {
 	r.Stdout = stdout
 
@@ -68,8 +100,44 @@ func (r *ActionResponse) WithSkipped(skipped bool) *ActionResponse {
 	return r
 }
 
+// WithRestored marks a response taken from the checkpoint of a previous run.
+func (r *ActionResponse) WithRestored(restored bool) *ActionResponse {
+	r.Restored = restored
+
+	return r
+}
+
 func (r *ActionResponse) WithResult(result string) *ActionResponse {
 	r.Result = result
 
 	return r
 }
+
+func (r *ActionResponse) WithError(err error) *ActionResponse {
+	r.Failed = err != nil
+	r.Error = ""
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+func (r *ActionResponse) WithAttempts(attempts []*ActionAttempt) *ActionResponse {
+	r.Attempts = attempts
+
+	return r
+}
+
+func (r *ActionResponse) WithOutputs(outputs map[string]string) *ActionResponse {
+	r.Outputs = outputs
+
+	return r
+}
+
+func (r *ActionResponse) WithInstances(instances []string) *ActionResponse {
+	r.Instances = instances
+
+	return r
+}
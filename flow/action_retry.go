@@ -0,0 +1,65 @@
+package flow
+
+import (
+	"time"
+
+	"github.com/pixality-inc/golang-core/retry"
+)
+
+// ActionRetry re-runs a failed action, it implements retry.Policy so backoff
+// is calculated the same way as for every other retried operation.
+type ActionRetry struct {
+	AttemptsValue           int           `json:"attempts"                      yaml:"attempts"`
+	InitialIntervalValue    time.Duration `json:"initial_interval,omitempty"    yaml:"initial_interval,omitempty"`
+	BackoffCoefficientValue float64       `json:"backoff_coefficient,omitempty" yaml:"backoff_coefficient,omitempty"`
+	MaxIntervalValue        time.Duration `json:"max_interval,omitempty"        yaml:"max_interval,omitempty"`
+}
+
+var _ retry.Policy = (*ActionRetry)(nil)
+
+func NewActionRetry(attempts int) ActionRetry {
+	return ActionRetry{
+		AttemptsValue:           attempts,
+		InitialIntervalValue:    0,
+		BackoffCoefficientValue: 0,
+		MaxIntervalValue:        0,
+	}
+}
+
+func (r ActionRetry) WithBackoff(initialInterval time.Duration, coefficient float64, maxInterval time.Duration) ActionRetry {
+	r.InitialIntervalValue = initialInterval
+	r.BackoffCoefficientValue = coefficient
+	r.MaxIntervalValue = maxInterval
+
+	return r
+}
+
+func (r *ActionRetry) Enabled() bool {
+	return r.AttemptsValue > 1
+}
+
+func (r *ActionRetry) MaxAttempts() int {
+	return r.AttemptsValue
+}
+
+func (r *ActionRetry) InitialInterval() time.Duration {
+	return r.InitialIntervalValue
+}
+
+// BackoffCoefficient defaults to 1, a constant interval between attempts.
+func (r *ActionRetry) BackoffCoefficient() float64 {
+	if r.BackoffCoefficientValue <= 0 {
+		return 1
+	}
+
+	return r.BackoffCoefficientValue
+}
+
+func (r *ActionRetry) MaxInterval() time.Duration {
+	return r.MaxIntervalValue
+}
+
+// RetryNonIdempotent is always true, retrying an action is an explicit choice of the flow author.
+func (r *ActionRetry) RetryNonIdempotent() bool {
+	return true
+}
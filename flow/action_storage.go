@@ -0,0 +1,86 @@
+package flow
+
+type StorageOperation string
+
+const (
+	// StorageOperationCopy copies Source to Destination, both located in storages.
+	StorageOperationCopy StorageOperation = "copy"
+	// StorageOperationMove moves Source to Destination, both located in storages.
+	StorageOperationMove StorageOperation = "move"
+	// StorageOperationUpload uploads the local file Source to the storage Destination.
+	StorageOperationUpload StorageOperation = "upload"
+	// StorageOperationDownload downloads the storage Source to the local file Destination.
+	StorageOperationDownload StorageOperation = "download"
+	// StorageOperationDelete deletes Source from its storage.
+	StorageOperationDelete StorageOperation = "delete"
+)
+
+// StorageLocation is a path inside one of the storages passed with WithStorages, or a
+// local file when Storage is empty. Relative local paths are resolved against the
+// action work dir. Path is a template.
+type StorageLocation struct {
+	Storage string `json:"storage,omitempty" yaml:"storage,omitempty"`
+	Path    string `json:"path"              yaml:"path"`
+}
+
+type ActionStorage struct {
+	Operation   StorageOperation `json:"operation"             yaml:"operation"`
+	Source      StorageLocation  `json:"source"                yaml:"source"`
+	Destination *StorageLocation `json:"destination,omitempty" yaml:"destination,omitempty"`
+}
+
+func NewStorageLocation(storage string, path string) StorageLocation {
+	return StorageLocation{
+		Storage: storage,
+		Path:    path,
+	}
+}
+
+func NewLocalLocation(path string) StorageLocation {
+	return StorageLocation{
+		Storage: "",
+		Path:    path,
+	}
+}
+
+func NewActionStorageCopy(source StorageLocation, destination StorageLocation) ActionStorage {
+	return ActionStorage{
+		Operation:   StorageOperationCopy,
+		Source:      source,
+		Destination: &destination,
+	}
+}
+
+func NewActionStorageMove(source StorageLocation, destination StorageLocation) ActionStorage {
+	return ActionStorage{
+		Operation:   StorageOperationMove,
+		Source:      source,
+		Destination: &destination,
+	}
+}
+
+func NewActionStorageUpload(localPath string, destination StorageLocation) ActionStorage {
+	return ActionStorage{
+		Operation:   StorageOperationUpload,
+		Source:      NewLocalLocation(localPath),
+		Destination: &destination,
+	}
+}
+
+func NewActionStorageDownload(source StorageLocation, localPath string) ActionStorage {
+	destination := NewLocalLocation(localPath)
+
+	return ActionStorage{
+		Operation:   StorageOperationDownload,
+		Source:      source,
+		Destination: &destination,
+	}
+}
+
+func NewActionStorageDelete(location StorageLocation) ActionStorage {
+	return ActionStorage{
+		Operation:   StorageOperationDelete,
+		Source:      location,
+		Destination: nil,
+	}
+}
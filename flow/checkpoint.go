@@ -0,0 +1,326 @@
+package flow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixality-inc/golang-core/errors"
+	"github.com/pixality-inc/golang-core/json"
+	"github.com/pixality-inc/golang-core/storage"
+)
+
+const checkpointFileSuffix = ".json"
+
+var (
+	ErrNoCheckpointStore      = errors.New("flow.no_checkpoint_store", "no checkpoint store configured")
+	ErrCheckpointNotFound     = errors.New("flow.checkpoint_not_found", "checkpoint not found")
+	ErrCheckpointInvalidRunId = errors.New("flow.checkpoint_invalid_run_id", "invalid checkpoint run id")
+)
+
+// Checkpoint is the persisted state of a run: every action that succeeded so far
+// together with the hash of the inputs it ran with.
+type Checkpoint struct {
+	RunId     string                       `json:"run_id"`
+	InputHash string                       `json:"input_hash"`
+	Actions   map[string]*CheckpointAction `json:"actions"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+// CheckpointAction holds the responses and data of a succeeded action, for for_each
+// actions they include every instance.
+type CheckpointAction struct {
+	InputHash string                     `json:"input_hash"`
+	Responses map[string]*ActionResponse `json:"responses"`
+	Data      map[string]any             `json:"data"`
+}
+
+func NewCheckpoint(runId string, inputHash string) *Checkpoint {
+	return &Checkpoint{
+		RunId:     runId,
+		InputHash: inputHash,
+		Actions:   make(map[string]*CheckpointAction),
+		UpdatedAt: time.Time{},
+	}
+}
+
+type CheckpointStore interface {
+	// Load returns the checkpoint of the run, ErrCheckpointNotFound when there is none.
+	Load(ctx context.Context, runId string) (*Checkpoint, error)
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// FileCheckpointStore keeps checkpoints as json files in a local directory.
+type FileCheckpointStore struct {
+	dir string
+}
+
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{
+		dir: dir,
+	}
+}
+
+func (s *FileCheckpointStore) Load(_ context.Context, runId string) (*Checkpoint, error) {
+	if err := validateRunId(runId); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, runId+checkpointFileSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runId)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", runId, err)
+	}
+
+	return unmarshalCheckpoint(runId, data)
+}
+
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint *Checkpoint) error {
+	if err := validateRunId(checkpoint.RunId); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint %s: %w", checkpoint.RunId, err)
+	}
+
+	if err = os.MkdirAll(s.dir, 0o750); err != nil { // nolint:mnd
+		return fmt.Errorf("failed to create checkpoints dir %s: %w", s.dir, err)
+	}
+
+	filename := filepath.Join(s.dir, checkpoint.RunId+checkpointFileSuffix)
+	tmp := filename + ".tmp"
+
+	if err = os.WriteFile(tmp, data, 0o600); err != nil { // nolint:mnd
+		return fmt.Errorf("failed to write checkpoint %s: %w", checkpoint.RunId, err)
+	}
+
+	if err = os.Rename(tmp, filename); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to write checkpoint %s: %w", checkpoint.RunId, err)
+	}
+
+	return nil
+}
+
+// StorageCheckpointStore keeps checkpoints as json files under dir of a storage.Storage.
+type StorageCheckpointStore struct {
+	storage storage.Storage
+	dir     string
+}
+
+func NewStorageCheckpointStore(storage storage.Storage, dir string) *StorageCheckpointStore {
+	return &StorageCheckpointStore{
+		storage: storage,
+		dir:     dir,
+	}
+}
+
+func (s *StorageCheckpointStore) Load(ctx context.Context, runId string) (*Checkpoint, error) {
+	if err := validateRunId(runId); err != nil {
+		return nil, err
+	}
+
+	filename := path.Join(s.dir, runId+checkpointFileSuffix)
+
+	exists, err := s.storage.FileExists(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check checkpoint %s: %w", runId, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runId)
+	}
+
+	file, err := s.storage.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", runId, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", runId, err)
+	}
+
+	return unmarshalCheckpoint(runId, data)
+}
+
+func (s *StorageCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	if err := validateRunId(checkpoint.RunId); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint %s: %w", checkpoint.RunId, err)
+	}
+
+	if err = s.storage.Write(ctx, path.Join(s.dir, checkpoint.RunId+checkpointFileSuffix), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", checkpoint.RunId, err)
+	}
+
+	return nil
+}
+
+func validateRunId(runId string) error {
+	if runId == "" || runId == "." || runId == ".." || strings.ContainsAny(runId, `/\`) {
+		return fmt.Errorf("%w: '%s'", ErrCheckpointInvalidRunId, runId)
+	}
+
+	return nil
+}
+
+func unmarshalCheckpoint(runId string, data []byte) (*Checkpoint, error) {
+	checkpoint := NewCheckpoint(runId, "")
+
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint %s: %w", runId, err)
+	}
+
+	if checkpoint.Actions == nil {
+		checkpoint.Actions = make(map[string]*CheckpointAction)
+	}
+
+	return checkpoint, nil
+}
+
+// checkpointer records succeeded actions of a run and persists them after each one.
+type checkpointer struct {
+	store      CheckpointStore
+	checkpoint *Checkpoint
+	previous   map[string]*CheckpointAction
+	ancestors  map[string][]string
+	mutex      sync.Mutex
+}
+
+func newCheckpointer(store CheckpointStore, checkpoint *Checkpoint, previous map[string]*CheckpointAction, g *graph) *checkpointer {
+	return &checkpointer{
+		store:      store,
+		checkpoint: checkpoint,
+		previous:   previous,
+		ancestors:  g.ancestors(),
+		mutex:      sync.Mutex{},
+	}
+}
+
+// hashInputs hashes everything the flow starts from: the config and the run env.
+func hashInputs(config *Config, env *Env) string {
+	return hashJson(map[string]any{
+		"config": config,
+		"env":    env,
+	})
+}
+
+// actionInputHash hashes the action definition, the run env and the data of every
+// action it transitively depends on. It is empty when the inputs can't be hashed,
+// such actions are never restored.
+func (c *checkpointer) actionInputHash(state *run, action Action) string {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	dependencies := make(map[string]any, len(c.ancestors[action.Name]))
+
+	for _, name := range c.ancestors[action.Name] {
+		dependencies[name] = state.actions[name]
+	}
+
+	return hashJson(map[string]any{
+		"action":       action,
+		"env":          state.env,
+		"dependencies": dependencies,
+	})
+}
+
+// restore takes the action from the previous run when it succeeded there with the same inputs.
+func (c *checkpointer) restore(state *run, action Action, inputHash string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	previous, ok := c.previous[action.Name]
+	if !ok || inputHash == "" || previous.InputHash != inputHash {
+		return false
+	}
+
+	for name, response := range previous.Responses {
+		state.setResponse(name, response.WithRestored(true))
+	}
+
+	for name, data := range previous.Data {
+		if actionData, ok := data.(map[string]any); ok {
+			state.setActionData(name, actionData)
+		}
+	}
+
+	c.checkpoint.Actions[action.Name] = previous
+
+	return true
+}
+
+// save records the succeeded action with its instances and persists the checkpoint.
+func (c *checkpointer) save(ctx context.Context, state *run, action Action, inputHash string) error {
+	names := []string{action.Name}
+
+	state.mutex.Lock()
+
+	if response, ok := state.result.ActionsResponses[action.Name]; ok {
+		// failures of continue_on_error actions are not checkpointed, they run again on resume
+		if response.Failed {
+			state.mutex.Unlock()
+
+			return nil
+		}
+
+		names = append(names, response.Instances...)
+	}
+
+	entry := &CheckpointAction{
+		InputHash: inputHash,
+		Responses: make(map[string]*ActionResponse, len(names)),
+		Data:      make(map[string]any, len(names)),
+	}
+
+	for _, name := range names {
+		if response, ok := state.result.ActionsResponses[name]; ok {
+			entry.Responses[name] = response
+		}
+
+		if data, ok := state.actions[name]; ok {
+			entry.Data[name] = data
+		}
+	}
+
+	state.mutex.Unlock()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.checkpoint.Actions[action.Name] = entry
+	c.checkpoint.UpdatedAt = time.Now()
+
+	return c.store.Save(ctx, c.checkpoint)
+}
+
+func hashJson(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
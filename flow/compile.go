@@ -0,0 +1,40 @@
+package flow
+
+import "fmt"
+
+// compileActionScripts compiles the inline scripts of the action under the names
+// they are executed with, script files are only read when the action runs.
+func (f *Impl) compileActionScripts(compiler ScriptCompiler, action Action) error {
+	prefix := "action." + action.Name + "."
+
+	scripts := map[string]string{
+		prefix + "when":        action.When,
+		prefix + "env_script":  action.EnvScript,
+		prefix + "args_script": action.ArgsScript,
+		prefix + "script":      action.Script,
+	}
+
+	if action.Trigger != nil {
+		scripts[prefix+"trigger.data_script"] = action.Trigger.DataScript
+	}
+
+	if action.Result != nil {
+		scripts[prefix+"result.data_script"] = action.Result.DataScript
+	}
+
+	if action.ForEach != nil {
+		scripts[prefix+"for_each.script"] = action.ForEach.Script
+	}
+
+	for name, script := range scripts {
+		if script == "" {
+			continue
+		}
+
+		if err := compiler.Compile(name, script); err != nil {
+			return fmt.Errorf("compile %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
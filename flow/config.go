@@ -1,5 +1,207 @@
 package flow
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type InputType string
+
+const (
+	InputTypeAny     InputType = ""
+	InputTypeString  InputType = "string"
+	InputTypeNumber  InputType = "number"
+	InputTypeInteger InputType = "integer"
+	InputTypeBoolean InputType = "boolean"
+	InputTypeList    InputType = "list"
+	InputTypeMap     InputType = "map"
+)
+
 type Config struct {
 	Actions []Action `yaml:"actions"`
+	// MaxParallel limits how many independent actions run at once when actions
+	// declare depends_on, zero means no limit.
+	MaxParallel int `yaml:"max_parallel,omitempty"`
+	// Inputs declares the parameters of a flow used as a sub-flow, they are
+	// available to its templates and scripts under their names.
+	Inputs map[string]ConfigInput `yaml:"inputs,omitempty"`
+	// Outputs are templates evaluated once a sub-flow finished, they become the
+	// outputs of the including action.
+	Outputs map[string]string `yaml:"outputs,omitempty"`
+	// Path is the file the config was loaded from, relative includes are resolved against it.
+	Path string `yaml:"-"`
+}
+
+type ConfigInput struct {
+	Type        InputType `json:"type,omitempty"        yaml:"type,omitempty"`
+	Required    bool      `json:"required,omitempty"    yaml:"required,omitempty"`
+	Default     any       `json:"default,omitempty"     yaml:"default,omitempty"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// LoadConfig reads a flow config from a yaml file.
+func LoadConfig(filename string) (*Config, error) {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, fmt.Errorf("abs %s: %w", filename, err)
+	}
+
+	data, err := os.ReadFile(absFilename)
+	if err != nil {
+		return nil, fmt.Errorf("read flow config %s: %w", absFilename, err)
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("flow config %s: %w", absFilename, err)
+	}
+
+	config.Path = absFilename
+
+	return config, nil
+}
+
+// ParseConfig strictly decodes a yaml flow config. Every unknown field is reported
+// as a *ConfigError with its line and column.
+func ParseConfig(data []byte) (*Config, error) {
+	var document yaml.Node
+
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+	}
+
+	config := new(Config)
+
+	if len(document.Content) == 0 {
+		return config, nil
+	}
+
+	root := document.Content[0]
+
+	if errs := checkConfigNode(root, reflect.TypeFor[Config](), ""); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := root.Decode(config); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+	}
+
+	return config, nil
+}
+
+// ConfigError is a problem at a position of a yaml flow config.
+type ConfigError struct {
+	Line   int
+	Column int
+	Path   string
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s: %v", e.Line, e.Column, e.Path, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// checkConfigNode walks the yaml node along the config type and reports the
+// mapping keys that are not fields of the struct they are decoded into.
+func checkConfigNode(node *yaml.Node, valueType reflect.Type, path string) []error {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	errs := make([]error, 0)
+
+	switch {
+	case valueType.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(valueType)
+
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key, value := node.Content[index], node.Content[index+1]
+
+			field, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, &ConfigError{
+					Line:   key.Line,
+					Column: key.Column,
+					Path:   joinConfigPath(path, key.Value),
+					Err:    ErrConfigUnknownField,
+				})
+
+				continue
+			}
+
+			errs = append(errs, checkConfigNode(value, field, joinConfigPath(path, key.Value))...)
+		}
+
+	case valueType.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key, value := node.Content[index], node.Content[index+1]
+
+			errs = append(errs, checkConfigNode(value, valueType.Elem(), joinConfigPath(path, key.Value))...)
+		}
+
+	case valueType.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for index, value := range node.Content {
+			errs = append(errs, checkConfigNode(value, valueType.Elem(), path+"["+strconv.Itoa(index)+"]")...)
+		}
+	}
+
+	return errs
+}
+
+// yamlFields maps the yaml names of the struct fields to their types.
+func yamlFields(structType reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, structType.NumField())
+
+	for index := range structType.NumField() {
+		field := structType.Field(index)
+
+		name := yamlFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func yamlFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(field.Name)
+	default:
+		return name
+	}
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
 }
@@ -0,0 +1,220 @@
+package flow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/errors"
+)
+
+var (
+	ErrActionUnknownDependency = errors.New("flow.action_unknown_dependency", "action depends on unknown action")
+	ErrActionDependencyCycle   = errors.New("flow.action_dependency_cycle", "action dependencies contain a cycle")
+)
+
+// graph is the execution order of the flow actions, nodes are indexes in Config.Actions.
+type graph struct {
+	actions    []Action
+	dependents [][]int
+	indegree   []int
+}
+
+func hasDependencies(actions []Action) bool {
+	for _, action := range actions {
+		if len(action.DependsOn) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildGraph links actions by their depends_on. Flows without any dependency
+// keep the declaration order by chaining every action to the previous one.
+func buildGraph(actions []Action) (*graph, error) {
+	g := &graph{
+		actions:    actions,
+		dependents: make([][]int, len(actions)),
+		indegree:   make([]int, len(actions)),
+	}
+
+	if !hasDependencies(actions) {
+		for index := 1; index < len(actions); index++ {
+			g.link(index-1, index)
+		}
+
+		return g, nil
+	}
+
+	indexes := make(map[string]int, len(actions))
+
+	for index, action := range actions {
+		indexes[action.Name] = index
+	}
+
+	for index, action := range actions {
+		for _, dependency := range action.DependsOn {
+			dependencyIndex, ok := indexes[dependency]
+			if !ok {
+				return nil, fmt.Errorf("%w: '%s' depends on '%s'", ErrActionUnknownDependency, action.Name, dependency)
+			}
+
+			g.link(dependencyIndex, index)
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		names := make([]string, len(cycle))
+
+		for index, node := range cycle {
+			names[index] = actions[node].Name
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrActionDependencyCycle, strings.Join(names, " -> "))
+	}
+
+	return g, nil
+}
+
+func (g *graph) link(from int, to int) {
+	g.dependents[from] = append(g.dependents[from], to)
+	g.indegree[to]++
+}
+
+// roots returns actions without dependencies in declaration order.
+func (g *graph) roots() []int {
+	roots := make([]int, 0)
+
+	for index, degree := range g.indegree {
+		if degree == 0 {
+			roots = append(roots, index)
+		}
+	}
+
+	return roots
+}
+
+// order returns the actions in the order a run with a single action at a time would start them.
+func (g *graph) order() []int {
+	remaining := make([]int, len(g.indegree))
+	copy(remaining, g.indegree)
+
+	order := make([]int, 0, len(g.actions))
+	queue := g.roots()
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		order = append(order, node)
+
+		for _, next := range g.dependents[node] {
+			remaining[next]--
+
+			if remaining[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return order
+}
+
+// ancestors returns the names of every action each action transitively depends on.
+func (g *graph) ancestors() map[string][]string {
+	sets := make([]map[int]struct{}, len(g.actions))
+	remaining := make([]int, len(g.indegree))
+	copy(remaining, g.indegree)
+
+	queue := g.roots()
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.dependents[node] {
+			if sets[next] == nil {
+				sets[next] = make(map[int]struct{})
+			}
+
+			sets[next][node] = struct{}{}
+
+			for ancestor := range sets[node] {
+				sets[next][ancestor] = struct{}{}
+			}
+
+			remaining[next]--
+
+			if remaining[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	ancestors := make(map[string][]string, len(g.actions))
+
+	for node, set := range sets {
+		names := make([]string, 0, len(set))
+
+		for ancestor := range set {
+			names = append(names, g.actions[ancestor].Name)
+		}
+
+		slices.Sort(names)
+
+		ancestors[g.actions[node].Name] = names
+	}
+
+	return ancestors
+}
+
+// findCycle returns the nodes of the first cycle found, with the first node repeated at the end.
+func (g *graph) findCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(g.actions))
+	stack := make([]int, 0, len(g.actions))
+
+	var visit func(node int) []int
+
+	visit = func(node int) []int {
+		state[node] = visiting
+		stack = append(stack, node)
+
+		for _, next := range g.dependents[node] {
+			switch state[next] {
+			case visiting:
+				for position, stacked := range stack {
+					if stacked == next {
+						return append(append([]int{}, stack[position:]...), next)
+					}
+				}
+
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[node] = visited
+
+		return nil
+	}
+
+	for node := range g.actions {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
@@ -49,6 +49,7 @@ func NewEnv(workDir string, context map[string]any, options ...EnvOption) *Env {
 }
 
 type Result struct {
+	RunId            string
 	ActionsResponses map[string]*ActionResponse
 	Data             map[string]any
 }
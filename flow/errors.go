@@ -3,13 +3,40 @@ package flow
 import "errors"
 
 var (
-	ErrNoScriptDriver              = errors.New("no script driver")
-	ErrActionNoOptions             = errors.New("no options provided")
-	ErrActionTooManyOptions        = errors.New("too many options for action provided")
-	ErrActionTriggerTooManyOptions = errors.New("too many options for action trigger provided")
-	ErrActionArgsTooManyOptions    = errors.New("too many options for action args provided")
-	ErrActionEnvTooManyOptions     = errors.New("too many options for action env provided")
-	ErrCommandFailed               = errors.New("command failed")
-	ErrAsMapStringString           = errors.New("asMapStringString")
-	ErrUnmarshalResultObject       = errors.New("unmarshal result object failed")
+	ErrConfigInvalid                      = errors.New("invalid flow config")
+	ErrConfigUnknownField                 = errors.New("unknown field")
+	ErrNoScriptDriver                     = errors.New("no script driver")
+	ErrActionNoOptions                    = errors.New("no options provided")
+	ErrActionTooManyOptions               = errors.New("too many options for action provided")
+	ErrActionTriggerTooManyOptions        = errors.New("too many options for action trigger provided")
+	ErrActionArgsTooManyOptions           = errors.New("too many options for action args provided")
+	ErrActionEnvTooManyOptions            = errors.New("too many options for action env provided")
+	ErrCommandFailed                      = errors.New("command failed")
+	ErrAsMapStringString                  = errors.New("asMapStringString")
+	ErrUnmarshalResultObject              = errors.New("unmarshal result object failed")
+	ErrActionTimeout                      = errors.New("action timed out")
+	ErrActionInvalidTimeout               = errors.New("invalid action timeout")
+	ErrActionInvalidRetryAttempts         = errors.New("invalid action retry attempts")
+	ErrActionStdoutNotJson                = errors.New("action stdout is not valid json")
+	ErrActionForEachNoOptions             = errors.New("no items provided for action for_each")
+	ErrActionForEachTooManyOptions        = errors.New("too many options for action for_each provided")
+	ErrNoHttpClient                       = errors.New("no http client")
+	ErrActionHttpNoUrl                    = errors.New("no url provided for action http")
+	ErrActionHttpInvalidMethod            = errors.New("invalid action http method")
+	ErrActionHttpBodyTooManyOptions       = errors.New("too many options for action http body provided")
+	ErrActionHttpInvalidExpectedStatus    = errors.New("invalid action http expected status")
+	ErrActionHttpFailed                   = errors.New("http request failed")
+	ErrActionHttpUnexpectedStatus         = errors.New("http request returned unexpected status")
+	ErrActionStorageInvalidOperation      = errors.New("invalid action storage operation")
+	ErrActionStorageNoPath                = errors.New("no path provided for action storage location")
+	ErrActionStorageNoStorage             = errors.New("no storage provided for action storage location")
+	ErrActionStorageNotLocal              = errors.New("action storage location must be a local path")
+	ErrActionStorageUnknownStorage        = errors.New("unknown storage in action storage location")
+	ErrActionStorageNoDestination         = errors.New("no destination provided for action storage")
+	ErrActionStorageUnexpectedDestination = errors.New("action storage delete does not take a destination")
+	ErrActionIncludeNoPath                = errors.New("no path provided for action include")
+	ErrActionIncludeRecursion             = errors.New("action include is recursive")
+	ErrActionIncludeUnknownInput          = errors.New("unknown action include input")
+	ErrActionIncludeMissingInput          = errors.New("missing required action include input")
+	ErrActionIncludeInvalidInput          = errors.New("invalid action include input")
 )
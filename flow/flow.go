@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/google/uuid"
 	"github.com/pixality-inc/golang-core/cli"
 	"github.com/pixality-inc/golang-core/errors"
+	httpClient "github.com/pixality-inc/golang-core/http_client"
 	"github.com/pixality-inc/golang-core/json"
 	"github.com/pixality-inc/golang-core/logger"
 	"github.com/pixality-inc/golang-core/storage"
@@ -30,6 +33,8 @@ var (
 type Flow interface {
 	Validate(ctx context.Context) error
 	Run(ctx context.Context, env *Env) (*Result, error)
+	Resume(ctx context.Context, runId string, env *Env) (*Result, error)
+	Plan(ctx context.Context, env *Env) (*Plan, error)
 	EvalTemplate(ctx context.Context, env *Env, name string, source string) (string, error)
 	EvalScript(ctx context.Context, env *Env, name string, script string) (any, error)
 	ValueToString(value any) (string, error)
@@ -42,14 +47,19 @@ type Flow interface {
 }
 
 type Impl struct {
-	log            logger.Loggable
-	config         *Config
-	storage        storage.Storage
-	templateDriver TemplateDriver
-	scriptDriver   ScriptDriver
-	triggers       map[string]ActionTriggerFunc
-	logsDir        *string
-	logFilePrefix  *string
+	log             logger.Loggable
+	config          *Config
+	storage         storage.Storage
+	templateDriver  TemplateDriver
+	scriptDriver    ScriptDriver
+	triggers        map[string]ActionTriggerFunc
+	logsDir         *string
+	logFilePrefix   *string
+	checkpointStore CheckpointStore
+	httpClient      httpClient.Client
+	storages        map[string]storage.Storage
+	observers       []Observer
+	includedBy      []string
 }
 
 func New(
@@ -65,14 +75,19 @@ func New(
 	}
 
 	flowEngine := &Impl{
-		log:            logger.NewLoggableImplWithService("flow"),
-		config:         config,
-		storage:        storage,
-		templateDriver: templateDriver,
-		scriptDriver:   scriptDriver,
-		triggers:       triggers,
-		logsDir:        nil,
-		logFilePrefix:  nil,
+		log:             logger.NewLoggableImplWithService("flow"),
+		config:          config,
+		storage:         storage,
+		templateDriver:  templateDriver,
+		scriptDriver:    scriptDriver,
+		triggers:        triggers,
+		logsDir:         nil,
+		logFilePrefix:   nil,
+		checkpointStore: nil,
+		httpClient:      nil,
+		storages:        nil,
+		observers:       nil,
+		includedBy:      nil,
 	}
 
 	for _, opt := range options {
@@ -82,7 +97,7 @@ func New(
 	return flowEngine
 }
 
-func (f *Impl) Validate(_ context.Context) error {
+func (f *Impl) Validate(ctx context.Context) error {
 	actionsNames := make(map[string]struct{})
 
 	for actionIndex, action := range f.config.Actions {
@@ -95,14 +110,84 @@ func (f *Impl) Validate(_ context.Context) error {
 		}
 
 		actionsNames[action.Name] = struct{}{}
+
+		if err := f.validateAction(ctx, action); err != nil {
+			return fmt.Errorf("%w: %s", err, action.Name)
+		}
+	}
+
+	if _, err := buildGraph(f.config.Actions); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (f *Impl) validateAction(ctx context.Context, action Action) error {
+	if err := f.validateActionOptions(action); err != nil {
+		return err
+	}
+
+	if compiler, ok := f.scriptDriver.(ScriptCompiler); ok {
+		if err := f.compileActionScripts(compiler, action); err != nil {
+			return err
+		}
+	}
+
+	if action.ForEach != nil {
+		if err := validateForEach(action.ForEach); err != nil {
+			return err
+		}
+	}
+
+	if action.Http != nil {
+		if err := f.validateActionHttp(action); err != nil {
+			return err
+		}
+	}
+
+	if action.Storage != nil {
+		if err := f.validateActionStorage(action); err != nil {
+			return err
+		}
+	}
+
+	if action.Include != nil {
+		if err := f.validateActionInclude(ctx, action); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (f *Impl) Run(ctx context.Context, env *Env) (*Result, error) {
-	log := f.log.GetLogger(ctx)
+	result, _, err := f.run(ctx, uuid.New().String(), env, nil)
+
+	return result, err
+}
+
+// Resume continues a failed run from its checkpoint. Actions that succeeded in the
+// previous run are not executed again while their inputs are unchanged, their
+// responses are restored and marked with Restored.
+func (f *Impl) Resume(ctx context.Context, runId string, env *Env) (*Result, error) {
+	if f.checkpointStore == nil {
+		return nil, ErrNoCheckpointStore
+	}
+
+	checkpoint, err := f.checkpointStore.Load(ctx, runId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := f.run(ctx, runId, env, checkpoint)
 
+	return result, err
+}
+
+// run executes the flow and returns the result with the final run state,
+// the state is nil when the actions graph is invalid.
+func (f *Impl) run(ctx context.Context, runId string, env *Env, previous *Checkpoint) (*Result, *run, error) {
 	if env == nil {
 		env = &Env{
 			WorkDir: "",
@@ -111,37 +196,61 @@ func (f *Impl) Run(ctx context.Context, env *Env) (*Result, error) {
 	}
 
 	result := &Result{
+		RunId:            runId,
 		ActionsResponses: make(map[string]*ActionResponse, len(f.config.Actions)),
 	}
 
-	for _, action := range f.config.Actions {
-		track := timetrack.New(ctx)
+	f.notify(ctx, newEvent(EventFlowStarted, runId, ""))
 
-		actionResponse, err := f.runAction(ctx, env, result, action)
-		if err != nil {
-			if actionResponse != nil {
-				duration := track.Finish()
+	track := timetrack.New(ctx)
 
-				result.ActionsResponses[action.Name] = actionResponse.
-					WithStartedAt(track.Start).
-					WithFinishedAt(track.End).
-					WithDuration(duration)
-			}
+	state, err := f.runActions(ctx, env, result, previous)
 
-			return result, fmt.Errorf("action '%s' failed in %s: %w", action.Name, util.FormatDuration(track.Finish()), err)
-		}
+	event := newEvent(EventFlowFinished, runId, "")
+	event.Duration = track.Finish()
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	f.notify(ctx, event)
+
+	return result, state, err
+}
+
+func (f *Impl) runActions(ctx context.Context, env *Env, result *Result, previous *Checkpoint) (*run, error) {
+	log := f.log.GetLogger(ctx)
+	runId := result.RunId
+
+	g, err := buildGraph(f.config.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &run{
+		env:          env,
+		result:       result,
+		actions:      make(map[string]any, len(f.config.Actions)),
+		checkpointer: nil,
+		mutex:        sync.Mutex{},
+	}
+
+	if f.checkpointStore != nil {
+		inputHash := hashInputs(f.config, env)
+		previousActions := make(map[string]*CheckpointAction)
 
-		duration := track.Finish()
+		if previous != nil {
+			if previous.InputHash != inputHash {
+				log.Infof("Inputs of run '%s' changed since the checkpoint, only unaffected actions are restored", runId)
+			}
 
-		log.Debugf("Action '%s' executed in %s", action.Name, util.FormatDuration(duration))
+			previousActions = previous.Actions
+		}
 
-		result.ActionsResponses[action.Name] = actionResponse.
-			WithStartedAt(track.Start).
-			WithFinishedAt(track.End).
-			WithDuration(duration)
+		state.checkpointer = newCheckpointer(f.checkpointStore, NewCheckpoint(runId, inputHash), previousActions, g)
 	}
 
-	return result, nil
+	return state, f.schedule(ctx, state, g)
 }
 
 func (f *Impl) EvalTemplate(ctx context.Context, env *Env, name string, source string) (string, error) {
@@ -180,14 +289,21 @@ func (f *Impl) Throw(err error) {
 	f.scriptDriver.Throw(err)
 }
 
-func (f *Impl) runAction(ctx context.Context, env *Env, result *Result, action Action) (*ActionResponse, error) {
+func (f *Impl) runAction(ctx context.Context, env *Env, state *run, action Action) (*ActionResponse, error) {
 	hasTrigger := action.Trigger != nil
 	hasResult := action.Result != nil
 	hasCommand := action.Command != ""
 	hasScript := action.Script != ""
 	hasScriptFile := action.ScriptFile != ""
+	hasHttp := action.Http != nil
+	hasStorage := action.Storage != nil
+	hasInclude := action.Include != nil
 
-	optionsSum := util.SliceSum([]bool{hasTrigger, hasResult, hasCommand, hasScript, hasScriptFile}, 0, boolInc)
+	optionsSum := util.SliceSum(
+		[]bool{hasTrigger, hasResult, hasCommand, hasScript, hasScriptFile, hasHttp, hasStorage, hasInclude},
+		0,
+		boolInc,
+	)
 
 	if optionsSum <= 0 {
 		return nil, ErrActionNoOptions
@@ -208,7 +324,7 @@ func (f *Impl) runAction(ctx context.Context, env *Env, result *Result, action A
 		return f.runActionTrigger(ctx, env, action)
 
 	case hasResult:
-		return f.runActionResult(ctx, env, result, action)
+		return f.runActionResult(ctx, env, state, action)
 
 	case hasCommand:
 		return f.runActionCommand(ctx, env, action)
@@ -218,12 +334,21 @@ func (f *Impl) runAction(ctx context.Context, env *Env, result *Result, action A
 
 	case hasScriptFile:
 		return f.runActionScriptFile(ctx, env, action)
+
+	case hasHttp:
+		return f.runActionHttp(ctx, env, action)
+
+	case hasStorage:
+		return f.runActionStorage(ctx, env, action)
+
+	case hasInclude:
+		return f.runActionInclude(ctx, env, state, action)
 	}
 
 	return nil, util.ErrNotImplemented
 }
 
-func (f *Impl) runActionResult(ctx context.Context, env *Env, flowResult *Result, action Action) (*ActionResponse, error) {
+func (f *Impl) runActionResult(ctx context.Context, env *Env, state *run, action Action) (*ActionResponse, error) {
 	dataScript := action.Result.DataScript
 	if dataScript == "" {
 		return NewActionResponse(), nil
@@ -239,7 +364,9 @@ func (f *Impl) runActionResult(ctx context.Context, env *Env, flowResult *Result
 		return nil, fmt.Errorf("result data jsValue to map[string]any: %w", err)
 	}
 
-	flowResult.Data = data
+	state.mutex.Lock()
+	state.result.Data = data
+	state.mutex.Unlock()
 
 	return NewActionResponse(), nil
 }
@@ -320,93 +447,31 @@ func (f *Impl) runActionCommand(ctx context.Context, env *Env, action Action) (*
 
 	failIfNonZeroCode := util.OrDefault(action.FailIfNonZeroCode, true)
 
-	command := action.Command
-
-	if command != "" {
-		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".command", command)
-		if err != nil {
-			return nil, fmt.Errorf("eval template for action '%s' command: %w", action.Name, err)
-		}
-
-		command = evalResult
-	}
-
-	workDir := action.WorkDir
-
-	if workDir != "" {
-		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".work_dir", workDir)
-		if err != nil {
-			return nil, fmt.Errorf("eval template for action '%s' work dir: %w", action.Name, err)
-		}
-
-		workDir = evalResult
+	resolved, err := f.resolveCommand(ctx, env, action)
+	if err != nil {
+		return nil, err
 	}
 
-	if workDir == "" {
-		workDir = env.WorkDir
-	}
+	command := resolved.Command
+	workDir := resolved.WorkDir
+	commandStdout := resolved.Stdout
+	commandStderr := resolved.Stderr
+	cmdArgs := resolved.Args
 
 	if workDir != "" {
-		if err := os.MkdirAll(workDir, os.ModePerm); err != nil {
+		if err = os.MkdirAll(workDir, os.ModePerm); err != nil {
 			return nil, fmt.Errorf("mkdir %s: %w", workDir, err)
 		}
 	}
 
-	commandStdout := action.Stdout
-
-	if commandStdout != "" {
-		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".stdout", commandStdout)
-		if err != nil {
-			return nil, fmt.Errorf("eval template for action '%s' stdout: %w", action.Name, err)
-		}
-
-		commandStdout = evalResult
-	}
-
-	if commandStdout != "" && !strings.HasPrefix(commandStdout, "/") {
-		commandStdout = path.Join(workDir, commandStdout)
-	}
-
-	commandStderr := action.Stderr
-
-	if commandStderr != "" {
-		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".stderr", commandStderr)
-		if err != nil {
-			return nil, fmt.Errorf("eval template for action '%s' stderr: %w", action.Name, err)
-		}
-
-		commandStderr = evalResult
-	}
-
-	if commandStderr != "" && !strings.HasPrefix(commandStderr, "/") {
-		commandStderr = path.Join(workDir, commandStderr)
-	}
-
-	cmdArgs, err := f.getActionArgs(ctx, env, action)
-	if err != nil {
-		return nil, fmt.Errorf("get action '%s' arguments: %w", action.Name, err)
-	}
-
-	cmdEnvs, err := f.getActionEnv(ctx, env, action)
-	if err != nil {
-		return nil, fmt.Errorf("get action '%s' environment: %w", action.Name, err)
-	}
-
 	cliOptions := make([]cli.Option, 0)
 
 	if workDir != "" {
 		cliOptions = append(cliOptions, cli.WithWorkDir(workDir))
 	}
 
-	hasEnv := len(cmdEnvs) > 0 || len(env.Env) > 0
-
-	if hasEnv {
-		envsMap := make(map[string]string)
-
-		maps.Copy(envsMap, env.Env)
-		maps.Copy(envsMap, cmdEnvs)
-
-		cliOptions = append(cliOptions, cli.WithEnvs(envsMap))
+	if resolved.Env != nil {
+		cliOptions = append(cliOptions, cli.WithEnvs(resolved.Env))
 	}
 
 	if f.logsDir != nil {
@@ -501,6 +566,112 @@ func (f *Impl) runActionCommand(ctx context.Context, env *Env, action Action) (*
 	return response, nil
 }
 
+// resolvedCommand is a command action with every template and script evaluated.
+type resolvedCommand struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+	WorkDir string
+	Stdout  string
+	Stderr  string
+}
+
+func (f *Impl) resolveCommand(ctx context.Context, env *Env, action Action) (*resolvedCommand, error) {
+	command := action.Command
+
+	if command != "" {
+		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".command", command)
+		if err != nil {
+			return nil, fmt.Errorf("eval template for action '%s' command: %w", action.Name, err)
+		}
+
+		command = evalResult
+	}
+
+	workDir, err := f.actionWorkDir(ctx, env, action)
+	if err != nil {
+		return nil, err
+	}
+
+	commandStdout := action.Stdout
+
+	if commandStdout != "" {
+		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".stdout", commandStdout)
+		if err != nil {
+			return nil, fmt.Errorf("eval template for action '%s' stdout: %w", action.Name, err)
+		}
+
+		commandStdout = evalResult
+	}
+
+	if commandStdout != "" && !strings.HasPrefix(commandStdout, "/") {
+		commandStdout = path.Join(workDir, commandStdout)
+	}
+
+	commandStderr := action.Stderr
+
+	if commandStderr != "" {
+		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".stderr", commandStderr)
+		if err != nil {
+			return nil, fmt.Errorf("eval template for action '%s' stderr: %w", action.Name, err)
+		}
+
+		commandStderr = evalResult
+	}
+
+	if commandStderr != "" && !strings.HasPrefix(commandStderr, "/") {
+		commandStderr = path.Join(workDir, commandStderr)
+	}
+
+	cmdArgs, err := f.getActionArgs(ctx, env, action)
+	if err != nil {
+		return nil, fmt.Errorf("get action '%s' arguments: %w", action.Name, err)
+	}
+
+	cmdEnvs, err := f.getActionEnv(ctx, env, action)
+	if err != nil {
+		return nil, fmt.Errorf("get action '%s' environment: %w", action.Name, err)
+	}
+
+	var envsMap map[string]string
+
+	if len(cmdEnvs) > 0 || len(env.Env) > 0 {
+		envsMap = make(map[string]string)
+
+		maps.Copy(envsMap, env.Env)
+		maps.Copy(envsMap, cmdEnvs)
+	}
+
+	return &resolvedCommand{
+		Command: command,
+		Args:    cmdArgs,
+		Env:     envsMap,
+		WorkDir: workDir,
+		Stdout:  commandStdout,
+		Stderr:  commandStderr,
+	}, nil
+}
+
+// actionWorkDir evaluates the action work dir, defaulting to the env work dir.
+func (f *Impl) actionWorkDir(ctx context.Context, env *Env, action Action) (string, error) {
+	workDir := action.WorkDir
+
+	if workDir != "" {
+		evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".work_dir", workDir)
+		if err != nil {
+			return "", fmt.Errorf("eval template for action '%s' work dir: %w", action.Name, err)
+		}
+
+		workDir = evalResult
+	}
+
+	if workDir == "" {
+		workDir = env.WorkDir
+	}
+
+	return workDir, nil
+}
+
 func (f *Impl) actionWhen(ctx context.Context, env *Env, action Action) (bool, error) {
 	if action.When == "" {
 		return true, nil
@@ -728,7 +899,8 @@ func (f *Impl) evalTemplate(ctx context.Context, env *Env, name string, source s
 	return f.templateDriver.Execute(ctx, env, name, source)
 }
 
-//nolint:unused
+// evalScript runs the script with the configured driver, actions running in parallel
+// evaluate concurrently, so drivers sharing state must lock it themselves.
 func (f *Impl) evalScript(ctx context.Context, env *Env, name string, source string) (any, error) {
 	if f.scriptDriver == nil {
 		return nil, ErrNoScriptDriver
@@ -0,0 +1,293 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/concurrency"
+	"github.com/pixality-inc/golang-core/timetrack"
+	"github.com/pixality-inc/golang-core/util"
+)
+
+// Context keys available to the templates and scripts of for_each instances.
+const (
+	ItemContextKey   = "item"
+	IndexContextKey  = "index"
+	MatrixContextKey = "matrix"
+)
+
+const actionDataInstances = "instances"
+
+type actionInstance struct {
+	action Action
+	env    *Env
+}
+
+func validateForEach(forEach *ActionForEach) error {
+	optionsSum := util.SliceSum(
+		[]bool{forEach.Items != nil, forEach.Template != "", forEach.Script != "", forEach.Matrix != nil},
+		0,
+		boolInc,
+	)
+
+	if optionsSum <= 0 {
+		return ErrActionForEachNoOptions
+	}
+
+	if optionsSum > 1 {
+		return ErrActionForEachTooManyOptions
+	}
+
+	return nil
+}
+
+// executeForEach runs one instance of the action per for_each item, then records an
+// aggregated response and data under the action name for the dependent actions.
+func (f *Impl) executeForEach(ctx context.Context, state *run, action Action) error {
+	log := f.log.GetLogger(ctx)
+
+	f.notifyAction(ctx, state, EventActionStarted, action.Name)
+
+	track := timetrack.New(ctx)
+
+	instances, err := f.expandForEach(ctx, state.envFor(), action)
+	if err != nil {
+		f.notifyActionFinished(ctx, state, action.Name, nil, err)
+
+		return fmt.Errorf("action '%s' failed in %s: %w", action.Name, util.FormatDuration(track.Finish()), err)
+	}
+
+	log.Debugf("Action '%s' expanded to %d instances", action.Name, len(instances))
+
+	runInstance := func(ctx context.Context, instance actionInstance) error {
+		return f.executeActionWithEnv(ctx, state, instance.action, instance.env)
+	}
+
+	if action.ForEach.Parallel {
+		err = concurrency.ParallelForEach(ctx, instances, runInstance, concurrency.WithLimit(action.ForEach.MaxParallel))
+	} else {
+		for _, instance := range instances {
+			if err = runInstance(ctx, instance); err != nil {
+				break
+			}
+		}
+	}
+
+	duration := track.Finish()
+
+	response, data := state.aggregateInstances(action, instances)
+
+	state.setResponse(action.Name, response.
+		WithError(err).
+		WithStartedAt(track.Start).
+		WithFinishedAt(track.End).
+		WithDuration(duration))
+
+	f.notifyActionFinished(ctx, state, action.Name, response, err)
+
+	if err != nil {
+		return fmt.Errorf("action '%s' failed in %s: %w", action.Name, util.FormatDuration(duration), err)
+	}
+
+	state.setActionData(action.Name, data)
+
+	return nil
+}
+
+// expandForEach evaluates the items of the action and builds its instances. Instances
+// are named after the action with the item index or matrix values, e.g. build[0]
+// or build[os=linux,arch=amd64] with matrix keys in alphabetical order.
+func (f *Impl) expandForEach(ctx context.Context, env *Env, action Action) ([]actionInstance, error) {
+	forEach := action.ForEach
+
+	if err := validateForEach(forEach); err != nil {
+		return nil, err
+	}
+
+	if forEach.Matrix != nil {
+		return expandMatrix(env, action), nil
+	}
+
+	items, err := f.forEachItems(ctx, env, action)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]actionInstance, len(items))
+
+	for index, item := range items {
+		instances[index] = newActionInstance(
+			action,
+			action.Name+"["+strconv.Itoa(index)+"]",
+			env.withContext(map[string]any{ItemContextKey: item, IndexContextKey: index}),
+		)
+	}
+
+	return instances, nil
+}
+
+func (f *Impl) forEachItems(ctx context.Context, env *Env, action Action) ([]any, error) {
+	forEach := action.ForEach
+
+	switch {
+	case forEach.Template != "":
+		templateName := "action." + action.Name + ".for_each.template"
+
+		evalResult, err := f.evalTemplate(ctx, env, templateName, forEach.Template)
+		if err != nil {
+			return nil, fmt.Errorf("eval for_each template %s: %w", templateName, err)
+		}
+
+		items, err := UnmarshalTemplateResultSlice(evalResult)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal for_each template %s: %w", templateName, err)
+		}
+
+		return util.MapSimple(items, func(item string) any { return item }), nil
+
+	case forEach.Script != "":
+		templateName := "action." + action.Name + ".for_each.script"
+
+		evalResult, err := f.evalScript(ctx, env, templateName, forEach.Script)
+		if err != nil {
+			return nil, fmt.Errorf("eval for_each script %s: %w", templateName, err)
+		}
+
+		items, err := f.scriptDriver.ValueToStringSlice(evalResult)
+		if err != nil {
+			return nil, fmt.Errorf("eval for_each script %s result to string slice: %w", templateName, err)
+		}
+
+		return util.MapSimple(items, func(item string) any { return item }), nil
+
+	default:
+		return forEach.Items, nil
+	}
+}
+
+func expandMatrix(env *Env, action Action) []actionInstance {
+	keys := slices.Sorted(maps.Keys(action.ForEach.Matrix))
+	combinations := []map[string]any{{}}
+
+	for _, key := range keys {
+		next := make([]map[string]any, 0, len(combinations)*len(action.ForEach.Matrix[key]))
+
+		for _, combination := range combinations {
+			for _, value := range action.ForEach.Matrix[key] {
+				extended := maps.Clone(combination)
+				extended[key] = value
+
+				next = append(next, extended)
+			}
+		}
+
+		combinations = next
+	}
+
+	instances := make([]actionInstance, len(combinations))
+
+	for index, combination := range combinations {
+		parts := make([]string, len(keys))
+
+		for keyIndex, key := range keys {
+			parts[keyIndex] = fmt.Sprintf("%s=%v", key, combination[key])
+		}
+
+		instances[index] = newActionInstance(
+			action,
+			action.Name+"["+strings.Join(parts, ",")+"]",
+			env.withContext(map[string]any{MatrixContextKey: combination, IndexContextKey: index}),
+		)
+	}
+
+	return instances
+}
+
+// actionBaseName strips the instance suffix of for_each and matrix instances,
+// "encode[0]" and "encode[codec=h264,size=720]" are both instances of "encode".
+func actionBaseName(name string) string {
+	if !strings.HasSuffix(name, "]") {
+		return name
+	}
+
+	if index := strings.IndexByte(name, '['); index > 0 {
+		return name[:index]
+	}
+
+	return name
+}
+
+func newActionInstance(action Action, name string, env *Env) actionInstance {
+	action.Name = name
+	action.ForEach = nil
+	action.DependsOn = nil
+
+	return actionInstance{
+		action: action,
+		env:    env,
+	}
+}
+
+// aggregateInstances summarizes the instances of a for_each action. The action data
+// lists every instance under instances and every declared output as a list of values.
+func (r *run) aggregateInstances(action Action, instances []actionInstance) (*ActionResponse, map[string]any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	names := make([]string, len(instances))
+	instancesData := make([]any, len(instances))
+	outputs := make(map[string]any, len(action.Outputs))
+	exitCode := 0
+	failed := false
+	skipped := len(instances) > 0
+
+	for name := range action.Outputs {
+		outputs[name] = make([]any, len(instances))
+	}
+
+	for index, instance := range instances {
+		names[index] = instance.action.Name
+
+		data, ok := r.actions[instance.action.Name].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		instancesData[index] = data
+
+		if code, _ := data[actionDataExitCode].(int); code != 0 && exitCode == 0 {
+			exitCode = code
+		}
+
+		failed = failed || data[actionDataFailed] == true
+		skipped = skipped && data[actionDataSkipped] == true
+
+		instanceOutputs, _ := data[actionDataOutputs].(map[string]any)
+
+		for name, values := range outputs {
+			values.([]any)[index] = instanceOutputs[name]
+		}
+	}
+
+	data := map[string]any{
+		actionDataExitCode:  exitCode,
+		actionDataStdout:    "",
+		actionDataStderr:    "",
+		actionDataResult:    "",
+		actionDataSkipped:   skipped,
+		actionDataFailed:    failed,
+		actionDataOutputs:   outputs,
+		actionDataInstances: instancesData,
+	}
+
+	response := NewActionResponse().
+		WithExitCode(exitCode).
+		WithSkipped(skipped).
+		WithInstances(names)
+
+	return response, data
+}
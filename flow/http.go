@@ -0,0 +1,184 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	httpClient "github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/json"
+)
+
+const (
+	minHttpStatus = 100
+	maxHttpStatus = 599
+)
+
+var httpMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+func (f *Impl) validateActionHttp(action Action) error {
+	request := action.Http
+
+	if f.httpClient == nil {
+		return ErrNoHttpClient
+	}
+
+	if request.Url == "" {
+		return ErrActionHttpNoUrl
+	}
+
+	if !slices.Contains(httpMethods, httpMethod(request)) {
+		return fmt.Errorf("%w: %s", ErrActionHttpInvalidMethod, request.Method)
+	}
+
+	if request.Body != nil && request.BodyTemplate != "" {
+		return ErrActionHttpBodyTooManyOptions
+	}
+
+	for _, status := range request.ExpectedStatus {
+		if status < minHttpStatus || status > maxHttpStatus {
+			return fmt.Errorf("%w: %d", ErrActionHttpInvalidExpectedStatus, status)
+		}
+	}
+
+	return nil
+}
+
+func httpMethod(request *ActionHttp) string {
+	if request.Method == "" {
+		return http.MethodGet
+	}
+
+	return strings.ToUpper(request.Method)
+}
+
+func (f *Impl) runActionHttp(ctx context.Context, env *Env, action Action) (*ActionResponse, error) {
+	log := f.log.GetLogger(ctx)
+
+	log.Debugf("Running action '%s' as http request", action.Name)
+
+	if err := f.validateActionHttp(action); err != nil {
+		return nil, err
+	}
+
+	request := action.Http
+	method := httpMethod(request)
+
+	url, err := f.evalTemplate(ctx, env, "action."+action.Name+".http.url", request.Url)
+	if err != nil {
+		return nil, fmt.Errorf("eval template for action '%s' url: %w", action.Name, err)
+	}
+
+	headers := make(httpClient.Headers, len(request.Headers)+1)
+
+	for _, name := range slices.Sorted(maps.Keys(request.Headers)) {
+		value, err := f.evalTemplate(ctx, env, "action."+action.Name+".http.headers."+name, request.Headers[name])
+		if err != nil {
+			return nil, fmt.Errorf("eval template for action '%s' header %s: %w", action.Name, name, err)
+		}
+
+		headers[name] = []string{value}
+	}
+
+	body, err := f.getActionHttpBody(ctx, env, action)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil && !hasHeader(headers, "Content-Type") {
+		headers["Content-Type"] = []string{"application/json"}
+	}
+
+	opts := []httpClient.RequestOption{httpClient.WithHeaders(headers)}
+
+	if body != nil {
+		opts = append(opts, httpClient.WithBody(body))
+	}
+
+	httpResponse, err := f.httpClient.Do(ctx, method, url, opts...)
+	if httpResponse == nil || (err != nil && !isHttpStatusError(err)) {
+		return nil, fmt.Errorf("%w: %s %s for action '%s': %w", ErrActionHttpFailed, method, url, action.Name, err)
+	}
+
+	response := NewActionResponse().
+		WithStatusCode(httpResponse.GetStatusCode()).
+		WithStdout(string(httpResponse.GetBody()))
+
+	if !expectedHttpStatus(request.ExpectedStatus, httpResponse.GetStatusCode()) {
+		return response, fmt.Errorf(
+			"%w: %s %s for action '%s' returned %d: %s",
+			ErrActionHttpUnexpectedStatus,
+			method,
+			url,
+			action.Name,
+			httpResponse.GetStatusCode(),
+			httpResponse.GetBody(),
+		)
+	}
+
+	return response, nil
+}
+
+func (f *Impl) getActionHttpBody(ctx context.Context, env *Env, action Action) ([]byte, error) {
+	request := action.Http
+
+	switch {
+	case request.Body != nil:
+		body, err := json.Marshal(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal action '%s' body: %w", action.Name, err)
+		}
+
+		return body, nil
+
+	case request.BodyTemplate != "":
+		body, err := f.evalTemplate(ctx, env, "action."+action.Name+".http.body_template", request.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("eval template for action '%s' body: %w", action.Name, err)
+		}
+
+		return []byte(body), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// expectedHttpStatus accepts every 2xx status when no statuses are expected explicitly.
+func expectedHttpStatus(expected []int, status int) bool {
+	if len(expected) == 0 {
+		return status >= http.StatusOK && status < http.StatusMultipleChoices
+	}
+
+	return slices.Contains(expected, status)
+}
+
+// isHttpStatusError reports whether the client failed on the status of a response that
+// arrived, transport errors come with a response too but its status is meaningless.
+func isHttpStatusError(err error) bool {
+	return errors.Is(err, httpClient.ErrNon200HttpCode) ||
+		errors.Is(err, httpClient.ErrNotFound) ||
+		errors.Is(err, httpClient.ErrBadRequest)
+}
+
+func hasHeader(headers httpClient.Headers, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
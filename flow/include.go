@@ -0,0 +1,299 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includePath resolves the path of an included flow file against the directory of the including one.
+func (f *Impl) includePath(action Action) string {
+	includePath := action.Include.Path
+
+	if filepath.IsAbs(includePath) || f.config.Path == "" {
+		return filepath.Clean(includePath)
+	}
+
+	return filepath.Join(filepath.Dir(f.config.Path), includePath)
+}
+
+// loadInclude loads the config of an included flow, failing when the flow file
+// is already being run by one of the including flows.
+func (f *Impl) loadInclude(action Action) (*Config, error) {
+	if action.Include.Path == "" {
+		return nil, ErrActionIncludeNoPath
+	}
+
+	config, err := LoadConfig(f.includePath(action))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Path == f.config.Path || slices.Contains(f.includedBy, config.Path) {
+		chain := append(slices.Clone(f.includedBy), f.config.Path, config.Path)
+
+		return nil, fmt.Errorf("%w: %s", ErrActionIncludeRecursion, strings.Join(chain, " -> "))
+	}
+
+	return config, nil
+}
+
+// subFlow creates the engine running an included flow, it shares the drivers,
+// triggers, clients and observers of the including flow but is not checkpointed.
+func (f *Impl) subFlow(config *Config) *Impl {
+	includedBy := slices.Clone(f.includedBy)
+
+	if f.config.Path != "" {
+		includedBy = append(includedBy, f.config.Path)
+	}
+
+	return &Impl{
+		log:             f.log,
+		config:          config,
+		storage:         f.storage,
+		templateDriver:  f.templateDriver,
+		scriptDriver:    f.scriptDriver,
+		triggers:        f.triggers,
+		logsDir:         f.logsDir,
+		logFilePrefix:   f.logFilePrefix,
+		checkpointStore: nil,
+		httpClient:      f.httpClient,
+		storages:        f.storages,
+		observers:       f.observers,
+		includedBy:      includedBy,
+	}
+}
+
+func (f *Impl) validateActionInclude(ctx context.Context, action Action) error {
+	config, err := f.loadInclude(action)
+	if err != nil {
+		return err
+	}
+
+	for name := range action.Include.Inputs {
+		if _, ok := config.Inputs[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrActionIncludeUnknownInput, name)
+		}
+	}
+
+	for name, input := range config.Inputs {
+		if _, ok := action.Include.Inputs[name]; !ok && input.Required && input.Default == nil {
+			return fmt.Errorf("%w: %s", ErrActionIncludeMissingInput, name)
+		}
+	}
+
+	if err = f.subFlow(config).Validate(ctx); err != nil {
+		return fmt.Errorf("include %s: %w", config.Path, err)
+	}
+
+	return nil
+}
+
+// runActionInclude runs the included flow with its inputs as the only context values,
+// the declared outputs of the sub-flow become the outputs of the action.
+func (f *Impl) runActionInclude(ctx context.Context, env *Env, state *run, action Action) (*ActionResponse, error) {
+	log := f.log.GetLogger(ctx)
+
+	config, err := f.loadInclude(action)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Running action '%s' as sub-flow %s", action.Name, config.Path)
+
+	inputs, err := f.includeInputs(ctx, env, action, config)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := f.actionWorkDir(ctx, env, action)
+	if err != nil {
+		return nil, err
+	}
+
+	subFlow := f.subFlow(config)
+
+	subEnv := &Env{
+		WorkDir: workDir,
+		Context: inputs,
+		Env:     env.Env,
+	}
+
+	_, subState, err := subFlow.run(ctx, state.result.RunId+"/"+action.Name, subEnv, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sub-flow %s: %w", config.Path, err)
+	}
+
+	outputs, err := subFlow.evalConfigOutputs(ctx, subState.envFor())
+	if err != nil {
+		return nil, fmt.Errorf("sub-flow %s: %w", config.Path, err)
+	}
+
+	return NewActionResponse().WithResult(config.Path).WithOutputs(outputs), nil
+}
+
+func (f *Impl) includeInputs(ctx context.Context, env *Env, action Action, config *Config) (map[string]any, error) {
+	inputs := make(map[string]any, len(config.Inputs))
+
+	for _, name := range slices.Sorted(maps.Keys(action.Include.Inputs)) {
+		declared, ok := config.Inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrActionIncludeUnknownInput, name)
+		}
+
+		value := action.Include.Inputs[name]
+
+		if source, ok := value.(string); ok {
+			evalResult, err := f.evalTemplate(ctx, env, "action."+action.Name+".include.inputs."+name, source)
+			if err != nil {
+				return nil, fmt.Errorf("eval template for action '%s' input %s: %w", action.Name, name, err)
+			}
+
+			value = evalResult
+		}
+
+		converted, err := convertInput(declared.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrActionIncludeInvalidInput, name, err)
+		}
+
+		inputs[name] = converted
+	}
+
+	for name, declared := range config.Inputs {
+		if _, ok := inputs[name]; ok {
+			continue
+		}
+
+		if declared.Required && declared.Default == nil {
+			return nil, fmt.Errorf("%w: %s", ErrActionIncludeMissingInput, name)
+		}
+
+		inputs[name] = declared.Default
+	}
+
+	return inputs, nil
+}
+
+func (f *Impl) evalConfigOutputs(ctx context.Context, env *Env) (map[string]string, error) {
+	if len(f.config.Outputs) == 0 {
+		return nil, nil
+	}
+
+	outputs := make(map[string]string, len(f.config.Outputs))
+
+	for _, name := range slices.Sorted(maps.Keys(f.config.Outputs)) {
+		templateName := "outputs." + name
+
+		evalResult, err := f.evalTemplate(ctx, env, templateName, f.config.Outputs[name])
+		if err != nil {
+			return nil, fmt.Errorf("eval output %s: %w", templateName, err)
+		}
+
+		outputs[name] = strings.TrimSpace(evalResult)
+	}
+
+	return outputs, nil
+}
+
+// convertInput converts an input value to the declared type, strings produced by
+// templates are parsed, e.g. "1280" for a number or "[a, b]" for a list.
+//
+//nolint:cyclop
+func convertInput(inputType InputType, value any) (any, error) {
+	source, isString := value.(string)
+
+	switch inputType {
+	case InputTypeAny:
+		return value, nil
+
+	case InputTypeString:
+		if isString {
+			return source, nil
+		}
+
+		return fmt.Sprint(value), nil
+
+	case InputTypeNumber:
+		if isString {
+			return strconv.ParseFloat(strings.TrimSpace(source), 64)
+		}
+
+		if number, ok := inputNumber(value); ok {
+			return number, nil
+		}
+
+	case InputTypeInteger:
+		if isString {
+			return strconv.Atoi(strings.TrimSpace(source))
+		}
+
+		if number, ok := inputNumber(value); ok && number == math.Trunc(number) {
+			return int(number), nil
+		}
+
+	case InputTypeBoolean:
+		if isString {
+			return strconv.ParseBool(strings.TrimSpace(source))
+		}
+
+		if boolean, ok := value.(bool); ok {
+			return boolean, nil
+		}
+
+	case InputTypeList:
+		if isString {
+			var list []any
+
+			if err := yaml.Unmarshal([]byte(source), &list); err != nil {
+				return nil, err
+			}
+
+			return list, nil
+		}
+
+		if list, ok := value.([]any); ok {
+			return list, nil
+		}
+
+	case InputTypeMap:
+		if isString {
+			var object map[string]any
+
+			if err := yaml.Unmarshal([]byte(source), &object); err != nil {
+				return nil, err
+			}
+
+			return object, nil
+		}
+
+		if object, ok := value.(map[string]any); ok {
+			return object, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown type %s", inputType)
+	}
+
+	return nil, fmt.Errorf("%v is not a %s", value, inputType)
+}
+
+func inputNumber(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
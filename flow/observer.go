@@ -0,0 +1,115 @@
+package flow
+
+import (
+	"context"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventFlowStarted     EventType = "flow_started"
+	EventFlowFinished    EventType = "flow_finished"
+	EventActionQueued    EventType = "action_queued"
+	EventActionStarted   EventType = "action_started"
+	EventActionRetried   EventType = "action_retried"
+	EventActionSkipped   EventType = "action_skipped"
+	EventActionSucceeded EventType = "action_succeeded"
+	EventActionFailed    EventType = "action_failed"
+)
+
+// Event is a lifecycle change of a run or of one of its actions. Exit code, output
+// sizes and duration are only set on events finishing an action or the flow,
+// Attempt is the number of the attempt about to start on action_retried. Instances is
+// the number of for_each instances on the event finishing their parent action.
+type Event struct {
+	Type       EventType     `json:"type"`
+	RunId      string        `json:"run_id"`
+	Action     string        `json:"action,omitempty"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Attempt    int           `json:"attempt,omitempty"`
+	ExitCode   int           `json:"exit_code"`
+	StatusCode int           `json:"status_code,omitempty"`
+	StdoutSize int           `json:"stdout_size"`
+	StderrSize int           `json:"stderr_size"`
+	Duration   time.Duration `json:"duration,omitempty"`
+	Restored   bool          `json:"restored,omitempty"`
+	Instances  int           `json:"instances,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+func newEvent(eventType EventType, runId string, action string) Event {
+	return Event{
+		Type:       eventType,
+		RunId:      runId,
+		Action:     action,
+		Timestamp:  time.Now(),
+		Attempt:    0,
+		ExitCode:   0,
+		StatusCode: 0,
+		StdoutSize: 0,
+		StderrSize: 0,
+		Duration:   0,
+		Restored:   false,
+		Instances:  0,
+		Error:      "",
+	}
+}
+
+// Observer is notified about every event of a run. Actions run concurrently,
+// so OnEvent must be safe for concurrent use and should return quickly.
+type Observer interface {
+	OnEvent(ctx context.Context, event Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(ctx context.Context, event Event)
+
+func (o ObserverFunc) OnEvent(ctx context.Context, event Event) {
+	o(ctx, event)
+}
+
+func (f *Impl) notify(ctx context.Context, event Event) {
+	for _, observer := range f.observers {
+		observer.OnEvent(ctx, event)
+	}
+}
+
+func (f *Impl) notifyAction(ctx context.Context, state *run, eventType EventType, action string) {
+	if len(f.observers) == 0 {
+		return
+	}
+
+	f.notify(ctx, newEvent(eventType, state.result.RunId, action))
+}
+
+// notifyActionFinished reports the outcome of an action, err is the error the action
+// failed with even if it continues on error.
+func (f *Impl) notifyActionFinished(ctx context.Context, state *run, action string, response *ActionResponse, err error) {
+	if len(f.observers) == 0 {
+		return
+	}
+
+	event := newEvent(EventActionSucceeded, state.result.RunId, action)
+
+	if response != nil {
+		event.ExitCode = response.ErrorCode
+		event.StatusCode = response.StatusCode
+		event.StdoutSize = len(response.Stdout)
+		event.StderrSize = len(response.Stderr)
+		event.Duration = response.Duration
+		event.Restored = response.Restored
+		event.Instances = len(response.Instances)
+
+		if response.Skipped {
+			event.Type = EventActionSkipped
+		}
+	}
+
+	if err != nil {
+		event.Type = EventActionFailed
+		event.Error = err.Error()
+	}
+
+	f.notify(ctx, event)
+}
@@ -0,0 +1,215 @@
+package flow
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pixality-inc/golang-core/json"
+	"github.com/pixality-inc/golang-core/logger"
+	"github.com/pixality-inc/golang-core/metrics"
+	"github.com/pixality-inc/golang-core/util"
+)
+
+// LoggerObserver writes every event to the logger, failures are logged as errors.
+type LoggerObserver struct {
+	log logger.Loggable
+}
+
+func NewLoggerObserver(log logger.Loggable) *LoggerObserver {
+	return &LoggerObserver{
+		log: log,
+	}
+}
+
+func (o *LoggerObserver) OnEvent(ctx context.Context, event Event) {
+	log := o.log.GetLogger(ctx).
+		WithField("run_id", event.RunId).
+		WithField("event", event.Type)
+
+	if event.Action != "" {
+		log = log.WithField("action", event.Action)
+	}
+
+	switch event.Type {
+	case EventFlowStarted:
+		log.Infof("Flow run '%s' started", event.RunId)
+
+	case EventFlowFinished:
+		if event.Error != "" {
+			log.WithField("error", event.Error).Errorf("Flow run '%s' failed in %s", event.RunId, util.FormatDuration(event.Duration))
+		} else {
+			log.Infof("Flow run '%s' finished in %s", event.RunId, util.FormatDuration(event.Duration))
+		}
+
+	case EventActionQueued, EventActionStarted:
+		log.Debugf("Action '%s' %s", event.Action, event.Type)
+
+	case EventActionRetried:
+		log.Warnf("Action '%s' retried, attempt %d", event.Action, event.Attempt)
+
+	case EventActionSkipped:
+		log.Infof("Action '%s' skipped", event.Action)
+
+	case EventActionSucceeded:
+		log.WithField("exit_code", event.ExitCode).
+			WithField("stdout_size", event.StdoutSize).
+			WithField("stderr_size", event.StderrSize).
+			Infof("Action '%s' succeeded in %s", event.Action, util.FormatDuration(event.Duration))
+
+	case EventActionFailed:
+		log.WithField("exit_code", event.ExitCode).
+			WithField("stdout_size", event.StdoutSize).
+			WithField("stderr_size", event.StderrSize).
+			WithField("error", event.Error).
+			Errorf("Action '%s' failed in %s", event.Action, util.FormatDuration(event.Duration))
+	}
+}
+
+const (
+	MetricActionDuration = "flow_action_duration_seconds"
+	MetricFlowDuration   = "flow_run_duration_seconds"
+)
+
+// MetricsObserver records the duration of runs and of every executed action in
+// histograms. Histograms are created and registered on the first event of each
+// action, labelled with the action name and result (succeeded or failed). For_each
+// and matrix instances are recorded under the name of their action, which itself is
+// not recorded. Skipped and restored actions are not recorded.
+type MetricsObserver struct {
+	log        logger.Loggable
+	manager    metrics.Manager
+	namespace  string
+	options    metrics.HistogramOptions
+	histograms map[string]metrics.Histogram
+	mutex      sync.Mutex
+}
+
+func NewMetricsObserver(manager metrics.Manager, namespace string, options metrics.HistogramOptions) *MetricsObserver {
+	if options == nil {
+		options = metrics.NewHistogramOptions()
+	}
+
+	return &MetricsObserver{
+		log:        logger.NewLoggableImplWithService("flow_metrics"),
+		manager:    manager,
+		namespace:  namespace,
+		options:    options,
+		histograms: make(map[string]metrics.Histogram),
+		mutex:      sync.Mutex{},
+	}
+}
+
+func (o *MetricsObserver) OnEvent(ctx context.Context, event Event) {
+	if event.Restored {
+		return
+	}
+
+	var (
+		name   string
+		labels map[string]string
+	)
+
+	switch event.Type {
+	case EventActionSucceeded, EventActionFailed:
+		if event.Instances > 0 {
+			return
+		}
+
+		name = MetricActionDuration
+		labels = map[string]string{
+			"action": actionBaseName(event.Action),
+			"result": metricResult(event),
+		}
+
+	case EventFlowFinished:
+		name = MetricFlowDuration
+		labels = map[string]string{
+			"result": metricResult(event),
+		}
+
+	default:
+		return
+	}
+
+	histogram, err := o.histogram(name, labels)
+	if err != nil {
+		o.log.GetLogger(ctx).WithError(err).Errorf("failed to register histogram %s", name)
+
+		return
+	}
+
+	histogram.Observe(event.Duration.Seconds())
+}
+
+func (o *MetricsObserver) histogram(name string, labels map[string]string) (metrics.Histogram, error) {
+	key := name + "/" + labels["action"] + "/" + labels["result"]
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if histogram, ok := o.histograms[key]; ok {
+		return histogram, nil
+	}
+
+	help := "Duration of flow actions in seconds"
+
+	if name == MetricFlowDuration {
+		help = "Duration of flow runs in seconds"
+	}
+
+	histogram := o.manager.NewHistogram(
+		metrics.NewMetricDescription(name).
+			WithNamespace(o.namespace).
+			WithHelp(help).
+			WithLabels(labels),
+		o.options,
+	)
+
+	if err := o.manager.Register(histogram); err != nil {
+		return nil, err
+	}
+
+	o.histograms[key] = histogram
+
+	return histogram, nil
+}
+
+func metricResult(event Event) string {
+	if event.Error != "" {
+		return "failed"
+	}
+
+	return "succeeded"
+}
+
+// JsonObserver streams events to the writer as JSON, one event per line.
+type JsonObserver struct {
+	log    logger.Loggable
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func NewJsonObserver(writer io.Writer) *JsonObserver {
+	return &JsonObserver{
+		log:    logger.NewLoggableImplWithService("flow_events"),
+		writer: writer,
+		mutex:  sync.Mutex{},
+	}
+}
+
+func (o *JsonObserver) OnEvent(ctx context.Context, event Event) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		o.log.GetLogger(ctx).WithError(err).Errorf("failed to marshal event %s", event.Type)
+
+		return
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, err = o.writer.Write(append(line, '\n')); err != nil {
+		o.log.GetLogger(ctx).WithError(err).Errorf("failed to write event %s", event.Type)
+	}
+}
@@ -1,5 +1,10 @@
 package flow
 
+import (
+	httpClient "github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/storage"
+)
+
 type Option = func(flowEngine *Impl)
 
 func WithLogFiles(logsDir string, logFilePrefix string) Option {
@@ -8,3 +13,32 @@ func WithLogFiles(logsDir string, logFilePrefix string) Option {
 		flowEngine.logFilePrefix = &logFilePrefix
 	}
 }
+
+// WithCheckpoints persists the state of every run to store after each succeeded action,
+// so a failed run can be continued with Resume.
+func WithCheckpoints(store CheckpointStore) Option {
+	return func(flowEngine *Impl) {
+		flowEngine.checkpointStore = store
+	}
+}
+
+// WithHttpClient sets the client used by http actions.
+func WithHttpClient(client httpClient.Client) Option {
+	return func(flowEngine *Impl) {
+		flowEngine.httpClient = client
+	}
+}
+
+// WithStorages sets the storages storage actions refer to by name.
+func WithStorages(storages map[string]storage.Storage) Option {
+	return func(flowEngine *Impl) {
+		flowEngine.storages = storages
+	}
+}
+
+// WithObservers adds observers notified about the lifecycle events of every run.
+func WithObservers(observers ...Observer) Option {
+	return func(flowEngine *Impl) {
+		flowEngine.observers = append(flowEngine.observers, observers...)
+	}
+}
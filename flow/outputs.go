@@ -0,0 +1,133 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/json"
+)
+
+// ActionsContextKey is the env context key under which templates and scripts
+// find the results of finished actions, e.g. actions.<name>.outputs.<output>.
+const ActionsContextKey = "actions"
+
+const (
+	actionDataExitCode = "exit_code"
+	actionDataStatus   = "status_code"
+	actionDataStdout   = "stdout"
+	actionDataStderr   = "stderr"
+	actionDataJson     = "json"
+	actionDataResult   = "result"
+	actionDataSkipped  = "skipped"
+	actionDataFailed   = "failed"
+	actionDataOutputs  = "outputs"
+)
+
+// envFor returns the env an action is evaluated with: the run env, extended with
+// the data of every action finished so far. The run env is returned as is until
+// the first action finished.
+func (r *run) envFor() *Env {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.actions) == 0 {
+		return r.env
+	}
+
+	return r.env.withContext(map[string]any{ActionsContextKey: maps.Clone(r.actions)})
+}
+
+func (r *run) setActionData(name string, data map[string]any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.actions[name] = data
+}
+
+// withContext returns a copy of the env with values added to its context.
+func (e *Env) withContext(values map[string]any) *Env {
+	merged := make(map[string]any, len(e.Context)+len(values))
+
+	maps.Copy(merged, e.Context)
+	maps.Copy(merged, values)
+
+	return &Env{
+		WorkDir: e.WorkDir,
+		Context: merged,
+		Env:     e.Env,
+	}
+}
+
+// newActionData describes a finished action for the following ones.
+func newActionData(action Action, response *ActionResponse) (map[string]any, error) {
+	// outputs set by the action itself, e.g. the declared outputs of a sub-flow
+	outputs := make(map[string]any, len(response.Outputs))
+
+	for name, value := range response.Outputs {
+		outputs[name] = value
+	}
+
+	data := map[string]any{
+		actionDataExitCode: response.ErrorCode,
+		actionDataStatus:   response.StatusCode,
+		actionDataStdout:   response.Stdout,
+		actionDataStderr:   response.Stderr,
+		actionDataResult:   response.Result,
+		actionDataSkipped:  response.Skipped,
+		actionDataFailed:   response.Failed,
+		actionDataOutputs:  outputs,
+	}
+
+	if action.StdoutJson && !response.Skipped && !response.Failed {
+		var parsed any
+
+		if err := json.Unmarshal([]byte(response.Stdout), &parsed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrActionStdoutNotJson, err)
+		}
+
+		data[actionDataJson] = parsed
+	}
+
+	return data, nil
+}
+
+// evalActionOutputs renders the declared outputs of an action, the action's own
+// data is already visible to them under actions.<name>.
+func (f *Impl) evalActionOutputs(
+	ctx context.Context,
+	env *Env,
+	action Action,
+	data map[string]any,
+) (map[string]string, error) {
+	if len(action.Outputs) == 0 {
+		return nil, nil
+	}
+
+	actions := make(map[string]any)
+
+	if current, ok := env.Context[ActionsContextKey].(map[string]any); ok {
+		maps.Copy(actions, current)
+	}
+
+	actions[action.Name] = data
+
+	outputsEnv := env.withContext(map[string]any{ActionsContextKey: actions})
+
+	outputs := make(map[string]string, len(action.Outputs))
+
+	for _, name := range slices.Sorted(maps.Keys(action.Outputs)) {
+		templateName := "action." + action.Name + ".outputs." + name
+
+		evalResult, err := f.evalTemplate(ctx, outputsEnv, templateName, action.Outputs[name])
+		if err != nil {
+			return nil, fmt.Errorf("eval output %s: %w", templateName, err)
+		}
+
+		outputs[name] = strings.TrimSpace(evalResult)
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,327 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	ActionKindCommand    = "command"
+	ActionKindScript     = "script"
+	ActionKindScriptFile = "script_file"
+	ActionKindTrigger    = "trigger"
+	ActionKindResult     = "result"
+	ActionKindHttp       = "http"
+	ActionKindStorage    = "storage"
+	ActionKindInclude    = "include"
+)
+
+var shellSafeArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// Plan is what a run of the flow would do, actions are listed in execution order.
+type Plan struct {
+	Actions []*PlannedAction `json:"actions"`
+}
+
+// PlannedAction is an action with its when condition, arguments, env and work dir
+// evaluated against the run env. Actions that would fail to evaluate keep the error,
+// which is expected for templates referring to data of actions that didn't run.
+type PlannedAction struct {
+	Name        string            `json:"name"`
+	Kind        string            `json:"kind"`
+	DependsOn   []string          `json:"depends_on,omitempty"`
+	Skipped     bool              `json:"skipped"`
+	Command     string            `json:"command,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	CommandLine string            `json:"command_line,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	WorkDir     string            `json:"work_dir,omitempty"`
+	Stdout      string            `json:"stdout,omitempty"`
+	Stderr      string            `json:"stderr,omitempty"`
+	Instances   []*PlannedAction  `json:"instances,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// Plan evaluates every action of the flow without executing commands, scripts,
+// triggers, requests or storage operations.
+func (f *Impl) Plan(ctx context.Context, env *Env) (*Plan, error) {
+	if env == nil {
+		env = NewEnv("", nil)
+	}
+
+	g, err := buildGraph(f.config.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := make([][]string, len(g.actions))
+
+	for index, dependents := range g.dependents {
+		for _, dependent := range dependents {
+			dependencies[dependent] = append(dependencies[dependent], g.actions[index].Name)
+		}
+	}
+
+	plan := &Plan{
+		Actions: make([]*PlannedAction, 0, len(g.actions)),
+	}
+
+	for _, index := range g.order() {
+		action := g.actions[index]
+
+		planned := f.planAction(ctx, env, action)
+		planned.DependsOn = dependencies[index]
+
+		plan.Actions = append(plan.Actions, planned)
+	}
+
+	return plan, nil
+}
+
+func (f *Impl) planAction(ctx context.Context, env *Env, action Action) *PlannedAction {
+	planned := &PlannedAction{
+		Name: action.Name,
+		Kind: actionKind(action),
+	}
+
+	if action.ForEach == nil {
+		f.planActionInstance(ctx, env, action, planned)
+
+		return planned
+	}
+
+	instances, err := f.expandForEach(ctx, env, action)
+	if err != nil {
+		planned.Error = err.Error()
+
+		return planned
+	}
+
+	planned.Instances = make([]*PlannedAction, len(instances))
+
+	for index, instance := range instances {
+		plannedInstance := &PlannedAction{
+			Name: instance.action.Name,
+			Kind: planned.Kind,
+		}
+
+		f.planActionInstance(ctx, instance.env, instance.action, plannedInstance)
+
+		planned.Instances[index] = plannedInstance
+	}
+
+	return planned
+}
+
+func (f *Impl) planActionInstance(ctx context.Context, env *Env, action Action, planned *PlannedAction) {
+	if ok, err := f.actionWhen(ctx, env, action); err != nil {
+		planned.Error = fmt.Errorf("when failed: %w", err).Error()
+
+		return
+	} else if !ok {
+		planned.Skipped = true
+
+		return
+	}
+
+	var err error
+
+	switch planned.Kind {
+	case ActionKindCommand:
+		err = f.planCommand(ctx, env, action, planned)
+
+	case ActionKindTrigger:
+		planned.CommandLine = "trigger " + action.Trigger.Name
+
+	case ActionKindScriptFile:
+		planned.CommandLine, err = f.evalTemplate(ctx, env, "action."+action.Name+".script_filename", action.ScriptFile)
+
+	case ActionKindHttp:
+		var url string
+
+		url, err = f.evalTemplate(ctx, env, "action."+action.Name+".http.url", action.Http.Url)
+		planned.CommandLine = httpMethod(action.Http) + " " + url
+
+	case ActionKindStorage:
+		err = f.planStorage(ctx, env, action, planned)
+
+	case ActionKindInclude:
+		planned.CommandLine = "include " + f.includePath(action)
+	}
+
+	if err != nil {
+		planned.Error = err.Error()
+	}
+}
+
+func (f *Impl) planCommand(ctx context.Context, env *Env, action Action, planned *PlannedAction) error {
+	resolved, err := f.resolveCommand(ctx, env, action)
+	if err != nil {
+		return err
+	}
+
+	planned.Command = resolved.Command
+	planned.Args = resolved.Args
+	planned.Env = resolved.Env
+	planned.WorkDir = resolved.WorkDir
+	planned.Stdout = resolved.Stdout
+	planned.Stderr = resolved.Stderr
+
+	parts := make([]string, 0, len(resolved.Args)+1)
+
+	for _, part := range append([]string{resolved.Command}, resolved.Args...) {
+		parts = append(parts, shellQuote(part))
+	}
+
+	planned.CommandLine = strings.Join(parts, " ")
+
+	return nil
+}
+
+func (f *Impl) planStorage(ctx context.Context, env *Env, action Action, planned *PlannedAction) error {
+	if err := f.validateActionStorage(action); err != nil {
+		return err
+	}
+
+	operation := action.Storage
+
+	source, err := f.storageLocationPath(ctx, env, action, "source", operation.Source)
+	if err != nil {
+		return err
+	}
+
+	planned.CommandLine = string(operation.Operation) + " " + storageLocationString(operation.Source.Storage, source)
+
+	if operation.Destination != nil {
+		destination, err := f.storageLocationPath(ctx, env, action, "destination", *operation.Destination)
+		if err != nil {
+			return err
+		}
+
+		planned.CommandLine += " " + storageLocationString(operation.Destination.Storage, destination)
+	}
+
+	return nil
+}
+
+func storageLocationString(storage string, locationPath string) string {
+	if storage == "" {
+		return locationPath
+	}
+
+	return storage + ":" + locationPath
+}
+
+func actionKind(action Action) string {
+	switch {
+	case action.Trigger != nil:
+		return ActionKindTrigger
+	case action.Result != nil:
+		return ActionKindResult
+	case action.Script != "":
+		return ActionKindScript
+	case action.ScriptFile != "":
+		return ActionKindScriptFile
+	case action.Http != nil:
+		return ActionKindHttp
+	case action.Storage != nil:
+		return ActionKindStorage
+	case action.Include != nil:
+		return ActionKindInclude
+	default:
+		return ActionKindCommand
+	}
+}
+
+func shellQuote(arg string) string {
+	if shellSafeArg.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
+
+// Mermaid renders the action graph as a Mermaid flowchart, skipped actions are dashed.
+func (p *Plan) Mermaid() string {
+	var builder strings.Builder
+
+	builder.WriteString("flowchart TD\n")
+
+	ids := make(map[string]string, len(p.Actions))
+
+	for index, action := range p.Actions {
+		id := "a" + strconv.Itoa(index)
+		ids[action.Name] = id
+
+		fmt.Fprintf(&builder, "    %s[\"%s\"]\n", id, mermaidEscape(planLabel(action)))
+	}
+
+	for _, action := range p.Actions {
+		for _, dependency := range action.DependsOn {
+			fmt.Fprintf(&builder, "    %s --> %s\n", ids[dependency], ids[action.Name])
+		}
+	}
+
+	skipped := make([]string, 0)
+
+	for _, action := range p.Actions {
+		if action.Skipped {
+			skipped = append(skipped, ids[action.Name])
+		}
+	}
+
+	if len(skipped) > 0 {
+		builder.WriteString("    classDef skipped stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&builder, "    class %s skipped\n", strings.Join(skipped, ","))
+	}
+
+	return builder.String()
+}
+
+// Dot renders the action graph in the Graphviz DOT language, skipped actions are dashed.
+func (p *Plan) Dot() string {
+	var builder strings.Builder
+
+	builder.WriteString("digraph flow {\n")
+
+	for _, action := range p.Actions {
+		attributes := "label=" + strconv.Quote(planLabel(action))
+
+		if action.Skipped {
+			attributes += ", style=dashed"
+		}
+
+		fmt.Fprintf(&builder, "    %s [%s];\n", strconv.Quote(action.Name), attributes)
+	}
+
+	for _, action := range p.Actions {
+		for _, dependency := range action.DependsOn {
+			fmt.Fprintf(&builder, "    %s -> %s;\n", strconv.Quote(dependency), strconv.Quote(action.Name))
+		}
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+func planLabel(action *PlannedAction) string {
+	label := action.Name
+
+	if len(action.Instances) > 0 {
+		label += " x" + strconv.Itoa(len(action.Instances))
+	}
+
+	if action.Skipped {
+		label += " (skipped)"
+	}
+
+	return label
+}
+
+func mermaidEscape(label string) string {
+	return strings.ReplaceAll(label, `"`, "#quot;")
+}
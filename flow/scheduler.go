@@ -0,0 +1,297 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/pixality-inc/golang-core/retry"
+	"github.com/pixality-inc/golang-core/timetrack"
+	"github.com/pixality-inc/golang-core/util"
+)
+
+// run holds the state shared by actions executing concurrently within one Run.
+type run struct {
+	env          *Env
+	result       *Result
+	actions      map[string]any
+	checkpointer *checkpointer
+	mutex        sync.Mutex
+}
+
+func (r *run) setResponse(name string, response *ActionResponse) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.result.ActionsResponses[name] = response
+}
+
+func (r *run) response(name string) *ActionResponse {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.result.ActionsResponses[name]
+}
+
+type actionDone struct {
+	index int
+	err   error
+}
+
+// schedule runs every action once all of its dependencies succeeded, starting at most
+// Config.MaxParallel actions at a time. After the first failure no new actions are
+// started, running ones are awaited and the first error is returned.
+func (f *Impl) schedule(ctx context.Context, state *run, g *graph) error {
+	remaining := make([]int, len(g.indegree))
+	copy(remaining, g.indegree)
+
+	ready := g.roots()
+
+	for _, index := range ready {
+		f.notifyAction(ctx, state, EventActionQueued, g.actions[index].Name)
+	}
+
+	done := make(chan actionDone)
+	running := 0
+
+	var firstErr error
+
+	for (firstErr == nil && len(ready) > 0) || running > 0 {
+		for firstErr == nil && len(ready) > 0 && (f.config.MaxParallel <= 0 || running < f.config.MaxParallel) {
+			index := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func() {
+				done <- actionDone{index: index, err: f.executeAction(ctx, state, g.actions[index])}
+			}()
+		}
+
+		finished := <-done
+		running--
+
+		if finished.err != nil {
+			if firstErr == nil {
+				firstErr = finished.err
+			}
+
+			continue
+		}
+
+		for _, dependent := range g.dependents[finished.index] {
+			remaining[dependent]--
+
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+
+				f.notifyAction(ctx, state, EventActionQueued, g.actions[dependent].Name)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func (f *Impl) executeAction(ctx context.Context, state *run, action Action) error {
+	if state.checkpointer == nil {
+		return f.executeActionOnce(ctx, state, action)
+	}
+
+	inputHash := state.checkpointer.actionInputHash(state, action)
+
+	if state.checkpointer.restore(state, action, inputHash) {
+		f.log.GetLogger(ctx).Debugf("Action '%s' restored from checkpoint", action.Name)
+
+		f.notifyActionFinished(ctx, state, action.Name, state.response(action.Name), nil)
+
+		return nil
+	}
+
+	if err := f.executeActionOnce(ctx, state, action); err != nil {
+		return err
+	}
+
+	if err := state.checkpointer.save(ctx, state, action, inputHash); err != nil {
+		f.log.GetLogger(ctx).WithError(err).Errorf("failed to save checkpoint of action '%s'", action.Name)
+	}
+
+	return nil
+}
+
+func (f *Impl) executeActionOnce(ctx context.Context, state *run, action Action) error {
+	if action.ForEach != nil {
+		return f.executeForEach(ctx, state, action)
+	}
+
+	return f.executeActionWithEnv(ctx, state, action, state.envFor())
+}
+
+// executeActionWithEnv runs the action with its retry policy and records the response
+// together with every attempt. Failures of actions marked continue_on_error are
+// only recorded, so their dependents still run.
+func (f *Impl) executeActionWithEnv(ctx context.Context, state *run, action Action, env *Env) error {
+	log := f.log.GetLogger(ctx)
+
+	f.notifyAction(ctx, state, EventActionStarted, action.Name)
+
+	track := timetrack.New(ctx)
+
+	var policy retry.Policy
+
+	if action.Retry != nil {
+		policy = action.Retry
+	}
+
+	attempts := make([]*ActionAttempt, 0, 1)
+
+	actionResponse, err := retry.Do(ctx, policy, f.log, func() (*ActionResponse, error) {
+		response, attempt, err := f.runAttempt(ctx, env, state, action, len(attempts)+1)
+
+		attempts = append(attempts, attempt)
+
+		return response, err
+	})
+
+	if err == nil {
+		err = f.finishAction(ctx, env, state, action, actionResponse)
+	}
+
+	duration := track.Finish()
+
+	if err != nil {
+		// keep the attempts history even when the action failed before producing a response
+		if actionResponse == nil && (len(attempts) > 1 || action.ContinueOnError) {
+			actionResponse = NewActionResponse()
+		}
+
+		if actionResponse != nil {
+			state.setResponse(action.Name, actionResponse.
+				WithError(err).
+				WithAttempts(attempts).
+				WithStartedAt(track.Start).
+				WithFinishedAt(track.End).
+				WithDuration(duration))
+		}
+
+		f.notifyActionFinished(ctx, state, action.Name, actionResponse, err)
+
+		if action.ContinueOnError {
+			if data, dataErr := newActionData(action, actionResponse); dataErr == nil {
+				state.setActionData(action.Name, data)
+			}
+
+			log.WithError(err).Warnf("Action '%s' failed in %s, continuing", action.Name, util.FormatDuration(duration))
+
+			return nil
+		}
+
+		return fmt.Errorf("action '%s' failed in %s: %w", action.Name, util.FormatDuration(duration), err)
+	}
+
+	log.Debugf("Action '%s' executed in %s", action.Name, util.FormatDuration(duration))
+
+	state.setResponse(action.Name, actionResponse.
+		WithAttempts(attempts).
+		WithStartedAt(track.Start).
+		WithFinishedAt(track.End).
+		WithDuration(duration))
+
+	f.notifyActionFinished(ctx, state, action.Name, actionResponse, nil)
+
+	return nil
+}
+
+// runAttempt runs the action once, bounding it with the action timeout which
+// is passed down to cli.Exec through the context.
+func (f *Impl) runAttempt(
+	ctx context.Context,
+	env *Env,
+	state *run,
+	action Action,
+	number int,
+) (*ActionResponse, *ActionAttempt, error) {
+	if number > 1 {
+		event := newEvent(EventActionRetried, state.result.RunId, action.Name)
+		event.Attempt = number
+
+		f.notify(ctx, event)
+	}
+
+	attemptCtx := ctx
+
+	if action.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		attemptCtx, cancel = context.WithTimeout(ctx, action.Timeout)
+		defer cancel()
+	}
+
+	track := timetrack.New(ctx)
+
+	response, err := f.runAction(attemptCtx, env, state, action)
+
+	duration := track.Finish()
+
+	attempt := &ActionAttempt{
+		Number:     number,
+		ErrorCode:  0,
+		Error:      "",
+		TimedOut:   false,
+		StartedAt:  track.Start,
+		FinishedAt: track.End,
+		Duration:   duration,
+	}
+
+	if response != nil {
+		attempt.ErrorCode = response.ErrorCode
+	}
+
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		attempt.TimedOut = true
+		err = fmt.Errorf("%w after %s: %w", ErrActionTimeout, util.FormatDuration(action.Timeout), err)
+	}
+
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	return response, attempt, err
+}
+
+// finishAction publishes the data and declared outputs of a succeeded action to the following actions.
+func (f *Impl) finishAction(ctx context.Context, env *Env, state *run, action Action, response *ActionResponse) error {
+	data, err := newActionData(action, response)
+	if err != nil {
+		return err
+	}
+
+	if !response.Skipped {
+		outputs, err := f.evalActionOutputs(ctx, env, action, data)
+		if err != nil {
+			return err
+		}
+
+		if len(outputs) > 0 {
+			merged := make(map[string]string, len(response.Outputs)+len(outputs))
+
+			maps.Copy(merged, response.Outputs)
+			maps.Copy(merged, outputs)
+
+			response.WithOutputs(merged)
+		}
+
+		actionOutputs := make(map[string]any, len(response.Outputs))
+
+		for name, value := range response.Outputs {
+			actionOutputs[name] = value
+		}
+
+		data[actionDataOutputs] = actionOutputs
+	}
+
+	state.setActionData(action.Name, data)
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package flow
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/pixality-inc/golang-core/json"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaEnums lists the allowed values of the string types of the config.
+var schemaEnums = map[reflect.Type][]any{
+	reflect.TypeFor[StorageOperation](): {
+		StorageOperationCopy,
+		StorageOperationMove,
+		StorageOperationUpload,
+		StorageOperationDownload,
+		StorageOperationDelete,
+	},
+	reflect.TypeFor[InputType](): {
+		InputTypeString,
+		InputTypeNumber,
+		InputTypeInteger,
+		InputTypeBoolean,
+		InputTypeList,
+		InputTypeMap,
+	},
+}
+
+// schemaRequired lists the fields that must be set in the config types.
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeFor[Action]():          {"name"},
+	reflect.TypeFor[ActionTrigger]():   {"name"},
+	reflect.TypeFor[ActionRetry]():     {"attempts"},
+	reflect.TypeFor[ActionHttp]():      {"url"},
+	reflect.TypeFor[ActionStorage]():   {"operation", "source"},
+	reflect.TypeFor[StorageLocation](): {"path"},
+	reflect.TypeFor[ActionInclude]():   {"path"},
+}
+
+// schemaRules are the cross-field rules of the config types, Validate enforces the same.
+var schemaRules = map[reflect.Type][]any{
+	reflect.TypeFor[Action](): {
+		atMostOneOf("trigger", "result", "command", "script", "script_file", "http", "storage", "include"),
+		atMostOneOf("env", "env_template", "env_script"),
+		atMostOneOf("args", "args_template", "args_script"),
+	},
+	reflect.TypeFor[ActionTrigger](): {
+		atMostOneOf("data", "data_script"),
+	},
+	reflect.TypeFor[ActionForEach](): {
+		map[string]any{
+			"oneOf": []any{
+				map[string]any{"required": []string{"items"}},
+				map[string]any{"required": []string{"template"}},
+				map[string]any{"required": []string{"script"}},
+				map[string]any{"required": []string{"matrix"}},
+			},
+		},
+	},
+	reflect.TypeFor[ActionHttp](): {
+		atMostOneOf("body", "body_template"),
+	},
+}
+
+// JsonSchema describes the yaml flow config for editors, unknown fields are not allowed.
+func JsonSchema() ([]byte, error) {
+	generator := &schemaGenerator{
+		defs: make(map[string]any),
+	}
+
+	schema := generator.schemaFor(reflect.TypeFor[Config]())
+
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = "Flow"
+	schema["$defs"] = generator.defs
+
+	return json.Marshal(schema)
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+//nolint:cyclop
+func (g *schemaGenerator) schemaFor(valueType reflect.Type) map[string]any {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if enum, ok := schemaEnums[valueType]; ok {
+		return map[string]any{"type": "string", "enum": enum}
+	}
+
+	if valueType == reflect.TypeFor[time.Duration]() {
+		return map[string]any{
+			"description": "duration, e.g. 30s or 1m30s",
+			"type":        []string{"string", "integer"},
+		}
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(valueType.Elem())}
+
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(valueType.Elem())}
+
+	case reflect.Struct:
+		if valueType == reflect.TypeFor[Config]() {
+			return g.structSchema(valueType)
+		}
+
+		if _, ok := g.defs[valueType.Name()]; !ok {
+			// reserve the name first, so recursive types end in a reference
+			g.defs[valueType.Name()] = map[string]any{}
+			g.defs[valueType.Name()] = g.structSchema(valueType)
+		}
+
+		return map[string]any{"$ref": "#/$defs/" + valueType.Name()}
+
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(structType reflect.Type) map[string]any {
+	properties := make(map[string]any, structType.NumField())
+
+	for index := range structType.NumField() {
+		field := structType.Field(index)
+
+		name := yamlFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		properties[name] = g.schemaFor(field.Type)
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if required, ok := schemaRequired[structType]; ok {
+		schema["required"] = required
+	}
+
+	if rules, ok := schemaRules[structType]; ok {
+		schema["allOf"] = rules
+	}
+
+	return schema
+}
+
+// atMostOneOf is a schema rule rejecting objects with more than one of the fields.
+func atMostOneOf(fields ...string) map[string]any {
+	pairs := make([]any, 0)
+
+	for first := range fields {
+		for second := first + 1; second < len(fields); second++ {
+			pairs = append(pairs, map[string]any{"required": []string{fields[first], fields[second]}})
+		}
+	}
+
+	return map[string]any{
+		"not": map[string]any{"anyOf": pairs},
+	}
+}
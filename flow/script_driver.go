@@ -14,3 +14,9 @@ type ScriptDriver interface {
 	NewError(err error) any
 	Throw(err error)
 }
+
+// ScriptCompiler is implemented by script drivers able to check scripts before
+// they run, Validate compiles every inline script of the flow with it.
+type ScriptCompiler interface {
+	Compile(name string, script string) error
+}
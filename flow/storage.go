@@ -0,0 +1,148 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pixality-inc/golang-core/storage"
+)
+
+// storageOperationLocations tells for every operation whether its source and
+// destination are located in a storage (true) or on the local filesystem (false).
+var storageOperationLocations = map[StorageOperation][2]bool{
+	StorageOperationCopy:     {true, true},
+	StorageOperationMove:     {true, true},
+	StorageOperationUpload:   {false, true},
+	StorageOperationDownload: {true, false},
+	StorageOperationDelete:   {true, false},
+}
+
+func (f *Impl) validateActionStorage(action Action) error {
+	operation := action.Storage
+
+	locations, ok := storageOperationLocations[operation.Operation]
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrActionStorageInvalidOperation, operation.Operation)
+	}
+
+	if err := f.validateStorageLocation("source", operation.Source, locations[0]); err != nil {
+		return err
+	}
+
+	if operation.Operation == StorageOperationDelete {
+		if operation.Destination != nil {
+			return ErrActionStorageUnexpectedDestination
+		}
+
+		return nil
+	}
+
+	if operation.Destination == nil {
+		return ErrActionStorageNoDestination
+	}
+
+	return f.validateStorageLocation("destination", *operation.Destination, locations[1])
+}
+
+func (f *Impl) validateStorageLocation(name string, location StorageLocation, inStorage bool) error {
+	if location.Path == "" {
+		return fmt.Errorf("%w: %s", ErrActionStorageNoPath, name)
+	}
+
+	if !inStorage {
+		if location.Storage != "" {
+			return fmt.Errorf("%w: %s", ErrActionStorageNotLocal, name)
+		}
+
+		return nil
+	}
+
+	if location.Storage == "" {
+		return fmt.Errorf("%w: %s", ErrActionStorageNoStorage, name)
+	}
+
+	if _, ok := f.storages[location.Storage]; !ok {
+		return fmt.Errorf("%w: %s '%s'", ErrActionStorageUnknownStorage, name, location.Storage)
+	}
+
+	return nil
+}
+
+func (f *Impl) runActionStorage(ctx context.Context, env *Env, action Action) (*ActionResponse, error) {
+	log := f.log.GetLogger(ctx)
+
+	log.Debugf("Running action '%s' as storage %s", action.Name, action.Storage.Operation)
+
+	if err := f.validateActionStorage(action); err != nil {
+		return nil, err
+	}
+
+	operation := action.Storage
+
+	sourcePath, err := f.storageLocationPath(ctx, env, action, "source", operation.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	source := f.storages[operation.Source.Storage]
+
+	if operation.Operation == StorageOperationDelete {
+		if err = source.DeleteFile(ctx, sourcePath); err != nil {
+			return nil, fmt.Errorf("delete %s for action '%s': %w", sourcePath, action.Name, err)
+		}
+
+		return NewActionResponse().WithResult(sourcePath), nil
+	}
+
+	destinationPath, err := f.storageLocationPath(ctx, env, action, "destination", *operation.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	destination := f.storages[operation.Destination.Storage]
+
+	switch operation.Operation {
+	case StorageOperationCopy:
+		err = storage.Copy(ctx, destination, destinationPath, source, sourcePath)
+
+	case StorageOperationMove:
+		err = storage.Move(ctx, destination, destinationPath, source, sourcePath)
+
+	case StorageOperationUpload:
+		err = destination.WriteFile(ctx, destinationPath, sourcePath)
+
+	case StorageOperationDownload:
+		if err = os.MkdirAll(filepath.Dir(destinationPath), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(destinationPath), err)
+		}
+
+		err = source.DownloadFile(ctx, sourcePath, destinationPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s %s to %s for action '%s': %w", operation.Operation, sourcePath, destinationPath, action.Name, err)
+	}
+
+	return NewActionResponse().WithResult(destinationPath), nil
+}
+
+// storageLocationPath evaluates the location path, local paths are resolved against the action work dir.
+func (f *Impl) storageLocationPath(ctx context.Context, env *Env, action Action, name string, location StorageLocation) (string, error) {
+	locationPath, err := f.evalTemplate(ctx, env, "action."+action.Name+".storage."+name+".path", location.Path)
+	if err != nil {
+		return "", fmt.Errorf("eval template for action '%s' %s path: %w", action.Name, name, err)
+	}
+
+	if location.Storage != "" || filepath.IsAbs(locationPath) {
+		return locationPath, nil
+	}
+
+	workDir, err := f.actionWorkDir(ctx, env, action)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(workDir, locationPath), nil
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_cel"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowRunCel(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("build").
+				WithCommand(testEchoCommandToRun).
+				WithArgsScript(`["-n", env.BRANCH + "-" + string(width)]`).
+				WithWhen(`env.BRANCH == "main" && width > 0`),
+			flow.NewAction("deploy").
+				WithCommand(testEchoCommandToRun, "deploy").
+				WithWhen(`actions.build.exit_code == 0 && actions.build.stdout.startsWith("main")`).
+				WithDependsOn("build"),
+			flow.NewAction("renditions").
+				WithCommand(testEchoCommandToRun, "-n").
+				WithForEach(flow.NewActionForEachScript(`sizes.filter(size, size <= width).map(size, string(size) + "p")`)),
+			flow.NewAction("preview").
+				WithCommand(testEchoCommandToRun).
+				WithWhen(`env.BRANCH != "main"`),
+		},
+	}, nil, nil, flow_cel.NewCel(), nil)
+
+	require.NoError(t, flowEngine.Validate(context.Background()))
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", map[string]any{
+		"env":   map[string]any{"BRANCH": "main"},
+		"width": 1080,
+		"sizes": []int{360, 720, 1080, 2160},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, "main-1080", result.ActionsResponses["build"].Stdout)
+	require.False(t, result.ActionsResponses["deploy"].Skipped)
+	require.True(t, result.ActionsResponses["preview"].Skipped)
+	require.Equal(t, []string{"renditions[0]", "renditions[1]", "renditions[2]"}, result.ActionsResponses["renditions"].Instances)
+}
+
+func TestFlowValidateCel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		action flow.Action
+	}{
+		{
+			name:   "syntax",
+			action: flow.NewAction("build").WithCommand(testEchoCommandToRun).WithWhen(`env.BRANCH == `),
+		},
+		{
+			name:   "unknown_function",
+			action: flow.NewAction("build").WithCommand(testEchoCommandToRun).WithArgsScript(`[shell("ls")]`),
+		},
+		{
+			name:   "type_mismatch",
+			action: flow.NewAction("build").WithCommand(testEchoCommandToRun).WithWhen(`width > "wide"`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{tt.action},
+			}, nil, nil, flow_cel.NewCel(flow_cel.WithVariable("width", cel.IntType)), nil)
+
+			require.ErrorIs(t, flowEngine.Validate(context.Background()), flow_cel.ErrCompileFailed)
+		})
+	}
+}
+
+func TestCel_Values(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_cel.NewCel()
+
+	env := flow.NewEnv("", map[string]any{"tags": []string{"a", "b"}, "size": 42})
+
+	value, err := driver.Execute(context.Background(), env, "map", `{"tags": tags, "size": size, "half": double(size) / 2.0}`)
+	require.NoError(t, err)
+
+	mapValue, err := driver.ValueToMapStringAny(value)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"tags": []any{"a", "b"}, "size": int64(42), "half": 21.0}, mapValue)
+
+	value, err = driver.Execute(context.Background(), env, "env", `{"SIZE": size, "FIRST": tags[0]}`)
+	require.NoError(t, err)
+
+	mapStringValue, err := driver.ValueToMapStringString(value)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"SIZE": "42", "FIRST": "a"}, mapStringValue)
+
+	_, err = driver.Execute(context.Background(), env, "cost", `[1, 2, 3].map(a, [1, 2, 3].map(b, [1, 2, 3].map(c, a * b * c)))`)
+	require.NoError(t, err)
+
+	limited := flow_cel.NewCel(flow_cel.WithCostLimit(10))
+
+	_, err = limited.Execute(context.Background(), env, "cost", `[1, 2, 3].map(a, [1, 2, 3].map(b, [1, 2, 3].map(c, a * b * c)))`)
+	require.Error(t, err)
+
+	_, err = driver.ValueToBool(value)
+	require.ErrorIs(t, err, flow_cel.ErrWrongValue)
+}
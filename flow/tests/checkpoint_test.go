@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/pixality-inc/golang-core/storage"
+	"github.com/pixality-inc/golang-core/storage/providers"
+	"github.com/stretchr/testify/require"
+)
+
+// newCheckpointFlow counts the runs of every action in dir, the check action fails until dir/ready exists.
+func newCheckpointFlow(dir string, store flow.CheckpointStore) flow.Flow {
+	return flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("prepare").
+				WithCommand(testShellCommandToRun, "-c", "echo run >> "+filepath.Join(dir, "prepare")+"; echo {{ .name }}").
+				WithOutput("name", "{{ .actions.prepare.stdout }}"),
+			flow.NewAction("check").
+				WithCommand(testShellCommandToRun, "-c", "echo run >> "+filepath.Join(dir, "check")+"; test -f "+filepath.Join(dir, "ready")),
+			flow.NewAction("report").
+				WithCommand(testEchoCommandToRun, "{{ .actions.prepare.outputs.name }}"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil, flow.WithCheckpoints(store))
+}
+
+func countRuns(t *testing.T, filename string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return 0
+	}
+
+	require.NoError(t, err)
+
+	return len(data) / len("run\n")
+}
+
+func TestFlowResume(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	flowEngine := newCheckpointFlow(dir, flow.NewFileCheckpointStore(filepath.Join(dir, "checkpoints")))
+	env := flow.NewEnv("", map[string]any{"name": "world"})
+
+	result, err := flowEngine.Run(context.Background(), env)
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+	require.NotEmpty(t, result.RunId)
+	require.FileExists(t, filepath.Join(dir, "checkpoints", result.RunId+".json"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ready"), nil, 0o600))
+
+	resumed, err := flowEngine.Resume(context.Background(), result.RunId, env)
+	require.NoError(t, err)
+	require.Equal(t, result.RunId, resumed.RunId)
+
+	require.True(t, resumed.ActionsResponses["prepare"].Restored)
+	require.False(t, resumed.ActionsResponses["check"].Restored)
+	require.Equal(t, "world\n", resumed.ActionsResponses["report"].Stdout)
+
+	require.Equal(t, 1, countRuns(t, filepath.Join(dir, "prepare")))
+	require.Equal(t, 2, countRuns(t, filepath.Join(dir, "check")))
+
+	// every action succeeded, nothing runs again
+	resumed, err = flowEngine.Resume(context.Background(), result.RunId, env)
+	require.NoError(t, err)
+	require.True(t, resumed.ActionsResponses["report"].Restored)
+	require.Equal(t, 2, countRuns(t, filepath.Join(dir, "check")))
+}
+
+func TestFlowResume_InputsChanged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	flowEngine := newCheckpointFlow(dir, flow.NewFileCheckpointStore(dir))
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", map[string]any{"name": "world"}))
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ready"), nil, 0o600))
+
+	resumed, err := flowEngine.Resume(context.Background(), result.RunId, flow.NewEnv("", map[string]any{"name": "flow"}))
+	require.NoError(t, err)
+
+	require.False(t, resumed.ActionsResponses["prepare"].Restored)
+	require.Equal(t, "flow\n", resumed.ActionsResponses["report"].Stdout)
+	require.Equal(t, 2, countRuns(t, filepath.Join(dir, "prepare")))
+}
+
+func TestFlowResume_StorageStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	checkpointsStorage := storage.NewStorage(providers.NewOsProvider(filepath.Join(dir, "storage")), providers.NewNoUrlProvider(""))
+	flowEngine := newCheckpointFlow(dir, flow.NewStorageCheckpointStore(checkpointsStorage, "checkpoints"))
+	env := flow.NewEnv("", map[string]any{"name": "world"})
+
+	result, err := flowEngine.Run(context.Background(), env)
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ready"), nil, 0o600))
+
+	resumed, err := flowEngine.Resume(context.Background(), result.RunId, env)
+	require.NoError(t, err)
+	require.True(t, resumed.ActionsResponses["prepare"].Restored)
+	require.Equal(t, 1, countRuns(t, filepath.Join(dir, "prepare")))
+}
+
+func TestFlowResume_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := flow.New(&flow.Config{}, nil, nil, nil, nil).Resume(context.Background(), "run", nil)
+	require.ErrorIs(t, err, flow.ErrNoCheckpointStore)
+
+	flowEngine := newCheckpointFlow(dir, flow.NewFileCheckpointStore(dir))
+
+	_, err = flowEngine.Resume(context.Background(), "unknown", nil)
+	require.ErrorIs(t, err, flow.ErrCheckpointNotFound)
+
+	_, err = flowEngine.Resume(context.Background(), "../unknown", nil)
+	require.ErrorIs(t, err, flow.ErrCheckpointInvalidRunId)
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfigYaml = `
+max_parallel: 2
+actions:
+  - name: build
+    command: make
+    args: [build]
+    timeout: 5m
+    retry:
+      attempts: 3
+      initial_interval: 1s
+  - name: upload
+    storage:
+      operation: upload
+      source:
+        path: out/app.tar
+      destination:
+        storage: artifacts
+        path: app.tar
+    depends_on: [build]
+`
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	config, err := flow.ParseConfig([]byte(testConfigYaml))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, config.MaxParallel)
+	require.Len(t, config.Actions, 2)
+	require.Equal(t, 5*time.Minute, config.Actions[0].Timeout)
+	require.Equal(t, 3, config.Actions[0].Retry.AttemptsValue)
+	require.Equal(t, flow.StorageOperationUpload, config.Actions[1].Storage.Operation)
+}
+
+func TestParseConfig_UnknownFields(t *testing.T) {
+	t.Parallel()
+
+	_, err := flow.ParseConfig([]byte(`
+actions:
+  - name: build
+    command: make
+    fail_if_non_zero: true
+    retry:
+      atempts: 3
+max_paralel: 2
+`))
+	require.ErrorIs(t, err, flow.ErrConfigUnknownField)
+
+	var configErrors []*flow.ConfigError
+
+	for _, joined := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var configError *flow.ConfigError
+
+		require.True(t, errors.As(joined, &configError))
+
+		configErrors = append(configErrors, configError)
+	}
+
+	require.Len(t, configErrors, 3)
+
+	require.Equal(t, "actions[0].fail_if_non_zero", configErrors[0].Path)
+	require.Equal(t, 5, configErrors[0].Line)
+	require.Equal(t, 5, configErrors[0].Column)
+
+	require.Equal(t, "actions[0].retry.atempts", configErrors[1].Path)
+	require.Equal(t, 7, configErrors[1].Line)
+	require.Equal(t, 7, configErrors[1].Column)
+
+	require.Equal(t, "max_paralel", configErrors[2].Path)
+	require.EqualError(t, configErrors[2], "line 8, column 1: max_paralel: unknown field")
+
+	_, err = flow.ParseConfig([]byte(`actions: {name: build}`))
+	require.ErrorIs(t, err, flow.ErrConfigInvalid)
+}
+
+func TestFlowValidate_CrossField(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  flow.Action
+		wantErr error
+	}{
+		{
+			name:    "script_and_script_file",
+			action:  flow.NewAction("run").WithScript("1").WithScriptFile("run.js"),
+			wantErr: flow.ErrActionTooManyOptions,
+		},
+		{
+			name:    "args_and_args_script",
+			action:  flow.NewAction("run").WithCommand(testEchoCommandToRun, "a").WithArgsScript(`["b"]`),
+			wantErr: flow.ErrActionArgsTooManyOptions,
+		},
+		{
+			name:    "env_template_and_env_script",
+			action:  flow.NewAction("run").WithCommand(testEchoCommandToRun).WithEnvTemplate("{}").WithEnvScript("({})"),
+			wantErr: flow.ErrActionEnvTooManyOptions,
+		},
+		{
+			name:    "unknown_trigger",
+			action:  flow.NewAction("notify").WithTrigger(flow.NewActionTrigger("slack")),
+			wantErr: flow.ErrTriggerNotFound,
+		},
+		{
+			name:    "negative_timeout",
+			action:  flow.NewAction("run").WithCommand(testEchoCommandToRun).WithTimeout(-time.Second),
+			wantErr: flow.ErrActionInvalidTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{tt.action},
+			}, nil, nil, nil, nil)
+
+			require.ErrorIs(t, flowEngine.Validate(context.Background()), tt.wantErr)
+		})
+	}
+}
+
+func TestJsonSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaJson, err := flow.JsonSchema()
+	require.NoError(t, err)
+
+	schemaDocument, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJson))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+
+	require.NoError(t, compiler.AddResource("flow.json", schemaDocument))
+
+	schema, err := compiler.Compile("flow.json")
+	require.NoError(t, err)
+
+	yamlToInstance := func(source string) any {
+		var document any
+
+		require.NoError(t, yaml.Unmarshal([]byte(source), &document))
+
+		documentJson, err := json.Marshal(document)
+		require.NoError(t, err)
+
+		instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(documentJson))
+		require.NoError(t, err)
+
+		return instance
+	}
+
+	require.NoError(t, schema.Validate(yamlToInstance(testConfigYaml)))
+
+	require.Error(t, schema.Validate(yamlToInstance(`
+actions:
+  - name: build
+    command: make
+    fail_if_non_zero: true
+`)))
+
+	require.Error(t, schema.Validate(yamlToInstance(`
+actions:
+  - name: build
+    script: "1"
+    script_file: build.js
+`)))
+
+	require.Error(t, schema.Validate(yamlToInstance(`
+actions:
+  - name: upload
+    storage:
+      operation: rename
+      source:
+        path: app.tar
+`)))
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/stretchr/testify/require"
+)
+
+const testSleepCommandToRun = "sleep"
+
+func TestFlowValidateDependencies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		actions []flow.Action
+		wantErr error
+	}{
+		{
+			name: "valid",
+			actions: []flow.Action{
+				flow.NewAction("a"),
+				flow.NewAction("b").WithDependsOn("a"),
+				flow.NewAction("c").WithDependsOn("a", "b"),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown_dependency",
+			actions: []flow.Action{
+				flow.NewAction("a").WithDependsOn("missing"),
+			},
+			wantErr: flow.ErrActionUnknownDependency,
+		},
+		{
+			name: "self_dependency",
+			actions: []flow.Action{
+				flow.NewAction("a").WithDependsOn("a"),
+			},
+			wantErr: flow.ErrActionDependencyCycle,
+		},
+		{
+			name: "cycle",
+			actions: []flow.Action{
+				flow.NewAction("a").WithDependsOn("c"),
+				flow.NewAction("b").WithDependsOn("a"),
+				flow.NewAction("c").WithDependsOn("b"),
+				flow.NewAction("d"),
+			},
+			wantErr: flow.ErrActionDependencyCycle,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{Actions: testCase.actions}, nil, nil, nil, nil)
+
+			err := flowEngine.Validate(ctx)
+
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFlowValidateDependencies_CyclePath(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("a").WithDependsOn("b"),
+			flow.NewAction("b").WithDependsOn("a"),
+		},
+	}, nil, nil, nil, nil)
+
+	err := flowEngine.Validate(context.Background())
+	require.ErrorIs(t, err, flow.ErrActionDependencyCycle)
+	require.ErrorContains(t, err, "a -> b -> a")
+}
+
+func TestFlowRunDependencies_Parallel(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("prepare").WithCommand(testCommandToRun),
+			flow.NewAction("rendition_1").WithCommand(testSleepCommandToRun, "0.3").WithDependsOn("prepare"),
+			flow.NewAction("rendition_2").WithCommand(testSleepCommandToRun, "0.3").WithDependsOn("prepare"),
+			flow.NewAction("rendition_3").WithCommand(testSleepCommandToRun, "0.3").WithDependsOn("prepare"),
+			flow.NewAction("publish").WithCommand(testCommandToRun).WithDependsOn("rendition_1", "rendition_2", "rendition_3"),
+		},
+	}, nil, nil, nil, nil)
+
+	startedAt := time.Now()
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, result.ActionsResponses, 5)
+	require.Less(t, time.Since(startedAt), 800*time.Millisecond)
+
+	prepare := result.ActionsResponses["prepare"]
+	publish := result.ActionsResponses["publish"]
+
+	for _, name := range []string{"rendition_1", "rendition_2", "rendition_3"} {
+		rendition := result.ActionsResponses[name]
+
+		require.False(t, rendition.StartedAt.Before(prepare.FinishedAt), name)
+		require.False(t, publish.StartedAt.Before(rendition.FinishedAt), name)
+	}
+}
+
+func TestFlowRunDependencies_MaxParallel(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		MaxParallel: 1,
+		Actions: []flow.Action{
+			flow.NewAction("a").WithCommand(testSleepCommandToRun, "0.1"),
+			flow.NewAction("b").WithCommand(testSleepCommandToRun, "0.1"),
+			flow.NewAction("c").WithCommand(testCommandToRun).WithDependsOn("a", "b"),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	a := result.ActionsResponses["a"]
+	b := result.ActionsResponses["b"]
+
+	require.False(t, b.StartedAt.Before(a.FinishedAt))
+}
+
+func TestFlowRunDependencies_FailureStopsDependents(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("a").WithCommand(testFailedCommandToRun),
+			flow.NewAction("b").WithCommand(testCommandToRun).WithDependsOn("a"),
+			flow.NewAction("c").WithCommand(testCommandToRun).WithDependsOn("b"),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+	require.ErrorContains(t, err, "action 'a' failed")
+	require.Len(t, result.ActionsResponses, 1)
+}
+
+func TestFlowRunDependencies_UnknownDependency(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("a").WithCommand(testCommandToRun).WithDependsOn("missing"),
+		},
+	}, nil, nil, nil, nil)
+
+	_, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionUnknownDependency)
+}
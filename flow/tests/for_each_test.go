@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowRunForEach_Items(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("rendition").
+				WithCommand(testEchoCommandToRun, "{{ .index }}:{{ .item }}").
+				WithForEach(flow.NewActionForEachItems("360p", "720p")).
+				WithOutput("size", "{{ .item }}"),
+			flow.NewAction("report").
+				WithCommand(testEchoCommandToRun).
+				WithArgsTemplate(`["{{ index .actions.rendition.outputs.size 1 }}", "{{ len .actions.rendition.instances }}"]`).
+				WithDependsOn("rendition"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", nil))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"rendition[0]", "rendition[1]"}, result.ActionsResponses["rendition"].Instances)
+	require.Equal(t, "0:360p\n", result.ActionsResponses["rendition[0]"].Stdout)
+	require.Equal(t, "1:720p\n", result.ActionsResponses["rendition[1]"].Stdout)
+	require.Equal(t, "720p 2\n", result.ActionsResponses["report"].Stdout)
+}
+
+func TestFlowRunForEach_Template(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("hello").
+				WithCommand(testEchoCommandToRun, "hello {{ .item }}").
+				WithForEach(flow.NewActionForEachTemplate(`["{{ .first }}", "{{ .second }}"]`)),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", map[string]any{"first": "foo", "second": "bar"}))
+	require.NoError(t, err)
+
+	require.Equal(t, "hello foo\n", result.ActionsResponses["hello[0]"].Stdout)
+	require.Equal(t, "hello bar\n", result.ActionsResponses["hello[1]"].Stdout)
+}
+
+func TestFlowRunForEach_Script(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("hello").
+				WithCommand(testEchoCommandToRun, "hello {{ .item }}").
+				WithForEach(flow.NewActionForEachScript(`names.split(",")`)),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), flow_goja.NewGoja(), nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", map[string]any{"names": "a,b,c"}))
+	require.NoError(t, err)
+
+	require.Len(t, result.ActionsResponses["hello"].Instances, 3)
+	require.Equal(t, "hello c\n", result.ActionsResponses["hello[2]"].Stdout)
+}
+
+func TestFlowRunForEach_Matrix(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("build").
+				WithCommand(testEchoCommandToRun, "{{ .matrix.os }}-{{ .matrix.arch }}").
+				WithForEach(flow.NewActionForEachMatrix(map[string][]any{
+					"os":   {"linux", "darwin"},
+					"arch": {"amd64", "arm64"},
+				}).WithParallel(0)),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", nil))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"build[arch=amd64,os=linux]",
+		"build[arch=amd64,os=darwin]",
+		"build[arch=arm64,os=linux]",
+		"build[arch=arm64,os=darwin]",
+	}, result.ActionsResponses["build"].Instances)
+	require.Equal(t, "darwin-arm64\n", result.ActionsResponses["build[arch=arm64,os=darwin]"].Stdout)
+}
+
+func TestFlowRunForEach_ParallelLimit(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("sleep").
+				WithCommand(testSleepCommandToRun, "{{ .item }}").
+				WithForEach(flow.NewActionForEachItems("0.3", "0.3", "0.3", "0.3").WithParallel(2)),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	startedAt := time.Now()
+
+	_, err := flowEngine.Run(context.Background(), flow.NewEnv("", nil))
+	require.NoError(t, err)
+
+	elapsed := time.Since(startedAt)
+	require.GreaterOrEqual(t, elapsed, 600*time.Millisecond)
+	require.Less(t, elapsed, 1200*time.Millisecond)
+}
+
+func TestFlowRunForEach_InstanceFailed(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("check").
+				WithCommand(testShellCommandToRun, "-c", "exit {{ .item }}").
+				WithForEach(flow.NewActionForEachItems(0, 3, 0)),
+			flow.NewAction("never").WithCommand(testEchoCommandToRun, "never"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", nil))
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+	require.ErrorContains(t, err, "action 'check[1]' failed")
+
+	require.True(t, result.ActionsResponses["check"].Failed)
+	require.NotContains(t, result.ActionsResponses, "check[2]")
+	require.NotContains(t, result.ActionsResponses, "never")
+}
+
+func TestFlowValidateForEach(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		forEach flow.ActionForEach
+		wantErr error
+	}{
+		{
+			name:    "items",
+			forEach: flow.NewActionForEachItems(),
+			wantErr: nil,
+		},
+		{
+			name:    "no_options",
+			forEach: flow.ActionForEach{},
+			wantErr: flow.ErrActionForEachNoOptions,
+		},
+		{
+			name: "too_many_options",
+			forEach: flow.ActionForEach{
+				Template: "[]",
+				Script:   "[]",
+			},
+			wantErr: flow.ErrActionForEachTooManyOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{
+					flow.NewAction("action").WithCommand(testEchoCommandToRun).WithForEach(tt.forEach),
+				},
+			}, nil, nil, nil, nil)
+
+			err := flowEngine.Validate(context.Background())
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/stretchr/testify/require"
+)
+
+var errGojaTest = errors.New("goja test error")
+
+func TestGoja_Timeout(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja(flow_goja.WithTimeout(50 * time.Millisecond))
+
+	_, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "loop", "while (true) {}")
+	require.ErrorIs(t, err, flow_goja.ErrScriptTimeout)
+}
+
+func TestGoja_ContextCancel(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := driver.Execute(ctx, flow.NewEnv("", nil), "loop", "while (true) {}")
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = driver.Execute(ctx, flow.NewEnv("", nil), "cancelled", "1 + 1")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGoja_StackOverflow(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja(flow_goja.WithMaxCallStackSize(100))
+
+	_, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "recursion", "function f(n) { return f(n + 1) } f(0)")
+	require.ErrorIs(t, err, flow_goja.ErrScriptStackOverflow)
+
+	result, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "shallow", "function f(n) { return n > 0 ? f(n - 1) : 'done' } f(50)")
+	require.NoError(t, err)
+
+	value, err := driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, "done", value)
+}
+
+func TestGoja_FreshRuntime(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja()
+
+	_, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "define", "var leaked = 1")
+	require.NoError(t, err)
+
+	result, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "read", "typeof leaked")
+	require.NoError(t, err)
+
+	value, err := driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, "undefined", value)
+
+	var wg sync.WaitGroup
+
+	for index := range 8 {
+		wg.Go(func() {
+			result, err := driver.Execute(context.Background(), flow.NewEnv("", map[string]any{"index": index}), "concurrent", "index * 2")
+			require.NoError(t, err)
+
+			value, err := driver.ValueToString(result)
+			require.NoError(t, err)
+			require.Equal(t, strconv.Itoa(index*2), value)
+		})
+	}
+
+	wg.Wait()
+}
+
+func TestGoja_Modules(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"helpers/format.js": {Data: []byte(`const util = require("./util"); exports.format = (name) => util.upper("hello ") + name`)},
+		"helpers/util.js":   {Data: []byte(`module.exports = { upper: (value) => value.toUpperCase() }`)},
+		"secret.js":         {Data: []byte(`exports.secret = "secret"`)},
+	}
+
+	driver := flow_goja.NewGoja(flow_goja.WithModules(fsys, "helpers/format.js", "helpers/util"))
+
+	result, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "format", `require("./helpers/format").format("world")`)
+	require.NoError(t, err)
+
+	value, err := driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, "HELLO world", value)
+
+	_, err = driver.Execute(context.Background(), flow.NewEnv("", nil), "secret", `require("secret")`)
+	require.ErrorIs(t, err, flow_goja.ErrModuleNotAllowed)
+
+	_, err = driver.Execute(context.Background(), flow.NewEnv("", nil), "escape", `require("../helpers/format")`)
+	require.ErrorIs(t, err, flow_goja.ErrModuleNotAllowed)
+
+	result, err = driver.Execute(context.Background(), flow.NewEnv("", nil), "catch", `try { require("secret"); "loaded" } catch (e) { "denied" }`)
+	require.NoError(t, err)
+
+	value, err = driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, "denied", value)
+}
+
+func TestGoja_NoRequireByDefault(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja()
+
+	result, err := driver.Execute(context.Background(), flow.NewEnv("", nil), "require", "typeof require")
+	require.NoError(t, err)
+
+	value, err := driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, "undefined", value)
+}
+
+func TestGoja_GoValuesInScript(t *testing.T) {
+	t.Parallel()
+
+	driver := flow_goja.NewGoja()
+
+	env := flow.NewEnv("", map[string]any{
+		"obj": func() any {
+			value, err := driver.AnyToValue(map[string]any{"a": 1})
+			require.NoError(t, err)
+
+			return value
+		},
+		"fail": func() (any, error) {
+			err, _ := driver.NewError(errGojaTest).(error)
+
+			return nil, err
+		},
+		"abort": func() any {
+			driver.Throw(errGojaTest)
+
+			return nil
+		},
+	})
+
+	for range 2 {
+		result, err := driver.Execute(context.Background(), env, "object", "obj().a + 1")
+		require.NoError(t, err)
+
+		value, err := driver.ValueToString(result)
+		require.NoError(t, err)
+		require.Equal(t, "2", value)
+	}
+
+	result, err := driver.Execute(context.Background(), env, "catch", `try { fail(); "passed" } catch (e) { e.message }`)
+	require.NoError(t, err)
+
+	value, err := driver.ValueToString(result)
+	require.NoError(t, err)
+	require.Equal(t, errGojaTest.Error(), value)
+
+	_, err = driver.Execute(context.Background(), env, "throw", "abort()")
+	require.ErrorIs(t, err, errGojaTest)
+}
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/pixality-inc/golang-core/http_client"
+	"github.com/pixality-inc/golang-core/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHttpClient(t *testing.T) http_client.Client {
+	t.Helper()
+
+	client, err := http_client.NewClientImpl(
+		logger.NewLoggableImplWithService("test"),
+		&http_client.ConfigYaml{TimeoutValue: 5 * time.Second},
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestFlowRunHttp(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var request map[string]any
+
+		_ = json.Unmarshal(body, &request)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"token":  r.Header.Get("Authorization"),
+			"type":   r.Header.Get("Content-Type"),
+			"name":   request["name"],
+		})
+	}))
+	defer server.Close()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("webhook").
+				WithHttp(flow.NewActionHttp("post", server.URL+"/hooks/{{ .hook }}").
+					WithHeader("Authorization", "Bearer {{ .token }}").
+					WithBodyTemplate(`{"name": "{{ .hook }}"}`).
+					WithExpectedStatus(http.StatusCreated)).
+				WithStdoutJson().
+				WithOutput("path", "{{ .actions.webhook.json.path }}"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil, flow.WithHttpClient(newTestHttpClient(t)))
+
+	require.NoError(t, flowEngine.Validate(context.Background()))
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", map[string]any{"hook": "deploy", "token": "secret"}))
+	require.NoError(t, err)
+
+	response := result.ActionsResponses["webhook"]
+	require.Equal(t, http.StatusCreated, response.StatusCode)
+	require.Equal(t, "/hooks/deploy", response.Outputs["path"])
+	require.JSONEq(t, `{
+		"method": "POST",
+		"path": "/hooks/deploy",
+		"token": "Bearer secret",
+		"type": "application/json",
+		"name": "deploy"
+	}`, response.Stdout)
+}
+
+func TestFlowRunHttp_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newTestHttpClient(t)
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("fetch").WithHttp(flow.NewActionHttp("", server.URL)),
+		},
+	}, nil, nil, nil, nil, flow.WithHttpClient(client))
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionHttpUnexpectedStatus)
+	require.Equal(t, http.StatusNotFound, result.ActionsResponses["fetch"].StatusCode)
+
+	flowEngine = flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("fetch").WithHttp(flow.NewActionHttp("", server.URL).WithExpectedStatus(http.StatusNotFound)),
+		},
+	}, nil, nil, nil, nil, flow.WithHttpClient(client))
+
+	_, err = flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+}
+
+func TestFlowValidateHttp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		request  flow.ActionHttp
+		noClient bool
+		wantErr  error
+	}{
+		{
+			name:     "no_client",
+			request:  flow.NewActionHttp("GET", "http://localhost"),
+			noClient: true,
+			wantErr:  flow.ErrNoHttpClient,
+		},
+		{
+			name:    "no_url",
+			request: flow.NewActionHttp("GET", ""),
+			wantErr: flow.ErrActionHttpNoUrl,
+		},
+		{
+			name:    "invalid_method",
+			request: flow.NewActionHttp("FETCH", "http://localhost"),
+			wantErr: flow.ErrActionHttpInvalidMethod,
+		},
+		{
+			name:    "body_too_many_options",
+			request: flow.NewActionHttp("POST", "http://localhost").WithBody(map[string]any{}).WithBodyTemplate("{}"),
+			wantErr: flow.ErrActionHttpBodyTooManyOptions,
+		},
+		{
+			name:    "invalid_expected_status",
+			request: flow.NewActionHttp("GET", "http://localhost").WithExpectedStatus(42),
+			wantErr: flow.ErrActionHttpInvalidExpectedStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := []flow.Option{flow.WithHttpClient(newTestHttpClient(t))}
+
+			if tt.noClient {
+				options = nil
+			}
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{
+					flow.NewAction("request").WithHttp(tt.request),
+				},
+			}, nil, nil, nil, nil, options...)
+
+			require.ErrorIs(t, flowEngine.Validate(context.Background()), tt.wantErr)
+		})
+	}
+}
+
+func TestFlowRunHttp_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+
+	require.NoError(t, listener.Close())
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("webhook").WithHttp(flow.NewActionHttp("post", "http://"+address+"/hooks")),
+		},
+	}, nil, nil, nil, nil, flow.WithHttpClient(newTestHttpClient(t)))
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionHttpFailed)
+	require.ErrorContains(t, err, "connection refused")
+	require.NotNil(t, result)
+	require.NotContains(t, result.ActionsResponses, "webhook")
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageFlow = `
+inputs:
+  name:
+    type: string
+    required: true
+  size:
+    type: integer
+    default: 1
+  compress:
+    type: boolean
+outputs:
+  archive: "{{ .actions.pack.outputs.file }}"
+  scope: "{{ .app }}"
+  packed: "{{ .actions.pack.stdout }}"
+actions:
+  - name: pack
+    command: echo
+    args_script: '[name, String(size * 2), String(compress)]'
+    outputs:
+      file: "{{ .name }}.tar"
+`
+
+func writeFlowFile(t *testing.T, filename string, content string) string {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o750))
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+
+	return filename
+}
+
+func TestFlowRunInclude(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeFlowFile(t, filepath.Join(dir, "lib", "package.yaml"), testPackageFlow)
+	mainFilename := writeFlowFile(t, filepath.Join(dir, "main.yaml"), `
+actions:
+  - name: package
+    include:
+      path: lib/package.yaml
+      inputs:
+        name: "{{ .app }}"
+        size: "3"
+        compress: true
+  - name: upload
+    command: echo
+    args: ["{{ .actions.package.outputs.archive }}"]
+    depends_on: [package]
+`)
+
+	config, err := flow.LoadConfig(mainFilename)
+	require.NoError(t, err)
+
+	flowEngine := flow.New(config, nil, flow_gotemplate.NewGoTemplate(), flow_goja.NewGoja(), nil)
+
+	require.NoError(t, flowEngine.Validate(context.Background()))
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv(dir, map[string]any{"app": "api"}))
+	require.NoError(t, err)
+
+	response := result.ActionsResponses["package"]
+	require.Equal(t, filepath.Join(dir, "lib", "package.yaml"), response.Result)
+	require.Equal(t, "api.tar", response.Outputs["archive"])
+	require.NotContains(t, response.Outputs["scope"], "api")
+	require.Equal(t, "api 6 true", response.Outputs["packed"])
+
+	require.Equal(t, "api.tar\n", result.ActionsResponses["upload"].Stdout)
+}
+
+func TestFlowRunInclude_Defaults(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("package").
+				WithInclude(flow.NewActionInclude(writeFlowFile(t, filepath.Join(dir, "package.yaml"), testPackageFlow)).
+					WithInput("name", "web")),
+			flow.NewAction("print").
+				WithCommand(testEchoCommandToRun, "{{ .actions.package.outputs.archive }}").
+				WithDependsOn("package"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), flow_goja.NewGoja(), nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "web.tar\n", result.ActionsResponses["print"].Stdout)
+}
+
+func TestFlowValidateInclude(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	packageFilename := writeFlowFile(t, filepath.Join(dir, "package.yaml"), testPackageFlow)
+
+	writeFlowFile(t, filepath.Join(dir, "a.yaml"), `
+actions:
+  - name: b
+    include:
+      path: nested/b.yaml
+`)
+	writeFlowFile(t, filepath.Join(dir, "nested", "b.yaml"), `
+actions:
+  - name: a
+    include:
+      path: ../a.yaml
+`)
+
+	tests := []struct {
+		name    string
+		include flow.ActionInclude
+		wantErr error
+	}{
+		{
+			name:    "no_path",
+			include: flow.NewActionInclude(""),
+			wantErr: flow.ErrActionIncludeNoPath,
+		},
+		{
+			name:    "missing_input",
+			include: flow.NewActionInclude(packageFilename),
+			wantErr: flow.ErrActionIncludeMissingInput,
+		},
+		{
+			name:    "unknown_input",
+			include: flow.NewActionInclude(packageFilename).WithInput("name", "api").WithInput("color", "red"),
+			wantErr: flow.ErrActionIncludeUnknownInput,
+		},
+		{
+			name:    "recursion",
+			include: flow.NewActionInclude(filepath.Join(dir, "a.yaml")),
+			wantErr: flow.ErrActionIncludeRecursion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{
+					flow.NewAction("include").WithInclude(tt.include),
+				},
+			}, nil, nil, nil, nil)
+
+			require.ErrorIs(t, flowEngine.Validate(context.Background()), tt.wantErr)
+		})
+	}
+}
+
+func TestFlowRunInclude_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	recursiveFilename := writeFlowFile(t, filepath.Join(dir, "self.yaml"), `
+actions:
+  - name: self
+    include:
+      path: self.yaml
+`)
+
+	config, err := flow.LoadConfig(recursiveFilename)
+	require.NoError(t, err)
+
+	_, err = flow.New(config, nil, nil, nil, nil).Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionIncludeRecursion)
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("package").WithInclude(
+				flow.NewActionInclude(writeFlowFile(t, filepath.Join(dir, "package.yaml"), testPackageFlow)).
+					WithInput("name", "api").
+					WithInput("size", "large"),
+			),
+		},
+	}, nil, nil, flow_goja.NewGoja(), nil)
+
+	_, err = flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionIncludeInvalidInput)
+}
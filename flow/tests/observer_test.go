@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/clock"
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/pixality-inc/golang-core/logger"
+	"github.com/pixality-inc/golang-core/metrics"
+	"github.com/pixality-inc/golang-core/metrics/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	events []flow.Event
+	mutex  sync.Mutex
+}
+
+func (o *recordingObserver) OnEvent(_ context.Context, event flow.Event) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) actionEvents(action string) []flow.EventType {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	types := make([]flow.EventType, 0)
+
+	for _, event := range o.events {
+		if event.Action == action {
+			types = append(types, event.Type)
+		}
+	}
+
+	return types
+}
+
+func (o *recordingObserver) last(action string) flow.Event {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for index := len(o.events) - 1; index >= 0; index-- {
+		if o.events[index].Action == action {
+			return o.events[index]
+		}
+	}
+
+	return flow.Event{}
+}
+
+func TestFlowObserver(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{}
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("hello").WithCommand(testEchoCommandToRun, "hello"),
+			flow.NewAction("flaky").
+				WithCommand(testShellCommandToRun, "-c", "exit 3").
+				WithRetry(flow.NewActionRetry(2).WithBackoff(time.Millisecond, 1, time.Millisecond)).
+				WithContinueOnError(true).
+				WithDependsOn("hello"),
+			flow.NewAction("never").
+				WithCommand(testEchoCommandToRun).
+				WithWhen("false").
+				WithDependsOn("hello"),
+		},
+	}, nil, nil, flow_goja.NewGoja(), nil, flow.WithObservers(observer))
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, flow.EventFlowStarted, observer.events[0].Type)
+	require.Equal(t, flow.EventFlowFinished, observer.events[len(observer.events)-1].Type)
+	require.Equal(t, result.RunId, observer.events[0].RunId)
+
+	require.Equal(t, []flow.EventType{
+		flow.EventActionQueued,
+		flow.EventActionStarted,
+		flow.EventActionSucceeded,
+	}, observer.actionEvents("hello"))
+
+	hello := observer.last("hello")
+	require.Equal(t, len("hello\n"), hello.StdoutSize)
+	require.Equal(t, result.RunId, hello.RunId)
+	require.False(t, hello.Timestamp.IsZero())
+
+	require.Equal(t, []flow.EventType{
+		flow.EventActionQueued,
+		flow.EventActionStarted,
+		flow.EventActionRetried,
+		flow.EventActionFailed,
+	}, observer.actionEvents("flaky"))
+
+	flaky := observer.last("flaky")
+	require.Equal(t, 3, flaky.ExitCode)
+	require.NotEmpty(t, flaky.Error)
+
+	require.Equal(t, flow.EventActionSkipped, observer.last("never").Type)
+}
+
+func TestFlowObserver_Json(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("fail").WithCommand(testShellCommandToRun, "-c", "echo oops >&2; exit 2"),
+		},
+	}, nil, nil, nil, nil, flow.WithObservers(flow.NewJsonObserver(&buffer)))
+
+	_, err := flowEngine.Run(context.Background(), nil)
+	require.Error(t, err)
+
+	events := make([]map[string]any, 0)
+
+	scanner := bufio.NewScanner(&buffer)
+
+	for scanner.Scan() {
+		var event map[string]any
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 5)
+	require.Equal(t, "action_failed", events[3]["type"])
+	require.Equal(t, "fail", events[3]["action"])
+	require.InEpsilon(t, 2, events[3]["exit_code"], 0)
+	require.InEpsilon(t, len("oops\n"), events[3]["stderr_size"], 0)
+	require.Equal(t, "flow_finished", events[4]["type"])
+	require.NotEmpty(t, events[4]["error"])
+}
+
+func TestFlowObserver_Metrics(t *testing.T) {
+	t.Parallel()
+
+	manager := metrics.New(drivers.NewPrometheusDriver(false, false), clock.Default)
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("first").WithCommand(testEchoCommandToRun),
+			flow.NewAction("second").WithCommand(testEchoCommandToRun).WithDependsOn("first"),
+			flow.NewAction("rendition").
+				WithCommand(testEchoCommandToRun).
+				WithForEach(flow.NewActionForEachItems("360p", "720p")),
+			flow.NewAction("encode").
+				WithCommand(testEchoCommandToRun).
+				WithForEach(flow.NewActionForEachMatrix(map[string][]any{"codec": {"h264"}, "size": {360, 720}})),
+		},
+	}, nil, nil, nil, nil, flow.WithObservers(
+		flow.NewMetricsObserver(manager, "test", nil),
+		flow.NewLoggerObserver(logger.NewLoggableImplWithService("test")),
+	))
+
+	for range 2 {
+		_, err := flowEngine.Run(context.Background(), nil)
+		require.NoError(t, err)
+	}
+
+	families, err := manager.Gather()
+	require.NoError(t, err)
+
+	counts := make(map[string]uint64)
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+
+			for _, label := range metric.GetLabel() {
+				key += "," + label.GetName() + "=" + label.GetValue()
+			}
+
+			counts[key] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	require.Equal(t, map[string]uint64{
+		"test_flow_action_duration_seconds,action=first,result=succeeded":     2,
+		"test_flow_action_duration_seconds,action=second,result=succeeded":    2,
+		"test_flow_action_duration_seconds,action=rendition,result=succeeded": 4,
+		"test_flow_action_duration_seconds,action=encode,result=succeeded":    4,
+		"test_flow_run_duration_seconds,result=succeeded":                     2,
+	}, counts)
+}
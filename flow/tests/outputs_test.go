@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowRunOutputs(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("probe").
+				WithCommand(testEchoCommandToRun, `{"duration": 12, "codec": "h264"}`).
+				WithStdoutJson().
+				WithOutput("codec", "{{ .actions.probe.json.codec }}"),
+			flow.NewAction("transcode").
+				WithCommand(testEchoCommandToRun).
+				WithArgsTemplate(`["{{ .actions.probe.outputs.codec }}", "{{ .actions.probe.exit_code }}"]`).
+				WithWhen("actions.probe.json.duration > 10").
+				WithDependsOn("probe"),
+			flow.NewAction("thumbnail").
+				WithCommand(testEchoCommandToRun, "thumbnail").
+				WithWhen("actions.probe.json.duration > 60").
+				WithDependsOn("probe"),
+			flow.NewAction("report").
+				WithCommand(testEchoCommandToRun).
+				WithArgsTemplate(`["{{ .actions.transcode.exit_code }}", "{{ .actions.thumbnail.skipped }}"]`).
+				WithDependsOn("transcode", "thumbnail"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), flow_goja.NewGoja(), nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv("", nil))
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{"codec": "h264"}, result.ActionsResponses["probe"].Outputs)
+	require.Equal(t, "h264 0\n", result.ActionsResponses["transcode"].Stdout)
+	require.True(t, result.ActionsResponses["thumbnail"].Skipped)
+	require.Equal(t, "0 true\n", result.ActionsResponses["report"].Stdout)
+}
+
+func TestFlowRunOutputs_FirstActionKeepsEnv(t *testing.T) {
+	t.Parallel()
+
+	env := flow.NewEnv("", map[string]any{"name": "world"})
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("hello").WithCommand(testEchoCommandToRun, "hello {{ .name }}"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), env)
+	require.NoError(t, err)
+	require.Equal(t, "hello world\n", result.ActionsResponses["hello"].Stdout)
+
+	_, ok := env.Context[flow.ActionsContextKey]
+	require.False(t, ok)
+}
+
+func TestFlowRunOutputs_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("probe").
+				WithCommand(testEchoCommandToRun, "not json").
+				WithStdoutJson(),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionStdoutNotJson)
+	require.True(t, result.ActionsResponses["probe"].Failed)
+}
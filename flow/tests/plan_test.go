@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_goja"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlanFlow(marker string) flow.Flow {
+	return flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("prepare").
+				WithCommand("touch", marker).
+				WithWorkDir("{{ .dir }}/work"),
+			flow.NewAction("transcode").
+				WithCommand("ffmpeg").
+				WithArgsTemplate(`["-i", "{{ .input }}", "-vf", "scale={{ .width }}:-2", "out file.mp4"]`).
+				WithEnvTemplate(`{"THREADS": "{{ .threads }}"}`).
+				WithWhen("width > 0").
+				WithDependsOn("prepare"),
+			flow.NewAction("thumbnail").
+				WithCommand("ffmpeg").
+				WithArgsScript(`["-i", input, "thumb.jpg"]`).
+				WithWhen("width > 1920").
+				WithDependsOn("prepare"),
+			flow.NewAction("rendition").
+				WithCommand("ffmpeg", "-s", "{{ .item }}").
+				WithForEach(flow.NewActionForEachItems("360p", "720p")).
+				WithDependsOn("transcode", "thumbnail"),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), flow_goja.NewGoja(), nil)
+}
+
+func TestFlowPlan(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "marker")
+
+	plan, err := newPlanFlow(marker).Plan(context.Background(), flow.NewEnv("", map[string]any{
+		"dir":     dir,
+		"input":   "in.mp4",
+		"width":   1280,
+		"threads": "4",
+	}, flow.WithEnv("HOME", "/home/flow")))
+	require.NoError(t, err)
+
+	require.NoFileExists(t, marker)
+	require.NoDirExists(t, filepath.Join(dir, "work"))
+
+	require.Len(t, plan.Actions, 4)
+
+	prepare := plan.Actions[0]
+	require.Equal(t, "prepare", prepare.Name)
+	require.Equal(t, filepath.Join(dir, "work"), prepare.WorkDir)
+	require.Empty(t, prepare.DependsOn)
+
+	transcode := plan.Actions[1]
+	require.Equal(t, flow.ActionKindCommand, transcode.Kind)
+	require.False(t, transcode.Skipped)
+	require.Equal(t, []string{"prepare"}, transcode.DependsOn)
+	require.Equal(t, "ffmpeg -i in.mp4 -vf scale=1280:-2 'out file.mp4'", transcode.CommandLine)
+	require.Equal(t, map[string]string{"HOME": "/home/flow", "THREADS": "4"}, transcode.Env)
+
+	thumbnail := plan.Actions[2]
+	require.True(t, thumbnail.Skipped)
+	require.Empty(t, thumbnail.CommandLine)
+
+	rendition := plan.Actions[3]
+	require.Equal(t, []string{"transcode", "thumbnail"}, rendition.DependsOn)
+	require.Len(t, rendition.Instances, 2)
+	require.Equal(t, "rendition[1]", rendition.Instances[1].Name)
+	require.Equal(t, "ffmpeg -s 720p", rendition.Instances[1].CommandLine)
+}
+
+func TestFlowPlan_Error(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("report").
+				WithCommand(testEchoCommandToRun).
+				WithWhen("actions.probe.exit_code == 0"),
+		},
+	}, nil, nil, flow_goja.NewGoja(), nil)
+
+	plan, err := flowEngine.Plan(context.Background(), nil)
+	require.NoError(t, err)
+	require.Contains(t, plan.Actions[0].Error, "when failed")
+
+	_, err = flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("a").WithCommand(testEchoCommandToRun).WithDependsOn("b"),
+			flow.NewAction("b").WithCommand(testEchoCommandToRun).WithDependsOn("a"),
+		},
+	}, nil, nil, nil, nil).Plan(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionDependencyCycle)
+}
+
+func TestFlowPlan_Export(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	plan, err := newPlanFlow(filepath.Join(dir, "marker")).Plan(context.Background(), flow.NewEnv("", map[string]any{
+		"dir":   dir,
+		"input": "in.mp4",
+		"width": 1280,
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, `flowchart TD
+    a0["prepare"]
+    a1["transcode"]
+    a2["thumbnail (skipped)"]
+    a3["rendition x2"]
+    a0 --> a1
+    a0 --> a2
+    a1 --> a3
+    a2 --> a3
+    classDef skipped stroke-dasharray: 5 5
+    class a2 skipped
+`, plan.Mermaid())
+
+	require.Equal(t, `digraph flow {
+    "prepare" [label="prepare"];
+    "transcode" [label="transcode"];
+    "thumbnail" [label="thumbnail (skipped)", style=dashed];
+    "rendition" [label="rendition x2"];
+    "prepare" -> "transcode";
+    "prepare" -> "thumbnail";
+    "transcode" -> "rendition";
+    "thumbnail" -> "rendition";
+}
+`, plan.Dot())
+
+	_, err = os.Stat(filepath.Join(dir, "marker"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
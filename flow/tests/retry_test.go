@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/stretchr/testify/require"
+)
+
+const testShellCommandToRun = "sh"
+
+func TestFlowRunAction_Timeout(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("hung").
+				WithCommand(testSleepCommandToRun, "5").
+				WithTimeout(100 * time.Millisecond),
+		},
+	}, nil, nil, nil, nil)
+
+	startedAt := time.Now()
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrActionTimeout)
+	require.Less(t, time.Since(startedAt), 2*time.Second)
+
+	response, ok := result.ActionsResponses["hung"]
+	require.True(t, ok)
+	require.True(t, response.Failed)
+	require.Len(t, response.Attempts, 1)
+	require.True(t, response.Attempts[0].TimedOut)
+}
+
+func TestFlowRunAction_RetrySucceeds(t *testing.T) {
+	t.Parallel()
+
+	// fails twice, then succeeds, counting attempts in a file of the work dir
+	script := `n=$(cat attempts 2>/dev/null || echo 0); n=$((n+1)); echo $n > attempts; [ $n -ge 3 ]`
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("flaky").
+				WithCommand(testShellCommandToRun, "-c", script).
+				WithRetry(flow.NewActionRetry(3).WithBackoff(10*time.Millisecond, 2, time.Second)),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv(t.TempDir(), nil))
+	require.NoError(t, err)
+
+	response := result.ActionsResponses["flaky"]
+	require.False(t, response.Failed)
+	require.Len(t, response.Attempts, 3)
+
+	for index, attempt := range response.Attempts {
+		require.Equal(t, index+1, attempt.Number)
+	}
+
+	require.Equal(t, 1, response.Attempts[0].ErrorCode)
+	require.NotEmpty(t, response.Attempts[0].Error)
+	require.Equal(t, 1, response.Attempts[1].ErrorCode)
+	require.Equal(t, 0, response.Attempts[2].ErrorCode)
+	require.Empty(t, response.Attempts[2].Error)
+}
+
+func TestFlowRunAction_RetryExhausted(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("broken").
+				WithCommand(testFailedCommandToRun).
+				WithRetry(flow.NewActionRetry(2)),
+			flow.NewAction("next").
+				WithCommand(testCommandToRun),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.ErrorIs(t, err, flow.ErrCommandFailed)
+
+	response := result.ActionsResponses["broken"]
+	require.True(t, response.Failed)
+	require.Len(t, response.Attempts, 2)
+
+	_, ok := result.ActionsResponses["next"]
+	require.False(t, ok)
+}
+
+func TestFlowRunAction_ContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("optional").
+				WithCommand(testFailedCommandToRun).
+				WithContinueOnError(true),
+			flow.NewAction("next").
+				WithCommand(testCommandToRun).
+				WithDependsOn("optional"),
+		},
+	}, nil, nil, nil, nil)
+
+	result, err := flowEngine.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	optional := result.ActionsResponses["optional"]
+	require.True(t, optional.Failed)
+	require.Contains(t, optional.Error, flow.ErrCommandFailed.Error())
+	require.Equal(t, 1, optional.ErrorCode)
+
+	next := result.ActionsResponses["next"]
+	require.False(t, next.Failed)
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pixality-inc/golang-core/flow"
+	"github.com/pixality-inc/golang-core/flow_gotemplate"
+	"github.com/pixality-inc/golang-core/storage"
+	"github.com/pixality-inc/golang-core/storage/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(dir string) storage.Storage {
+	return storage.NewStorage(providers.NewOsProvider(dir), providers.NewNoUrlProvider(""))
+}
+
+func TestFlowRunStorage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	workDir := filepath.Join(dir, "work")
+
+	require.NoError(t, os.MkdirAll(workDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "video.mp4"), []byte("video"), 0o600))
+
+	storages := map[string]storage.Storage{
+		"uploads": newTestStorage(filepath.Join(dir, "uploads")),
+		"archive": newTestStorage(filepath.Join(dir, "archive")),
+	}
+
+	flowEngine := flow.New(&flow.Config{
+		Actions: []flow.Action{
+			flow.NewAction("upload").WithStorage(flow.NewActionStorageUpload(
+				"{{ .name }}.mp4",
+				flow.NewStorageLocation("uploads", "videos/{{ .name }}.mp4"),
+			)),
+			flow.NewAction("copy").WithStorage(flow.NewActionStorageCopy(
+				flow.NewStorageLocation("uploads", "videos/{{ .name }}.mp4"),
+				flow.NewStorageLocation("archive", "copy.mp4"),
+			)),
+			flow.NewAction("move").WithStorage(flow.NewActionStorageMove(
+				flow.NewStorageLocation("archive", "copy.mp4"),
+				flow.NewStorageLocation("archive", "2026/{{ .name }}.mp4"),
+			)),
+			flow.NewAction("download").WithStorage(flow.NewActionStorageDownload(
+				flow.NewStorageLocation("archive", "2026/{{ .name }}.mp4"),
+				"downloads/{{ .name }}.mp4",
+			)),
+			flow.NewAction("delete").WithStorage(flow.NewActionStorageDelete(
+				flow.NewStorageLocation("uploads", "videos/{{ .name }}.mp4"),
+			)),
+		},
+	}, nil, flow_gotemplate.NewGoTemplate(), nil, nil, flow.WithStorages(storages))
+
+	require.NoError(t, flowEngine.Validate(context.Background()))
+
+	result, err := flowEngine.Run(context.Background(), flow.NewEnv(workDir, map[string]any{"name": "video"}))
+	require.NoError(t, err)
+
+	require.Equal(t, filepath.Join(workDir, "downloads", "video.mp4"), result.ActionsResponses["download"].Result)
+
+	downloaded, err := os.ReadFile(filepath.Join(workDir, "downloads", "video.mp4"))
+	require.NoError(t, err)
+	require.Equal(t, "video", string(downloaded))
+
+	require.FileExists(t, filepath.Join(dir, "archive", "2026", "video.mp4"))
+	require.NoFileExists(t, filepath.Join(dir, "archive", "copy.mp4"))
+	require.NoFileExists(t, filepath.Join(dir, "uploads", "videos", "video.mp4"))
+}
+
+func TestFlowValidateStorage(t *testing.T) {
+	t.Parallel()
+
+	uploads := flow.NewStorageLocation("uploads", "file")
+	destination := flow.NewStorageLocation("uploads", "copy")
+
+	tests := []struct {
+		name      string
+		operation flow.ActionStorage
+		wantErr   error
+	}{
+		{
+			name:      "invalid_operation",
+			operation: flow.ActionStorage{Operation: "rename", Source: uploads},
+			wantErr:   flow.ErrActionStorageInvalidOperation,
+		},
+		{
+			name:      "no_path",
+			operation: flow.NewActionStorageDelete(flow.NewStorageLocation("uploads", "")),
+			wantErr:   flow.ErrActionStorageNoPath,
+		},
+		{
+			name:      "unknown_storage",
+			operation: flow.NewActionStorageDelete(flow.NewStorageLocation("missing", "file")),
+			wantErr:   flow.ErrActionStorageUnknownStorage,
+		},
+		{
+			name:      "copy_from_local",
+			operation: flow.NewActionStorageCopy(flow.NewLocalLocation("file"), destination),
+			wantErr:   flow.ErrActionStorageNoStorage,
+		},
+		{
+			name:      "upload_from_storage",
+			operation: flow.ActionStorage{Operation: flow.StorageOperationUpload, Source: uploads, Destination: &destination},
+			wantErr:   flow.ErrActionStorageNotLocal,
+		},
+		{
+			name:      "no_destination",
+			operation: flow.ActionStorage{Operation: flow.StorageOperationCopy, Source: uploads},
+			wantErr:   flow.ErrActionStorageNoDestination,
+		},
+		{
+			name:      "delete_with_destination",
+			operation: flow.ActionStorage{Operation: flow.StorageOperationDelete, Source: uploads, Destination: &destination},
+			wantErr:   flow.ErrActionStorageUnexpectedDestination,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flowEngine := flow.New(&flow.Config{
+				Actions: []flow.Action{
+					flow.NewAction("storage").WithStorage(tt.operation),
+				},
+			}, nil, nil, nil, nil, flow.WithStorages(map[string]storage.Storage{
+				"uploads": newTestStorage(t.TempDir()),
+			}))
+
+			require.ErrorIs(t, flowEngine.Validate(context.Background()), tt.wantErr)
+		})
+	}
+}
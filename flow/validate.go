@@ -0,0 +1,65 @@
+package flow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/util"
+)
+
+// validateActionOptions checks the rules between fields of an action that are
+// otherwise only enforced once the action runs. Actions without any kind are
+// accepted here and fail when they run.
+func (f *Impl) validateActionOptions(action Action) error {
+	kinds := make([]string, 0, 1)
+
+	for kind, isSet := range map[string]bool{
+		"trigger":     action.Trigger != nil,
+		"result":      action.Result != nil,
+		"command":     action.Command != "",
+		"script":      action.Script != "",
+		"script_file": action.ScriptFile != "",
+		"http":        action.Http != nil,
+		"storage":     action.Storage != nil,
+		"include":     action.Include != nil,
+	} {
+		if isSet {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	if len(kinds) > 1 {
+		slices.Sort(kinds)
+
+		return fmt.Errorf("%w: %s are mutually exclusive", ErrActionTooManyOptions, strings.Join(kinds, ", "))
+	}
+
+	if util.SliceSum([]bool{len(action.Args) > 0, action.ArgsTemplate != "", action.ArgsScript != ""}, 0, boolInc) > 1 {
+		return fmt.Errorf("%w: args, args_template and args_script are mutually exclusive", ErrActionArgsTooManyOptions)
+	}
+
+	if util.SliceSum([]bool{len(action.Env) > 0, action.EnvTemplate != "", action.EnvScript != ""}, 0, boolInc) > 1 {
+		return fmt.Errorf("%w: env, env_template and env_script are mutually exclusive", ErrActionEnvTooManyOptions)
+	}
+
+	if action.Trigger != nil {
+		if action.Trigger.Data != nil && action.Trigger.DataScript != "" {
+			return fmt.Errorf("%w: data and data_script are mutually exclusive", ErrActionTriggerTooManyOptions)
+		}
+
+		if _, ok := f.triggers[action.Trigger.Name]; !ok {
+			return fmt.Errorf("%w: %s", ErrTriggerNotFound, action.Trigger.Name)
+		}
+	}
+
+	if action.Timeout < 0 {
+		return fmt.Errorf("%w: %s", ErrActionInvalidTimeout, action.Timeout)
+	}
+
+	if action.Retry != nil && action.Retry.AttemptsValue < 0 {
+		return fmt.Errorf("%w: %d", ErrActionInvalidRetryAttempts, action.Retry.AttemptsValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+package flow_cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	celAst "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/pixality-inc/golang-core/flow"
+)
+
+var (
+	ErrWrongValue    = errors.New("wrong value")
+	ErrCompileFailed = errors.New("expression compile failed")
+)
+
+// Cel evaluates Common Expression Language expressions, which are side effect free
+// and always terminate. Expressions are compiled once and cached by source, the
+// context values of the env are their variables.
+type Cel struct {
+	envOptions []cel.EnvOption
+	variables  map[string]*cel.Type
+	costLimit  uint64
+	env        *cel.Env
+	envErr     error
+	envOnce    sync.Once
+	programs   map[string]cel.Program
+	mutex      sync.RWMutex
+}
+
+func NewCel(options ...Option) *Cel {
+	driver := &Cel{
+		envOptions: nil,
+		variables:  make(map[string]*cel.Type),
+		costLimit:  DefaultCostLimit,
+		env:        nil,
+		envErr:     nil,
+		envOnce:    sync.Once{},
+		programs:   make(map[string]cel.Program),
+		mutex:      sync.RWMutex{},
+	}
+
+	for _, opt := range options {
+		opt(driver)
+	}
+
+	return driver
+}
+
+// Compile type-checks the expression and caches its program, it is called by
+// flow.Validate for every expression of the flow.
+func (d *Cel) Compile(name string, script string) error {
+	_, err := d.program(name, script)
+
+	return err
+}
+
+func (d *Cel) Execute(ctx context.Context, env *flow.Env, name string, script string) (any, error) {
+	program, err := d.program(name, script)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := env.Context
+	if variables == nil {
+		variables = make(map[string]any)
+	}
+
+	result, _, err := program.ContextEval(ctx, variables)
+	if err != nil {
+		return nil, fmt.Errorf("expression %s failed: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (d *Cel) program(name string, script string) (cel.Program, error) {
+	d.mutex.RLock()
+	program, ok := d.programs[script]
+	d.mutex.RUnlock()
+
+	if ok {
+		return program, nil
+	}
+
+	program, err := d.compile(script)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrCompileFailed, name, err)
+	}
+
+	d.mutex.Lock()
+	d.programs[script] = program
+	d.mutex.Unlock()
+
+	return program, nil
+}
+
+func (d *Cel) compile(script string) (cel.Program, error) {
+	baseEnv, err := d.baseEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, issues := baseEnv.Parse(script)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	variables := make([]cel.EnvOption, 0)
+
+	for _, identifier := range freeIdentifiers(parsed) {
+		if _, ok := d.variables[identifier]; !ok {
+			variables = append(variables, cel.Variable(identifier, cel.DynType))
+		}
+	}
+
+	env, err := baseEnv.Extend(variables...)
+	if err != nil {
+		return nil, err
+	}
+
+	checked, issues := env.Check(parsed)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	programOptions := []cel.ProgramOption{cel.InterruptCheckFrequency(interruptCheckFrequency)}
+
+	if d.costLimit > 0 {
+		programOptions = append(programOptions, cel.CostLimit(d.costLimit))
+	}
+
+	return env.Program(checked, programOptions...)
+}
+
+func (d *Cel) baseEnv() (*cel.Env, error) {
+	d.envOnce.Do(func() {
+		options := make([]cel.EnvOption, 0, len(d.envOptions)+len(d.variables))
+
+		options = append(options, d.envOptions...)
+
+		for name, celType := range d.variables {
+			options = append(options, cel.Variable(name, celType))
+		}
+
+		d.env, d.envErr = cel.NewEnv(options...)
+	})
+
+	return d.env, d.envErr
+}
+
+// freeIdentifiers lists the identifiers of the expression except comprehension variables.
+func freeIdentifiers(parsed *cel.Ast) []string {
+	bound := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	identifiers := make([]string, 0)
+
+	celAst.PostOrderVisit(parsed.NativeRep().Expr(), celAst.NewExprVisitor(func(expr celAst.Expr) {
+		switch expr.Kind() {
+		case celAst.ComprehensionKind:
+			comprehension := expr.AsComprehension()
+
+			bound[comprehension.IterVar()] = struct{}{}
+			bound[comprehension.AccuVar()] = struct{}{}
+
+			if comprehension.HasIterVar2() {
+				bound[comprehension.IterVar2()] = struct{}{}
+			}
+
+		case celAst.IdentKind:
+			if _, ok := seen[expr.AsIdent()]; !ok {
+				seen[expr.AsIdent()] = struct{}{}
+				identifiers = append(identifiers, expr.AsIdent())
+			}
+
+		default:
+		}
+	}))
+
+	free := make([]string, 0, len(identifiers))
+
+	for _, identifier := range identifiers {
+		if _, ok := bound[identifier]; !ok {
+			free = append(free, identifier)
+		}
+	}
+
+	return free
+}
+
+func (d *Cel) ValueToString(value any) (string, error) {
+	val, err := asValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	if str, ok := val.(types.String); ok {
+		return string(str), nil
+	}
+
+	converted := val.ConvertToType(types.StringType)
+	if types.IsError(converted) {
+		return "", fmt.Errorf("%w: %s to string: %v", ErrWrongValue, val.Type().TypeName(), converted)
+	}
+
+	return string(converted.(types.String)), nil //nolint:forcetypeassert
+}
+
+func (d *Cel) ValueToBool(value any) (bool, error) {
+	val, err := asValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	boolean, ok := val.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s is not a bool", ErrWrongValue, val.Type().TypeName())
+	}
+
+	return bool(boolean), nil
+}
+
+func (d *Cel) ValueToStringSlice(value any) ([]string, error) {
+	val, err := asValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := val.(traits.Lister)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a list", ErrWrongValue, val.Type().TypeName())
+	}
+
+	results := make([]string, 0)
+
+	for iterator := list.Iterator(); iterator.HasNext() == types.True; {
+		element, err := d.ValueToString(iterator.Next())
+		if err != nil {
+			return nil, fmt.Errorf("list index %d: %w", len(results), err)
+		}
+
+		results = append(results, element)
+	}
+
+	return results, nil
+}
+
+func (d *Cel) ValueToMapStringString(value any) (map[string]string, error) {
+	val, err := asValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, ok := val.(traits.Mapper)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a map", ErrWrongValue, val.Type().TypeName())
+	}
+
+	results := make(map[string]string)
+
+	for iterator := mapper.Iterator(); iterator.HasNext() == types.True; {
+		key := iterator.Next()
+
+		keyString, err := d.ValueToString(key)
+		if err != nil {
+			return nil, fmt.Errorf("map key: %w", err)
+		}
+
+		valueString, err := d.ValueToString(mapper.Get(key))
+		if err != nil {
+			return nil, fmt.Errorf("map key %s: %w", keyString, err)
+		}
+
+		results[keyString] = valueString
+	}
+
+	return results, nil
+}
+
+func (d *Cel) ValueToMapStringAny(value any) (map[string]any, error) {
+	val, err := asValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	native := toNative(val)
+
+	mapValue, ok := native.(map[string]any)
+	if ok {
+		return mapValue, nil
+	}
+
+	return map[string]any{"__value": native}, nil
+}
+
+func (d *Cel) AnyToValue(value any) (any, error) {
+	val := types.DefaultTypeAdapter.NativeToValue(value)
+	if types.IsError(val) {
+		return nil, fmt.Errorf("%w: %T: %v", ErrWrongValue, value, val)
+	}
+
+	return val, nil
+}
+
+func (d *Cel) NewError(err error) any {
+	return types.WrapErr(err)
+}
+
+// Throw panics with err, expressions have no exceptions to raise.
+func (d *Cel) Throw(err error) {
+	panic(err)
+}
+
+func asValue(value any) (ref.Val, error) {
+	val, ok := value.(ref.Val)
+	if !ok {
+		return nil, fmt.Errorf("%w: value %T is not a ref.Val", ErrWrongValue, value)
+	}
+
+	if types.IsError(val) {
+		return nil, fmt.Errorf("%w: %v", ErrWrongValue, val)
+	}
+
+	return val, nil
+}
+
+// toNative converts lists and maps recursively into []any and map[string]any.
+func toNative(val ref.Val) any {
+	switch value := val.(type) {
+	case traits.Mapper:
+		result := make(map[string]any)
+
+		for iterator := value.Iterator(); iterator.HasNext() == types.True; {
+			key := iterator.Next()
+
+			result[fmt.Sprint(key.Value())] = toNative(value.Get(key))
+		}
+
+		return result
+
+	case traits.Lister:
+		result := make([]any, 0)
+
+		for iterator := value.Iterator(); iterator.HasNext() == types.True; {
+			result = append(result, toNative(iterator.Next()))
+		}
+
+		return result
+
+	default:
+		if val == types.NullValue {
+			return nil
+		}
+
+		return val.Value()
+	}
+}
@@ -0,0 +1,34 @@
+package flow_cel
+
+import "github.com/google/cel-go/cel"
+
+// DefaultCostLimit bounds the work of a single evaluation, so expressions over
+// large lists fail fast instead of blocking the flow.
+const DefaultCostLimit = 1_000_000
+
+// interruptCheckFrequency is how many comprehension iterations run between checks of the context.
+const interruptCheckFrequency = 100
+
+type Option = func(driver *Cel)
+
+// WithVariable declares the type of a context value, so expressions using it are
+// type-checked. Undeclared identifiers are checked as dyn.
+func WithVariable(name string, celType *cel.Type) Option {
+	return func(driver *Cel) {
+		driver.variables[name] = celType
+	}
+}
+
+// WithCostLimit sets the maximum cost of an evaluation, zero means no limit.
+func WithCostLimit(costLimit uint64) Option {
+	return func(driver *Cel) {
+		driver.costLimit = costLimit
+	}
+}
+
+// WithEnvOptions adds CEL environment options, e.g. ext.Strings() for string functions.
+func WithEnvOptions(options ...cel.EnvOption) Option {
+	return func(driver *Cel) {
+		driver.envOptions = append(driver.envOptions, options...)
+	}
+}
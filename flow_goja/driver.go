@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/dop251/goja"
 	"github.com/pixality-inc/golang-core/flow"
@@ -15,33 +16,105 @@ var (
 	ErrWrongValue           = errors.New("wrong value")
 	ErrJsValueToStringArray = errors.New("js value to string array")
 	ErrJsValueToAnyArray    = errors.New("js value to any array")
+	ErrScriptTimeout        = errors.New("script timed out")
+	ErrScriptStackOverflow  = errors.New("script call stack size exceeded")
+	ErrModuleNotAllowed     = errors.New("module is not allowed")
 )
 
+// Goja runs every script in a fresh runtime, so concurrent evaluations never share
+// state. Scripts are interrupted once the context is done or the timeout elapsed.
 type Goja struct {
-	js *goja.Runtime
+	timeout          time.Duration
+	maxCallStackSize int
+	modules          *modules
 }
 
-func NewGoja() *Goja {
-	return &Goja{
-		js: goja.New(),
+func NewGoja(options ...Option) *Goja {
+	driver := &Goja{
+		timeout:          0,
+		maxCallStackSize: DefaultMaxCallStackSize,
+		modules:          nil,
 	}
+
+	for _, opt := range options {
+		opt(driver)
+	}
+
+	return driver
 }
 
 func (d *Goja) Execute(ctx context.Context, env *flow.Env, name string, script string) (any, error) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeoutCause(ctx, d.timeout, fmt.Errorf("%w after %s", ErrScriptTimeout, d.timeout))
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("script %s failed: %w", name, context.Cause(ctx))
+	}
+
+	vm, err := d.newRuntime()
+	if err != nil {
+		return nil, fmt.Errorf("script %s failed: %w", name, err)
+	}
+
 	for key, value := range env.Context {
-		if err := d.js.Set(key, value); err != nil {
+		if err := vm.Set(key, value); err != nil {
 			return nil, fmt.Errorf("setting env context key %s: %w", key, err)
 		}
 	}
 
-	result, err := d.js.RunString(script)
+	stop := context.AfterFunc(ctx, func() {
+		vm.Interrupt(context.Cause(ctx))
+	})
+	defer stop()
+
+	result, err := runScript(vm, name, script)
 	if err != nil {
+		var stackOverflow *goja.StackOverflowError
+
+		if errors.As(err, &stackOverflow) {
+			return nil, fmt.Errorf("script %s failed: %w: %w", name, ErrScriptStackOverflow, err)
+		}
+
 		return nil, fmt.Errorf("script %s failed: %w", name, err)
 	}
 
 	return result, nil
 }
 
+// runScript runs the script, a Throw of a Go function called by it fails the evaluation.
+func runScript(vm *goja.Runtime, name string, script string) (result goja.Value, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			thrown, ok := recovered.(*thrownError)
+			if !ok {
+				panic(recovered)
+			}
+
+			result, err = nil, thrown.err
+		}
+	}()
+
+	return vm.RunScript(name, script)
+}
+
+func (d *Goja) newRuntime() (*goja.Runtime, error) {
+	vm := goja.New()
+
+	vm.SetMaxCallStackSize(d.maxCallStackSize)
+
+	if d.modules != nil {
+		if err := vm.Set("require", d.modules.newLoader(vm).requireFrom(".")); err != nil {
+			return nil, fmt.Errorf("setting require: %w", err)
+		}
+	}
+
+	return vm, nil
+}
+
 func (d *Goja) ValueToString(value any) (string, error) {
 	val, ok := value.(goja.Value)
 	if !ok {
@@ -138,14 +211,24 @@ func (d *Goja) ValueToMapStringAny(value any) (map[string]any, error) {
 	return map[string]any{"__value": exportedValue}, nil
 }
 
+// AnyToValue returns value as is, the runtime of the evaluation calling the Go function
+// converts it, values of another runtime can't be used by the script.
 func (d *Goja) AnyToValue(value any) (any, error) {
-	return d.js.ToValue(value), nil
+	return value, nil
 }
 
+// NewError returns err as is, returned as the error result of a Go function it is thrown
+// by the runtime of the evaluation as a GoError the script can catch.
 func (d *Goja) NewError(err error) any {
-	return d.js.NewGoError(err)
+	return err
 }
 
+// Throw aborts the evaluation calling the Go function, Execute fails with err. Scripts
+// can't catch it, Go functions return an error to throw a catchable exception.
 func (d *Goja) Throw(err error) {
-	panic(d.NewError(err))
+	panic(&thrownError{err: err})
+}
+
+type thrownError struct {
+	err error
 }
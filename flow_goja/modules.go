@@ -0,0 +1,172 @@
+package flow_goja
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+const moduleExtension = ".js"
+
+// modules is the whitelist of files scripts may require. Modules are compiled once
+// and evaluated once per runtime, so every evaluation gets its own module state.
+type modules struct {
+	fsys     fs.FS
+	allowed  map[string]struct{}
+	programs map[string]*goja.Program
+	mutex    sync.Mutex
+}
+
+func newModules(fsys fs.FS, names []string) *modules {
+	allowed := make(map[string]struct{}, len(names))
+
+	for _, name := range names {
+		if resolved, err := resolveModule(".", name); err == nil {
+			allowed[resolved] = struct{}{}
+		}
+	}
+
+	return &modules{
+		fsys:     fsys,
+		allowed:  allowed,
+		programs: make(map[string]*goja.Program),
+		mutex:    sync.Mutex{},
+	}
+}
+
+// resolveModule returns the path of the module name required from dir.
+func resolveModule(dir string, name string) (string, error) {
+	if path.IsAbs(name) {
+		return "", fmt.Errorf("%w: '%s' is absolute", ErrModuleNotAllowed, name)
+	}
+
+	resolved := name
+
+	if strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") {
+		resolved = path.Join(dir, name)
+	}
+
+	resolved = path.Clean(resolved)
+
+	if path.Ext(resolved) == "" {
+		resolved += moduleExtension
+	}
+
+	if !fs.ValidPath(resolved) {
+		return "", fmt.Errorf("%w: '%s' is outside of the modules dir", ErrModuleNotAllowed, name)
+	}
+
+	return resolved, nil
+}
+
+func (m *modules) program(name string) (*goja.Program, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if program, ok := m.programs[name]; ok {
+		return program, nil
+	}
+
+	source, err := fs.ReadFile(m.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module %s: %w", name, err)
+	}
+
+	program, err := goja.Compile(name, "(function(exports, require, module) {"+string(source)+"\n})", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile module %s: %w", name, err)
+	}
+
+	m.programs[name] = program
+
+	return program, nil
+}
+
+// loader evaluates modules within a single runtime.
+type loader struct {
+	modules *modules
+	vm      *goja.Runtime
+	cache   map[string]*goja.Object
+}
+
+func (m *modules) newLoader(vm *goja.Runtime) *loader {
+	return &loader{
+		modules: m,
+		vm:      vm,
+		cache:   make(map[string]*goja.Object),
+	}
+}
+
+// requireFrom returns the require function for modules located in dir.
+func (l *loader) requireFrom(dir string) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		exports, err := l.require(dir, call.Argument(0).String())
+		if err != nil {
+			// script exceptions and interrupts of the module are rethrown as they are
+			var exception *goja.Exception
+			var interrupted *goja.InterruptedError
+			var stackOverflow *goja.StackOverflowError
+
+			if errors.As(err, &exception) || errors.As(err, &interrupted) || errors.As(err, &stackOverflow) {
+				panic(err)
+			}
+
+			panic(l.vm.NewGoError(err))
+		}
+
+		return exports
+	}
+}
+
+func (l *loader) require(dir string, name string) (goja.Value, error) {
+	resolved, err := resolveModule(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := l.modules.allowed[resolved]; !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrModuleNotAllowed, resolved)
+	}
+
+	// cached before evaluation so cyclic requires see the partially filled exports
+	if module, ok := l.cache[resolved]; ok {
+		return module.Get("exports"), nil
+	}
+
+	program, err := l.modules.program(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapper, err := l.vm.RunProgram(program)
+	if err != nil {
+		return nil, err
+	}
+
+	call, ok := goja.AssertFunction(wrapper)
+	if !ok {
+		return nil, fmt.Errorf("%w: module %s", ErrWrongValue, resolved)
+	}
+
+	module := l.vm.NewObject()
+	exports := l.vm.NewObject()
+
+	if err = module.Set("exports", exports); err != nil {
+		return nil, err
+	}
+
+	l.cache[resolved] = module
+
+	if _, err = call(goja.Undefined(), exports, l.vm.ToValue(l.requireFrom(path.Dir(resolved))), module); err != nil {
+		delete(l.cache, resolved)
+
+		return nil, err
+	}
+
+	return module.Get("exports"), nil
+}
@@ -0,0 +1,37 @@
+package flow_goja
+
+import (
+	"io/fs"
+	"time"
+)
+
+// DefaultMaxCallStackSize bounds the recursion depth of scripts so a runaway
+// recursion fails fast instead of exhausting memory.
+const DefaultMaxCallStackSize = 1024
+
+type Option = func(driver *Goja)
+
+// WithTimeout interrupts scripts running longer than timeout, zero means scripts
+// are only bounded by the context passed to Execute.
+func WithTimeout(timeout time.Duration) Option {
+	return func(driver *Goja) {
+		driver.timeout = timeout
+	}
+}
+
+// WithMaxCallStackSize sets the maximum call stack depth of scripts.
+func WithMaxCallStackSize(size int) Option {
+	return func(driver *Goja) {
+		driver.maxCallStackSize = size
+	}
+}
+
+// WithModules lets scripts require the listed files of fsys, e.g. os.DirFS of the flow
+// directory. Modules are CommonJS files named by their path relative to the root of
+// fsys, the .js extension may be omitted in require calls. Scripts have no require
+// and no filesystem or network access without this option.
+func WithModules(fsys fs.FS, modules ...string) Option {
+	return func(driver *Goja) {
+		driver.modules = newModules(fsys, modules)
+	}
+}
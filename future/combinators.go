@@ -0,0 +1,327 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/pixality-inc/golang-core/either"
+	"github.com/pixality-inc/golang-core/logger"
+	"github.com/pixality-inc/golang-core/promise"
+)
+
+var (
+	ErrTimeout   = errors.New("future timeout")
+	ErrNoFutures = errors.New("no futures")
+	ErrAllFailed = errors.New("all futures failed")
+)
+
+type indexedResult[T any] struct {
+	index int
+	value T
+	err   error
+}
+
+// All resolves with the values of all futures in order.
+// It fails fast with the first error and cancels the remaining futures.
+func All[T any](ctx context.Context, futures []Future[T], options ...Option) Future[[]T] {
+	return New(ctx, func(ctx context.Context) ([]T, error) {
+		values := make([]T, len(futures))
+		results := watch(ctx, futures)
+
+		for range futures {
+			result := results.next()
+			if result.err != nil {
+				cancelAll(futures)
+
+				return nil, result.err
+			}
+
+			values[result.index] = result.value
+		}
+
+		return values, nil
+	}, options...)
+}
+
+// AllSettled waits for all futures and resolves with their outcomes in order.
+// It only fails when ctx is done, in which case the remaining futures are canceled.
+func AllSettled[T any](ctx context.Context, futures []Future[T], options ...Option) Future[[]either.EitherError[T]] {
+	return New(ctx, func(ctx context.Context) ([]either.EitherError[T], error) {
+		outcomes := make([]either.EitherError[T], len(futures))
+		results := watch(ctx, futures)
+
+		for range futures {
+			result := results.next()
+
+			if ctx.Err() != nil {
+				cancelAll(futures)
+
+				return nil, ctx.Err()
+			}
+
+			if result.err != nil {
+				outcomes[result.index] = either.Error[T](result.err)
+			} else {
+				outcomes[result.index] = either.RightError[T](result.value)
+			}
+		}
+
+		return outcomes, nil
+	}, options...)
+}
+
+// Any resolves with the first successful value and cancels the remaining futures.
+// If every future fails it rejects with ErrAllFailed joined with all errors.
+func Any[T any](ctx context.Context, futures []Future[T], options ...Option) Future[T] {
+	return New(ctx, func(ctx context.Context) (T, error) {
+		var defaultValue T
+
+		if len(futures) == 0 {
+			return defaultValue, ErrNoFutures
+		}
+
+		errs := make([]error, len(futures))
+		results := watch(ctx, futures)
+
+		for range futures {
+			result := results.next()
+			if result.err == nil {
+				cancelAll(futures)
+
+				return result.value, nil
+			}
+
+			if ctx.Err() != nil {
+				cancelAll(futures)
+
+				return defaultValue, ctx.Err()
+			}
+
+			errs[result.index] = result.err
+		}
+
+		return defaultValue, errors.Join(append([]error{ErrAllFailed}, errs...)...)
+	}, options...)
+}
+
+// Race settles with the outcome of the first future to settle and cancels the rest.
+func Race[T any](ctx context.Context, futures []Future[T], options ...Option) Future[T] {
+	return New(ctx, func(ctx context.Context) (T, error) {
+		if len(futures) == 0 {
+			var defaultValue T
+
+			return defaultValue, ErrNoFutures
+		}
+
+		result := watch(ctx, futures).next()
+
+		cancelAll(futures)
+
+		return result.value, result.err
+	}, options...)
+}
+
+// Map transforms the value of a successful future.
+func Map[T any, R any](
+	ctx context.Context,
+	fut Future[T],
+	fn func(ctx context.Context, value T) (R, error),
+	options ...Option,
+) Future[R] {
+	return New(ctx, func(ctx context.Context) (R, error) {
+		value, err := getOrCancel(ctx, fut)
+		if err != nil {
+			var defaultValue R
+
+			return defaultValue, err
+		}
+
+		return fn(ctx, value)
+	}, options...)
+}
+
+// FlatMap chains a future-returning function onto a successful future.
+func FlatMap[T any, R any](
+	ctx context.Context,
+	fut Future[T],
+	fn func(ctx context.Context, value T) Future[R],
+	options ...Option,
+) Future[R] {
+	return New(ctx, func(ctx context.Context) (R, error) {
+		value, err := getOrCancel(ctx, fut)
+		if err != nil {
+			var defaultValue R
+
+			return defaultValue, err
+		}
+
+		return getOrCancel(ctx, fn(ctx, value))
+	}, options...)
+}
+
+// Then chains a function that receives both the value and the error of the future.
+func Then[T any, R any](
+	ctx context.Context,
+	fut Future[T],
+	fn func(ctx context.Context, value T, err error) (R, error),
+	options ...Option,
+) Future[R] {
+	return New(ctx, func(ctx context.Context) (R, error) {
+		value, err := getOrCancel(ctx, fut)
+		if err != nil && ctx.Err() != nil {
+			var defaultValue R
+
+			return defaultValue, err
+		}
+
+		return fn(ctx, value, err)
+	}, options...)
+}
+
+// WithTimeout rejects with ErrTimeout and cancels fut when it does not settle within timeout.
+func WithTimeout[T any](ctx context.Context, fut Future[T], timeout time.Duration, options ...Option) Future[T] {
+	return New(ctx, func(ctx context.Context) (T, error) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		value, err := fut.Get(timeoutCtx)
+		if err != nil && timeoutCtx.Err() != nil && !fut.IsResolved() {
+			fut.Cancel()
+
+			if ctx.Err() != nil {
+				return value, ctx.Err()
+			}
+
+			return value, errors.Join(ErrTimeout, context.DeadlineExceeded)
+		}
+
+		return value, err
+	}, options...)
+}
+
+// OnComplete runs callback on the pool executor once fut settles.
+func OnComplete[T any](
+	ctx context.Context,
+	fut Future[T],
+	callback func(ctx context.Context, value T, err error),
+	options ...Option,
+) {
+	opts := applyOptions(options...)
+
+	err := opts.poolExecutor.Execute(ctx, func(ctx context.Context) error {
+		value, err := fut.Get(ctx)
+
+		callback(ctx, value, err)
+
+		return nil
+	})
+	if err != nil {
+		logger.GetLogger(ctx).WithError(err).Error("failed to execute future callback with pool executor")
+	}
+}
+
+// OnSuccess runs callback on the pool executor if fut resolves successfully.
+func OnSuccess[T any](
+	ctx context.Context,
+	fut Future[T],
+	callback func(ctx context.Context, value T),
+	options ...Option,
+) {
+	OnComplete(ctx, fut, func(ctx context.Context, value T, err error) {
+		if err == nil {
+			callback(ctx, value)
+		}
+	}, options...)
+}
+
+// OnFailure runs callback on the pool executor if fut is rejected.
+func OnFailure[T any](
+	ctx context.Context,
+	fut Future[T],
+	callback func(ctx context.Context, err error),
+	options ...Option,
+) {
+	OnComplete(ctx, fut, func(ctx context.Context, _ T, err error) {
+		if err != nil {
+			callback(ctx, err)
+		}
+	}, options...)
+}
+
+// watcher waits for the futures on their channels with a single select in the caller's
+// goroutine, so combinators don't spawn a goroutine per future.
+type watcher[T any] struct {
+	ctx     context.Context //nolint:containedctx // the watcher lives for one combinator call
+	cases   []reflect.SelectCase
+	indexes []int
+}
+
+// watch reports the results of the futures as they settle, the first case waits for ctx.
+func watch[T any](ctx context.Context, futures []Future[T]) *watcher[T] {
+	cases := make([]reflect.SelectCase, 0, len(futures)+1)
+	indexes := make([]int, 0, len(futures)+1)
+
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	indexes = append(indexes, -1)
+
+	for index, fut := range futures {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(fut.Chan())})
+		indexes = append(indexes, index)
+	}
+
+	return &watcher[T]{
+		ctx:     ctx,
+		cases:   cases,
+		indexes: indexes,
+	}
+}
+
+// next returns the result of the next future to settle, or ctx.Err() once ctx is done.
+func (w *watcher[T]) next() indexedResult[T] {
+	chosen, received, ok := reflect.Select(w.cases)
+	if chosen == 0 {
+		return indexedResult[T]{
+			index: -1,
+			err:   w.ctx.Err(),
+		}
+	}
+
+	result := indexedResult[T]{
+		index: w.indexes[chosen],
+	}
+
+	w.cases = slices.Delete(w.cases, chosen, chosen+1)
+	w.indexes = slices.Delete(w.indexes, chosen, chosen+1)
+
+	if !ok {
+		result.err = promise.ErrChannelClosed
+
+		return result
+	}
+
+	eith, _ := received.Interface().(either.EitherError[T])
+
+	result.value, result.err = eith.Value()
+
+	return result
+}
+
+func getOrCancel[T any](ctx context.Context, fut Future[T]) (T, error) {
+	value, err := fut.Get(ctx)
+	if err != nil && ctx.Err() != nil {
+		fut.Cancel()
+	}
+
+	return value, err
+}
+
+func cancelAll[T any](futures []Future[T]) {
+	for _, fut := range futures {
+		if !fut.IsResolved() {
+			fut.Cancel()
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/pool"
+	"github.com/pixality-inc/golang-core/promise"
+)
+
+var errCombinatorTest = errors.New("combinator test error")
+
+func blockingFuture(t *testing.T, canceled *atomic.Int32) Future[int] {
+	t.Helper()
+
+	return New(t.Context(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		canceled.Add(1)
+
+		return 0, ctx.Err()
+	})
+}
+
+func TestAllResolvesInOrder(t *testing.T) {
+	t.Parallel()
+
+	slow := New(t.Context(), func(_ context.Context) (int, error) {
+		time.Sleep(20 * time.Millisecond)
+
+		return 1, nil
+	})
+
+	values, err := All(t.Context(), []Future[int]{slow, Resolved(2), Resolved(3)}).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestAllEmpty(t *testing.T) {
+	t.Parallel()
+
+	values, err := All[int](t.Context(), nil).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func TestAllFailsFastAndCancelsSiblings(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Int32
+
+	futures := []Future[int]{
+		blockingFuture(t, &canceled),
+		Rejected[int](errCombinatorTest),
+		blockingFuture(t, &canceled),
+	}
+
+	_, err := All(t.Context(), futures).Get(t.Context())
+
+	require.ErrorIs(t, err, errCombinatorTest)
+	require.Eventually(t, func() bool { return canceled.Load() == 2 }, time.Second, time.Millisecond)
+
+	_, err = futures[0].Get(t.Context())
+	require.ErrorIs(t, err, ErrCanceled)
+}
+
+func TestAllSettled(t *testing.T) {
+	t.Parallel()
+
+	outcomes, err := AllSettled(t.Context(), []Future[int]{
+		Resolved(1),
+		Rejected[int](errCombinatorTest),
+	}).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	requireEitherRight(t, outcomes[0], 1)
+	requireEitherLeft(t, outcomes[1], errCombinatorTest)
+}
+
+func TestAnyReturnsFirstSuccess(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Int32
+
+	value, err := Any(t.Context(), []Future[int]{
+		Rejected[int](errCombinatorTest),
+		blockingFuture(t, &canceled),
+		Resolved(7),
+	}).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, 7, value)
+	require.Eventually(t, func() bool { return canceled.Load() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestAnyAllFailed(t *testing.T) {
+	t.Parallel()
+
+	_, err := Any(t.Context(), []Future[int]{
+		Rejected[int](errCombinatorTest),
+		Rejected[int](errFutureTest),
+	}).Get(t.Context())
+
+	require.ErrorIs(t, err, ErrAllFailed)
+	require.ErrorIs(t, err, errCombinatorTest)
+	require.ErrorIs(t, err, errFutureTest)
+
+	_, err = Any[int](t.Context(), nil).Get(t.Context())
+	require.ErrorIs(t, err, ErrNoFutures)
+}
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Int32
+
+	_, err := Race(t.Context(), []Future[int]{
+		blockingFuture(t, &canceled),
+		Rejected[int](errCombinatorTest),
+	}).Get(t.Context())
+
+	require.ErrorIs(t, err, errCombinatorTest)
+	require.Eventually(t, func() bool { return canceled.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err = Race[int](t.Context(), nil).Get(t.Context())
+	require.ErrorIs(t, err, ErrNoFutures)
+}
+
+func TestMapAndFlatMap(t *testing.T) {
+	t.Parallel()
+
+	mapped := Map(t.Context(), Resolved(21), func(_ context.Context, value int) (string, error) {
+		return strconv.Itoa(value * 2), nil
+	})
+
+	value, err := mapped.Get(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "42", value)
+
+	flatMapped := FlatMap(t.Context(), mapped, func(ctx context.Context, value string) Future[int] {
+		return New(ctx, func(_ context.Context) (int, error) {
+			return strconv.Atoi(value)
+		})
+	})
+
+	number, err := flatMapped.Get(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 42, number)
+
+	var called atomic.Bool
+
+	_, err = Map(t.Context(), Rejected[int](errCombinatorTest), func(_ context.Context, value int) (int, error) {
+		called.Store(true)
+
+		return value, nil
+	}).Get(t.Context())
+
+	require.ErrorIs(t, err, errCombinatorTest)
+	require.False(t, called.Load())
+}
+
+func TestThenReceivesError(t *testing.T) {
+	t.Parallel()
+
+	value, err := Then(t.Context(), Rejected[int](errCombinatorTest), func(_ context.Context, _ int, err error) (string, error) {
+		if errors.Is(err, errCombinatorTest) {
+			return "recovered", nil
+		}
+
+		return "", err
+	}).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, "recovered", value)
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Int32
+
+	_, err := WithTimeout(t.Context(), blockingFuture(t, &canceled), 10*time.Millisecond).Get(t.Context())
+
+	require.ErrorIs(t, err, ErrTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Eventually(t, func() bool { return canceled.Load() == 1 }, time.Second, time.Millisecond)
+
+	value, err := WithTimeout(t.Context(), Resolved(5), time.Second).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, 5, value)
+}
+
+func TestCombinatorCancellationPropagates(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Int32
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	all := All(ctx, []Future[int]{blockingFuture(t, &canceled), blockingFuture(t, &canceled)})
+
+	cancel()
+
+	_, err := all.Get(t.Context())
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Eventually(t, func() bool { return canceled.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestCallbacks(t *testing.T) {
+	t.Parallel()
+
+	successes := make(chan int, 1)
+	failures := make(chan error, 1)
+
+	OnSuccess(t.Context(), Resolved(3), func(_ context.Context, value int) {
+		successes <- value
+	})
+
+	OnFailure(t.Context(), Rejected[int](errCombinatorTest), func(_ context.Context, err error) {
+		failures <- err
+	})
+
+	OnSuccess(t.Context(), Rejected[int](errCombinatorTest), func(_ context.Context, value int) {
+		successes <- value
+	})
+
+	select {
+	case value := <-successes:
+		require.Equal(t, 3, value)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for success callback")
+	}
+
+	select {
+	case err := <-failures:
+		require.ErrorIs(t, err, errCombinatorTest)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for failure callback")
+	}
+}
+
+func TestCombinatorsUsePoolExecutor(t *testing.T) {
+	t.Parallel()
+
+	poolExecutor := pool.New("future-test", 4)
+	require.NoError(t, poolExecutor.Start(t.Context()))
+
+	defer func() {
+		require.NoError(t, poolExecutor.Stop())
+	}()
+
+	futures := []Future[int]{
+		New(t.Context(), func(_ context.Context) (int, error) { return 1, nil }, WithPoolExecutor(poolExecutor)),
+		New(t.Context(), func(_ context.Context) (int, error) { return 2, nil }, WithPoolExecutor(poolExecutor)),
+	}
+
+	values, err := All(t.Context(), futures, WithPoolExecutor(poolExecutor)).Get(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, values)
+}
+
+func TestFutureRejectsWhenPoolNotStarted(t *testing.T) {
+	t.Parallel()
+
+	fut := New(t.Context(), func(_ context.Context) (int, error) {
+		return 1, nil
+	}, WithPoolExecutor(pool.New("not-started", 1)))
+
+	_, err := fut.Get(t.Context())
+
+	require.ErrorIs(t, err, pool.ErrNotStarted)
+}
+
+func TestCombinatorsDontSpawnGoroutinePerFuture(t *testing.T) { //nolint:paralleltest // counts goroutines
+	promises := make([]promise.Promise[int], 100)
+	futures := make([]Future[int], len(promises))
+
+	for index := range promises {
+		promises[index] = promise.New[int]()
+		futures[index] = &Impl[int]{promise: promises[index], cancel: func() {}, options: NewDefaultOptions()}
+	}
+
+	goroutines := runtime.NumGoroutine()
+
+	all := All(t.Context(), futures)
+	race := Race(t.Context(), futures)
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.Less(t, runtime.NumGoroutine(), goroutines+10)
+
+	for index, p := range promises {
+		require.NoError(t, p.Resolve(index))
+	}
+
+	values, err := all.Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, values, len(promises))
+	require.Equal(t, 99, values[99])
+
+	_, err = race.Get(t.Context())
+	require.NoError(t, err)
+}
@@ -10,15 +10,19 @@ import (
 	"github.com/pixality-inc/golang-core/promise"
 )
 
+var ErrCanceled = errors.New("future canceled")
+
 type Future[T any] interface {
 	Get(ctx context.Context) (T, error)
 	Chan() <-chan either.EitherError[T]
 	IsResolved() bool
+	Cancel()
 }
 
 type Impl[T any] struct {
 	promise promise.Promise[T]
 	body    func(ctx context.Context) (T, error)
+	cancel  context.CancelFunc
 	options *Options
 }
 
@@ -27,17 +31,42 @@ func New[T any](
 	body func(ctx context.Context) (T, error),
 	options ...Option,
 ) Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+
 	impl := &Impl[T]{
 		promise: promise.New[T](),
 		body:    body,
+		cancel:  cancel,
+		options: applyOptions(options...),
+	}
+
+	impl.execute(ctx)
+
+	return impl
+}
+
+// Resolved returns an already resolved future.
+func Resolved[T any](value T) Future[T] {
+	impl := &Impl[T]{
+		promise: promise.New[T](),
+		cancel:  func() {},
 		options: NewDefaultOptions(),
 	}
 
-	for _, option := range options {
-		option(impl.options)
+	_ = impl.promise.Resolve(value)
+
+	return impl
+}
+
+// Rejected returns an already rejected future.
+func Rejected[T any](err error) Future[T] {
+	impl := &Impl[T]{
+		promise: promise.New[T](),
+		cancel:  func() {},
+		options: NewDefaultOptions(),
 	}
 
-	impl.execute(ctx)
+	_ = impl.promise.Reject(err)
 
 	return impl
 }
@@ -54,24 +83,38 @@ func (f *Impl[T]) IsResolved() bool {
 	return f.promise.IsResolved()
 }
 
+// Cancel cancels the context passed to the body and rejects the future with ErrCanceled
+// unless it is already resolved.
+func (f *Impl[T]) Cancel() {
+	f.cancel()
+
+	_ = f.promise.Reject(errors.Join(ErrCanceled, context.Canceled))
+}
+
 func (f *Impl[T]) execute(ctx context.Context) {
 	err := f.options.poolExecutor.Execute(ctx, f.run)
 	if err != nil {
 		logger.GetLogger(ctx).WithError(err).Error("failed to execute future with pool executor")
+
+		f.cancel()
+
+		_ = f.promise.Reject(err)
 	}
 }
 
 func (f *Impl[T]) run(ctx context.Context) error {
+	defer f.cancel()
+
 	value, err := f.body(ctx)
 	if err != nil {
-		if rErr := f.promise.Reject(err); rErr != nil {
+		if rErr := f.promise.Reject(err); rErr != nil && !errors.Is(rErr, promise.ErrAlreadyResolved) {
 			return fmt.Errorf("future reject: %w", errors.Join(rErr, err))
 		}
 
 		return err
 	}
 
-	if rErr := f.promise.Resolve(value); rErr != nil {
+	if rErr := f.promise.Resolve(value); rErr != nil && !errors.Is(rErr, promise.ErrAlreadyResolved) {
 		return fmt.Errorf("future resolve: %w", rErr)
 	}
 
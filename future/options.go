@@ -19,3 +19,13 @@ func WithPoolExecutor(pe pool.PoolExecutor) Option {
 		options.poolExecutor = pe
 	}
 }
+
+func applyOptions(options ...Option) *Options {
+	opts := NewDefaultOptions()
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
@@ -235,7 +235,19 @@ type ApiRouteParameterEnumValue struct {
 	Value string `json:"value" yaml:"value"`
 }
 
+// ApiConstraints bound the values of parameters and body fields, lengths of arrays
+// bound the number of their items.
+type ApiConstraints struct {
+	Min       *float64 `json:"min"       yaml:"min"`
+	Max       *float64 `json:"max"       yaml:"max"`
+	MinLength *int     `json:"minLength" yaml:"minLength"`
+	MaxLength *int     `json:"maxLength" yaml:"maxLength"`
+	Pattern   string   `json:"pattern"   yaml:"pattern"`
+}
+
 type ApiRouteParameter struct {
+	ApiConstraints `json:",inline" yaml:",inline"`
+
 	In          ApiRouteParameterIn          `json:"in"          yaml:"in"`
 	Type        string                       `json:"type"        yaml:"type"`
 	Array       bool                         `json:"array"       yaml:"array"`
@@ -247,6 +259,14 @@ type ApiRouteParameter struct {
 	Enum        []ApiRouteParameterEnumValue `json:"enum"        yaml:"enum"`
 }
 
+type ApiFieldConstraints struct {
+	ApiConstraints `json:",inline" yaml:",inline"`
+
+	Required bool     `json:"required" yaml:"required"`
+	Format   string   `json:"format"   yaml:"format"`
+	Enum     []string `json:"enum"     yaml:"enum"`
+}
+
 type ApiRouteRequestFile struct {
 	Name string `json:"name" yaml:"name"`
 }
@@ -308,8 +328,10 @@ type Api struct {
 	ControllerImports []ApiImport                               `json:"controllerImports" yaml:"controllerImports"`
 	RouterImports     []ApiImport                               `json:"routerImports"     yaml:"routerImports"`
 	ServerImports     []ApiImport                               `json:"serverImports"     yaml:"serverImports"`
+	ClientImports     []ApiImport                               `json:"clientImports"     yaml:"clientImports"`
 	Routes            map[string]map[ApiRouteOperation]ApiRoute `json:"routes"            yaml:"routes"`
 	Errors            map[string]string                         `json:"errors"            yaml:"errors"`
+	Constraints       map[string]map[string]ApiFieldConstraints `json:"constraints"       yaml:"constraints"`
 }
 
 func (a *Api) GetRoutes() []ApiRouteEntry {
@@ -401,7 +423,7 @@ type TagEntry struct {
 	Tag  *openapi3.Tag
 }
 
-func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums ApiEnums, apiModels ApiModels) error {
+func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums ApiEnums, apiModels ApiModels) (*openapi3.T, error) {
 	log := g.log.GetLogger(ctx)
 
 	swaggerFilename := path.Join(g.config.DocsDir, "swagger.yaml")
@@ -409,7 +431,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 
 	modelFields, err := g.generateApiGen(ctx, genFilename, apiSchema)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Base schema
@@ -452,7 +474,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 				},
 			}
 		default:
-			return fmt.Errorf("%w: %s", errUnknownSecurityType, security.Type)
+			return nil, fmt.Errorf("%w: %s", errUnknownSecurityType, security.Type)
 		}
 	}
 
@@ -731,6 +753,18 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 				property = arrayProperty(property, extras)
 			}
 
+			if constraints, ok := apiSchema.Api.Constraints[protoModelName][name]; ok {
+				resultIsRequired = resultIsRequired || constraints.Required
+
+				enumValues := make([]any, 0, len(constraints.Enum))
+
+				for _, enumValue := range constraints.Enum {
+					enumValues = append(enumValues, enumValue)
+				}
+
+				applySchemaConstraints(property, constraints.ApiConstraints, constraints.Format, enumValues)
+			}
+
 			return property, resultIsRequired, nil
 		}
 
@@ -899,7 +933,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 
 			if operation.Security != nil {
 				if len(operation.Security) > 1 {
-					return fmt.Errorf("%w: '%s'", errOnlyOneSecurityAllowed, operation.Id)
+					return nil, fmt.Errorf("%w: '%s'", errOnlyOneSecurityAllowed, operation.Id)
 				}
 
 				pathOperation.Security = &openapi3.SecurityRequirements{openapi3.SecurityRequirement{
@@ -924,7 +958,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 				case "number", "float":
 					schemaType = openapi3.TypeNumber
 				default:
-					return fmt.Errorf("%w: '%s' for '%s' in '%s'", errUnknownParameterType, param.Type, paramName, operation.Id)
+					return nil, fmt.Errorf("%w: '%s' for '%s' in '%s'", errUnknownParameterType, param.Type, paramName, operation.Id)
 				}
 
 				schema := &openapi3.SchemaRef{
@@ -944,14 +978,31 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 					schema.Value.Enum = enumValues
 				}
 
+				parameter := &openapi3.Parameter{
+					Name:        paramName,
+					In:          string(param.In),
+					Description: param.Description,
+					Required:    param.Required,
+					Schema:      schema,
+				}
+
+				// Arrays are passed comma separated.
+				if param.Array {
+					parameter.Schema = &openapi3.SchemaRef{
+						Value: &openapi3.Schema{
+							Type:  &openapi3.Types{openapi3.TypeArray},
+							Items: schema,
+						},
+					}
+
+					parameter.Style = openapi3.SerializationForm
+					parameter.Explode = new(false)
+				}
+
+				applySchemaConstraints(parameter.Schema, param.ApiConstraints, "", nil)
+
 				parameters = append(parameters, &openapi3.ParameterRef{
-					Value: &openapi3.Parameter{
-						Name:        paramName,
-						In:          string(param.In),
-						Description: param.Description,
-						Required:    param.Required,
-						Schema:      schema,
-					},
+					Value: parameter,
 				})
 			}
 
@@ -989,7 +1040,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 				}
 			} else if operation.RequestModel != "" {
 				if requestModelName, err := addSchema(operation.RequestModel); err != nil {
-					return err
+					return nil, err
 				} else {
 					pathOperation.RequestBody = &openapi3.RequestBodyRef{
 						Value: &openapi3.RequestBody{
@@ -1014,7 +1065,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 
 			for _, responseModel := range operation.ResponseModels {
 				if responseModelName, err := addSchema(responseModel); err != nil {
-					return err
+					return nil, err
 				} else {
 					responseRef := &openapi3.ResponseRef{
 						Value: &openapi3.Response{
@@ -1031,7 +1082,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 					if _, ok := apiSchema.Api.Errors[responseModel]; ok {
 						statusCode, err := strconv.Atoi(responseModel)
 						if err != nil {
-							return err
+							return nil, err
 						}
 
 						responses = append(responses, openapi3.WithStatus(statusCode, responseRef))
@@ -1055,7 +1106,7 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 			case ApiRouteOperationDelete:
 				pathItem.Delete = pathOperation
 			default:
-				return fmt.Errorf("%w: '%s'", errUnknownRouteOperation, routeOperation)
+				return nil, fmt.Errorf("%w: '%s'", errUnknownRouteOperation, routeOperation)
 			}
 		}
 
@@ -1103,15 +1154,15 @@ func (g *Gen) generateApi(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 	// (encoding/json sorts map keys, gopkg.in/yaml.v3 does not guarantee that).
 	specBuf, err := sigsyaml.Marshal(spec)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	//nolint:gosec // G306: generated file permissions are intentionally permissive
 	if err = os.WriteFile(swaggerFilename, specBuf, os.ModePerm); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return spec, nil
 }
 
 func (g *Gen) generateApiGen(ctx context.Context, genFilename string, apiSchema *ApiSchema) (map[string]map[string]ProtoField, error) {
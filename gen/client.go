@@ -0,0 +1,553 @@
+// nolint
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobeam/stringy"
+)
+
+var errUnknownErrorStatus = errors.New("api error must be declared by its http status code")
+
+var nonAlphanumericRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+type clientError struct {
+	StatusCode int
+	Model      string
+	Name       string
+}
+
+// generateClient writes the typed Go client of the api, one method per route and
+// operation over http_client.Client.
+func (g *Gen) generateClient(ctx context.Context, apiSchema *ApiSchema) error {
+	log := g.log.GetLogger(ctx)
+
+	clientFilename := path.Join(g.config.ClientDir, "client_gen.go")
+
+	log.Infof("Generating api client '%s'", clientFilename)
+
+	if err := os.MkdirAll(g.config.ClientDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	clientErrors, err := getClientErrors(apiSchema)
+	if err != nil {
+		return err
+	}
+
+	var (
+		paramsGen    []byte
+		interfaceGen []byte
+		methodsGen   []byte
+		hasFile      bool
+	)
+
+	for _, routeEntry := range apiSchema.Api.GetRoutes() {
+		for _, operationEntry := range routeEntry.Operations {
+			operation := operationEntry.Route
+
+			method, err := clientHttpMethod(operationEntry.Operation)
+			if err != nil {
+				return fmt.Errorf("%w: '%s'", err, operation.Id)
+			}
+
+			paramsName := ""
+
+			if len(operation.Parameters) > 0 ||
+				operation.RequestModel != "" ||
+				operation.RawBody ||
+				operation.RawHeaders ||
+				len(operation.RequestFiles) > 0 {
+				paramsName = operation.Id + "Params"
+			}
+
+			responseModel := ""
+
+			for _, modelName := range operation.ResponseModels {
+				if _, ok := apiSchema.Api.Errors[modelName]; ok {
+					continue
+				}
+
+				responseModel = "*" + modelName
+
+				break
+			}
+
+			if len(operation.RequestFiles) > 0 {
+				hasFile = true
+			}
+
+			// Params
+
+			if paramsName != "" {
+				paramsGen = append(paramsGen, []byte(fmt.Sprintf("type %s struct {\n", paramsName))...)
+
+				for _, paramEntry := range operation.GetParameters() {
+					typeStr, _, err := clientParameterType(paramEntry.Parameter)
+					if err != nil {
+						return fmt.Errorf("%w: '%s' in '%s'", err, paramEntry.Name, operation.Id)
+					}
+
+					paramsGen = append(paramsGen, []byte(fmt.Sprintf("\t%s %s\n", clientParameterName(paramEntry.Name), typeStr))...)
+				}
+
+				if operation.RequestModel != "" {
+					paramsGen = append(paramsGen, []byte(fmt.Sprintf("\tRequest *%s\n", operation.RequestModel))...)
+				}
+
+				if operation.RawBody {
+					paramsGen = append(paramsGen, []byte("\tRawBody []byte\n")...)
+				}
+
+				if operation.RawHeaders {
+					paramsGen = append(paramsGen, []byte("\tRawHeaders map[string]string\n")...)
+				}
+
+				for _, fileEntry := range operation.RequestFiles {
+					variableName := stringy.New(fileEntry.Name).CamelCase().UcFirst()
+
+					paramsGen = append(paramsGen, []byte(fmt.Sprintf("\t%s *File\n", variableName))...)
+				}
+
+				paramsGen = append(paramsGen, []byte("}\n\n")...)
+			}
+
+			// Interface
+
+			methodParams := []string{"ctx context.Context"}
+
+			if paramsName != "" {
+				methodParams = append(methodParams, "params "+paramsName)
+			}
+
+			methodParams = append(methodParams, "opts ...http_client.RequestOption")
+
+			methodResults := "error"
+
+			if responseModel != "" {
+				methodResults = "(" + responseModel + ", error)"
+			}
+
+			signature := fmt.Sprintf("%s(%s) %s", operation.Id, strings.Join(methodParams, ", "), methodResults)
+
+			interfaceGen = append(interfaceGen, []byte("\t"+signature+"\n")...)
+
+			// Method
+
+			errorResult := "err"
+
+			if responseModel != "" {
+				errorResult = "nil, err"
+			}
+
+			methodsGen = append(methodsGen, []byte(fmt.Sprintf("func (c *ClientImpl) %s {\n", signature))...)
+			methodsGen = append(methodsGen, []byte(fmt.Sprintf("\turi := %s\n", strconv.Quote(routeEntry.Url)))...)
+
+			for _, paramEntry := range operation.GetParameters() {
+				param := paramEntry.Parameter
+
+				if param.In != ApiRouteParameterInPath {
+					continue
+				}
+
+				placeholder := regexp.MustCompile(`\{` + regexp.QuoteMeta(paramEntry.Name) + `(\?|:[^}]*)?\}`).FindString(routeEntry.Url)
+				if placeholder == "" {
+					return fmt.Errorf("%w: path parameter '%s' is not in '%s'", errUnknownParameterIn, paramEntry.Name, routeEntry.Url)
+				}
+
+				value, err := clientParameterValue(param, "params."+clientParameterName(paramEntry.Name))
+				if err != nil {
+					return fmt.Errorf("%w: '%s' in '%s'", err, paramEntry.Name, operation.Id)
+				}
+
+				if param.Required || param.Array {
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf("\turi = strings.ReplaceAll(uri, %s, url.PathEscape(%s))\n", strconv.Quote(placeholder), value))...)
+				} else {
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf(`
+	if params.%s != nil {
+		uri = strings.ReplaceAll(uri, %s, url.PathEscape(%s))
+	} else {
+		uri = strings.ReplaceAll(uri, %s, "")
+	}
+`, clientParameterName(paramEntry.Name), strconv.Quote(placeholder), value, strconv.Quote(placeholder)))...)
+				}
+			}
+
+			methodsGen = append(methodsGen, []byte(`
+	requestOptions := []http_client.RequestOption{
+		http_client.WithHeader("Accept", string(c.format)),
+	}
+`)...)
+
+			for _, paramEntry := range operation.GetParameters() {
+				param := paramEntry.Parameter
+
+				switch param.In {
+				case ApiRouteParameterInPath:
+					continue
+				case ApiRouteParameterInQuery:
+				default:
+					return fmt.Errorf("%w: '%s' in '%s'", errUnknownParameterIn, paramEntry.Name, param.In)
+				}
+
+				fieldName := "params." + clientParameterName(paramEntry.Name)
+
+				value, err := clientParameterValue(param, fieldName)
+				if err != nil {
+					return fmt.Errorf("%w: '%s' in '%s'", err, paramEntry.Name, operation.Id)
+				}
+
+				withQueryParam := fmt.Sprintf("requestOptions = append(requestOptions, http_client.WithQueryParam(%s, %s))", strconv.Quote(paramEntry.Name), value)
+
+				switch {
+				case param.Array:
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf("\n\tif len(%s) > 0 {\n\t\t%s\n\t}\n", fieldName, withQueryParam))...)
+				case param.Required:
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf("\n\t%s\n", withQueryParam))...)
+				default:
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf("\n\tif %s != nil {\n\t\t%s\n\t}\n", fieldName, withQueryParam))...)
+				}
+			}
+
+			if operation.RequestModel != "" {
+				methodsGen = append(methodsGen, []byte(fmt.Sprintf(`
+	if params.Request != nil {
+		body, err := http_client.MarshalProto(params.Request, c.format)
+		if err != nil {
+			return %s
+		}
+
+		requestOptions = append(requestOptions, http_client.WithBody(body), http_client.WithHeader("Content-Type", string(c.format)))
+	}
+`, errorResult))...)
+			}
+
+			if operation.RawBody {
+				methodsGen = append(methodsGen, []byte(`
+	requestOptions = append(requestOptions, http_client.WithBody(params.RawBody))
+`)...)
+			}
+
+			if operation.RawHeaders {
+				methodsGen = append(methodsGen, []byte(`
+	for key, value := range params.RawHeaders {
+		requestOptions = append(requestOptions, http_client.WithHeader(key, value))
+	}
+`)...)
+			}
+
+			if len(operation.RequestFiles) > 0 {
+				methodsGen = append(methodsGen, []byte("\n\tformData := http_client.NewFormDataImpl()\n")...)
+
+				for _, fileEntry := range operation.RequestFiles {
+					variableName := stringy.New(fileEntry.Name).CamelCase().UcFirst()
+
+					methodsGen = append(methodsGen, []byte(fmt.Sprintf(`
+	if params.%s != nil {
+		if err := formData.AddFile(%s, params.%s.Name, params.%s.ContentType, params.%s.Body); err != nil {
+			return %s
+		}
+	}
+`, variableName, strconv.Quote(fileEntry.Name), variableName, variableName, variableName, errorResult))...)
+				}
+
+				methodsGen = append(methodsGen, []byte("\n\trequestOptions = append(requestOptions, http_client.WithFormData(formData))\n")...)
+			}
+
+			methodsGen = append(methodsGen, []byte(fmt.Sprintf(`
+	response, err := c.httpClient.Do(ctx, %s, uri, append(requestOptions, opts...)...)
+	if err != nil {
+		err = decodeError(response, err)
+
+		return %s
+	}
+`, strconv.Quote(method), errorResult))...)
+
+			if responseModel != "" {
+				methodsGen = append(methodsGen, []byte(fmt.Sprintf(`
+	result := new(%s)
+
+	if err := http_client.UnmarshalProto(response, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+`, strings.TrimPrefix(responseModel, "*")))...)
+			} else {
+				methodsGen = append(methodsGen, []byte("\n\treturn nil\n}\n\n")...)
+			}
+		}
+	}
+
+	// Errors
+
+	var errorsGen []byte
+
+	if len(clientErrors) > 0 {
+		errorsGen = append(errorsGen, []byte("var (\n")...)
+
+		for _, clientError := range clientErrors {
+			errorsGen = append(errorsGen, []byte(fmt.Sprintf(
+				"\tErr%s = errors.New(%s)\n",
+				clientError.Name,
+				strconv.Quote(strings.ToLower(http.StatusText(clientError.StatusCode))),
+			))...)
+		}
+
+		errorsGen = append(errorsGen, []byte(")\n\n")...)
+
+		for _, clientError := range clientErrors {
+			errorsGen = append(errorsGen, []byte(fmt.Sprintf(`// %sError is returned for %d responses, it matches Err%s with errors.Is.
+type %sError struct {
+	Response *%s
+	Err      error
+}
+
+func (e *%sError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *%sError) Unwrap() []error {
+	return []error{Err%s, e.Err}
+}
+
+`,
+				clientError.Name, clientError.StatusCode, clientError.Name,
+				clientError.Name, clientError.Model,
+				clientError.Name,
+				clientError.Name, clientError.Name,
+			))...)
+		}
+	}
+
+	errorsGen = append(errorsGen, []byte(`// decodeError maps the declared api errors to their typed errors.
+func decodeError(response http_client.Response, err error) error {
+	if response == nil {
+		return err
+	}
+
+	switch response.GetStatusCode() {
+`)...)
+
+	for _, clientError := range clientErrors {
+		errorsGen = append(errorsGen, []byte(fmt.Sprintf(`	case %d:
+		model := new(%s)
+
+		if decodeErr := http_client.UnmarshalProto(response, model); decodeErr != nil {
+			return errors.Join(err, decodeErr)
+		}
+
+		return &%sError{Response: model, Err: err}
+`, clientError.StatusCode, clientError.Model, clientError.Name))...)
+	}
+
+	errorsGen = append(errorsGen, []byte(`	default:
+		return err
+	}
+}
+`)...)
+
+	// File
+
+	var clientGen []byte
+
+	clientGen = append(clientGen, []byte(`type Client interface {
+`)...)
+	clientGen = append(clientGen, interfaceGen...)
+	clientGen = append(clientGen, []byte(`}
+
+type ClientImpl struct {
+	httpClient http_client.Client
+	format     http_client.ProtoFormat
+}
+
+// NewClient sends and accepts proto messages as format, auth headers like
+// http_client.WithBearerToken are passed to the methods as options.
+func NewClient(httpClient http_client.Client, format http_client.ProtoFormat) *ClientImpl {
+	return &ClientImpl{
+		httpClient: httpClient,
+		format:     format,
+	}
+}
+
+`)...)
+
+	if hasFile {
+		clientGen = append(clientGen, []byte(`type File struct {
+	Name        string
+	ContentType string
+	Body        io.Reader
+}
+
+`)...)
+	}
+
+	clientGen = append(clientGen, paramsGen...)
+	clientGen = append(clientGen, methodsGen...)
+	clientGen = append(clientGen, errorsGen...)
+
+	imports := [][]string{
+		{"", "context"},
+		{"", "github.com/pixality-inc/golang-core/http_client"},
+	}
+
+	for _, imp := range []struct {
+		usage string
+		path  string
+	}{
+		{"errors.", "errors"},
+		{"io.Reader", "io"},
+		{"url.PathEscape", "net/url"},
+		{"strconv.", "strconv"},
+		{"strings.", "strings"},
+		{"fmt.Sprint", "fmt"},
+		{"uuid.UUID", "github.com/google/uuid"},
+		{"time.Time", "time"},
+	} {
+		if bytes.Contains(clientGen, []byte(imp.usage)) {
+			imports = append(imports, []string{"", imp.path})
+		}
+	}
+
+	imports = append(imports, apiSchema.Api.ClientImports...)
+
+	result := generateFile(g.config.ClientPackageName, imports)
+	result = append(result, '\n', '\n')
+	result = append(result, clientGen...)
+
+	//nolint:gosec // G306: generated file permissions are intentionally permissive
+	return os.WriteFile(clientFilename, result, os.ModePerm)
+}
+
+func getClientErrors(apiSchema *ApiSchema) ([]clientError, error) {
+	clientErrors := make([]clientError, 0, len(apiSchema.Api.Errors))
+
+	for status, model := range apiSchema.Api.Errors {
+		statusCode, err := strconv.Atoi(status)
+		if err != nil || http.StatusText(statusCode) == "" {
+			return nil, fmt.Errorf("%w: '%s'", errUnknownErrorStatus, status)
+		}
+
+		clientErrors = append(clientErrors, clientError{
+			StatusCode: statusCode,
+			Model:      model,
+			Name:       nonAlphanumericRegexp.ReplaceAllString(http.StatusText(statusCode), ""),
+		})
+	}
+
+	sort.Slice(clientErrors, func(i, j int) bool {
+		return clientErrors[i].StatusCode < clientErrors[j].StatusCode
+	})
+
+	return clientErrors, nil
+}
+
+func clientHttpMethod(operation ApiRouteOperation) (string, error) {
+	switch operation {
+	case ApiRouteOperationGet:
+		return http.MethodGet, nil
+	case ApiRouteOperationPost:
+		return http.MethodPost, nil
+	case ApiRouteOperationPut:
+		return http.MethodPut, nil
+	case ApiRouteOperationPatch:
+		return http.MethodPatch, nil
+	case ApiRouteOperationDelete:
+		return http.MethodDelete, nil
+	default:
+		return "", fmt.Errorf("%w: '%s'", errUnknownOperationType, operation)
+	}
+}
+
+func clientParameterName(name string) string {
+	return stringy.New(name).SnakeCase().CamelCase().UcFirst()
+}
+
+// clientParameterType mirrors the types of the controller request models.
+func clientParameterType(param ApiRouteParameter) (string, string, error) {
+	var typeStr string
+
+	if param.Model != "" {
+		typeStr = param.Model
+	} else if param.Format != "" && !parameterIsString(param) {
+		switch param.Format {
+		case "uint64":
+			typeStr = "uint64"
+		case "uuid":
+			typeStr = "uuid.UUID"
+		case "unix_time":
+			typeStr = "time.Time"
+		default:
+			return "", "", fmt.Errorf("%w: '%s'", errUnknownParameterFormat, param.Format)
+		}
+	} else {
+		switch param.Type {
+		case "string":
+			typeStr = "string"
+		case "bool":
+			typeStr = "bool"
+		default:
+			return "", "", fmt.Errorf("%w: '%s'", errUnknownParameterType, param.Type)
+		}
+	}
+
+	elementType := typeStr
+
+	if param.Array {
+		if typeStr != "string" {
+			return "", "", errParameterArrayNotSupported
+		}
+
+		typeStr = "[]" + typeStr
+	} else if !param.Required {
+		typeStr = "*" + typeStr
+	}
+
+	return typeStr, elementType, nil
+}
+
+// clientParameterValue renders the expression formatting the field as the string
+// the request handler parses.
+func clientParameterValue(param ApiRouteParameter, fieldName string) (string, error) {
+	_, elementType, err := clientParameterType(param)
+	if err != nil {
+		return "", err
+	}
+
+	if param.Array {
+		return "strings.Join(" + fieldName + ", \",\")", nil
+	}
+
+	value := fieldName
+
+	if !param.Required {
+		value = "*" + fieldName
+	}
+
+	switch elementType {
+	case "string":
+		return value, nil
+	case "bool":
+		return "strconv.FormatBool(" + value + ")", nil
+	case "uint64":
+		return "strconv.FormatUint(" + value + ", 10)", nil
+	case "uuid.UUID":
+		return fieldName + ".String()", nil
+	case "time.Time":
+		return "strconv.FormatInt(" + fieldName + ".Unix(), 10)", nil
+	default:
+		return "fmt.Sprint(" + value + ")", nil
+	}
+}
@@ -0,0 +1,97 @@
+package gen
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testClientApiSchema() *ApiSchema {
+	return &ApiSchema{
+		Api: Api{
+			ClientImports: []ApiImport{
+				{"", "google.golang.org/protobuf/types/known/emptypb"},
+				{"", "google.golang.org/protobuf/types/known/wrapperspb"},
+			},
+			Routes: map[string]map[ApiRouteOperation]ApiRoute{
+				"/users/{id}": {
+					ApiRouteOperationGet: {
+						Id: "GetUser",
+						Parameters: map[string]ApiRouteParameter{
+							"id":     {In: ApiRouteParameterInPath, Type: "string", Format: "uuid", Required: true},
+							"fields": {In: ApiRouteParameterInQuery, Type: "string", Array: true},
+							"full":   {In: ApiRouteParameterInQuery, Type: "bool"},
+						},
+						ResponseModels: []string{"wrapperspb.StringValue", "404"},
+					},
+					ApiRouteOperationPut: {
+						Id: "UpdateUser",
+						Parameters: map[string]ApiRouteParameter{
+							"id": {In: ApiRouteParameterInPath, Type: "string", Format: "uuid", Required: true},
+						},
+						RequestModel:   "wrapperspb.StringValue",
+						ResponseModels: []string{"emptypb.Empty", "400"},
+					},
+				},
+				"/avatars": {
+					ApiRouteOperationPost: {
+						Id:           "UploadAvatar",
+						RequestFiles: []ApiRouteRequestFile{{Name: "avatar"}},
+						IsHttp:       true,
+					},
+				},
+			},
+			Errors: map[string]string{
+				"400": "wrapperspb.StringValue",
+				"404": "wrapperspb.StringValue",
+			},
+		},
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	generator := NewGen(&Config{
+		ClientDir:         dir,
+		ClientPackageName: "client",
+	})
+
+	require.NoError(t, generator.generateClient(context.Background(), testClientApiSchema()))
+
+	clientFilename := path.Join(dir, "client_gen.go")
+
+	fset := token.NewFileSet()
+
+	typeCheckGenerated(t, fset, newGeneratedImporter(fset), "example.com/client", dir)
+
+	content, err := os.ReadFile(clientFilename)
+	require.NoError(t, err)
+
+	for _, expected := range []string{
+		"GetUser(ctx context.Context, params GetUserParams, opts ...http_client.RequestOption) (*wrapperspb.StringValue, error)",
+		"UpdateUser(ctx context.Context, params UpdateUserParams, opts ...http_client.RequestOption) (*emptypb.Empty, error)",
+		"UploadAvatar(ctx context.Context, params UploadAvatarParams, opts ...http_client.RequestOption) error",
+		`uri = strings.ReplaceAll(uri, "{id}", url.PathEscape(params.Id.String()))`,
+		`http_client.WithQueryParam("fields", strings.Join(params.Fields, ","))`,
+		`http_client.WithQueryParam("full", strconv.FormatBool(*params.Full))`,
+		"Full *bool",
+		`ErrNotFound = errors.New("not found")`,
+		"return &BadRequestError{Response: model, Err: err}",
+		`"github.com/google/uuid"`,
+		`"io"`,
+	} {
+		require.Contains(t, string(content), expected)
+	}
+
+	schema := testClientApiSchema()
+	schema.Api.Errors["teapot"] = "wrapperspb.StringValue"
+
+	require.ErrorIs(t, generator.generateClient(context.Background(), schema), errUnknownErrorStatus)
+}
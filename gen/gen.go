@@ -34,17 +34,21 @@ var (
 )
 
 type Config struct {
-	ProjectId       string
-	Dir             string
-	DaoDir          string
-	MigrationsDir   string
-	DocsDir         string
-	ApiDir          string
-	ProtoFilename   string
-	ApiModelsPrefix string
-	ApiPackageName  string
-	ApiEnums        ApiEnums
-	ApiModels       ApiModels
+	ProjectId          string
+	Dir                string
+	DaoDir             string
+	MigrationsDir      string
+	DocsDir            string
+	ApiDir             string
+	ProtoFilename      string
+	ApiModelsPrefix    string
+	ApiPackageName     string
+	ApiEnums           ApiEnums
+	ApiModels          ApiModels
+	ConfirmDestructive bool
+	ClientDir          string
+	ClientPackageName  string
+	TypeScriptDir      string
 }
 
 func NewConfig(
@@ -61,17 +65,21 @@ func NewConfig(
 	apiModels ApiModels,
 ) *Config {
 	return &Config{
-		ProjectId:       projectId,
-		Dir:             dir,
-		DaoDir:          daoDir,
-		MigrationsDir:   migrationsDir,
-		DocsDir:         docsDir,
-		ApiDir:          apiDir,
-		ProtoFilename:   protoFilename,
-		ApiModelsPrefix: apiModelsPrefix,
-		ApiPackageName:  apiPackageName,
-		ApiEnums:        apiEnums,
-		ApiModels:       apiModels,
+		ProjectId:          projectId,
+		Dir:                dir,
+		DaoDir:             daoDir,
+		MigrationsDir:      migrationsDir,
+		DocsDir:            docsDir,
+		ApiDir:             apiDir,
+		ProtoFilename:      protoFilename,
+		ApiModelsPrefix:    apiModelsPrefix,
+		ApiPackageName:     apiPackageName,
+		ApiEnums:           apiEnums,
+		ApiModels:          apiModels,
+		ConfirmDestructive: false,
+		ClientDir:          "",
+		ClientPackageName:  "",
+		TypeScriptDir:      "",
 	}
 }
 
@@ -83,6 +91,7 @@ type enumResponse struct {
 type fileResponse struct {
 	sourceFileConfig sourceFileConfig
 	model            *ModelRequest
+	table            schemaTable
 	sql              []byte
 	daoGen           []byte
 	dao              []byte
@@ -94,6 +103,7 @@ type sourceFileField struct {
 	Seq        bool     `json:"seq"        yaml:"seq"`
 	Primary    bool     `json:"primary"    yaml:"primary"`
 	Unique     bool     `json:"unique"     yaml:"unique"`
+	Index      bool     `json:"index"      yaml:"index"`
 	Array      bool     `json:"array"      yaml:"array"`
 	ArraySize  int      `json:"array_size" yaml:"array_size"`
 	Nullable   bool     `json:"nullable"   yaml:"nullable"`
@@ -177,6 +187,7 @@ type fieldAttribute string
 var (
 	fieldAttributeSequence fieldAttribute = "sequence"
 	fieldAttributeUnique   fieldAttribute = "unique"
+	fieldAttributeIndex    fieldAttribute = "index"
 	fieldAttributeUuid     fieldAttribute = "uuid"
 	fieldAttributeJson     fieldAttribute = "json"
 	fieldAttributeGeometry fieldAttribute = "geometry"
@@ -244,7 +255,23 @@ func (g *Gen) Generate(ctx context.Context) error {
 		return err
 	}
 
-	if err = g.generateFiles(ctx, generateRequest); err != nil {
+	files, err := g.generateFiles(ctx, generateRequest)
+	if err != nil {
+		return err
+	}
+
+	tables := make(map[string]schemaTable, len(files))
+
+	for _, file := range files {
+		tables[file.table.Name] = file.table
+	}
+
+	snapshot := &schemaSnapshot{
+		Enums:  enums.Enums,
+		Tables: tables,
+	}
+
+	if err = g.generateMigrations(ctx, snapshot); err != nil {
 		return err
 	}
 
@@ -261,7 +288,8 @@ func (g *Gen) Generate(ctx context.Context) error {
 		return err
 	}
 
-	if err = g.generateApi(ctx, apiSchema, g.config.ApiEnums, g.config.ApiModels); err != nil {
+	spec, err := g.generateApi(ctx, apiSchema, g.config.ApiEnums, g.config.ApiModels)
+	if err != nil {
 		return err
 	}
 
@@ -269,6 +297,18 @@ func (g *Gen) Generate(ctx context.Context) error {
 		return err
 	}
 
+	if g.config.ClientDir != "" {
+		if err = g.generateClient(ctx, apiSchema); err != nil {
+			return err
+		}
+	}
+
+	if g.config.TypeScriptDir != "" {
+		if err = g.generateTypeScript(ctx, enums.Enums, files, apiSchema, spec); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -416,14 +456,16 @@ func (g *Gen) generateEnum(ctx context.Context, name string, values []string) (*
 	return response, nil
 }
 
-func (g *Gen) generateFiles(ctx context.Context, request *GenerateRequest) error {
+func (g *Gen) generateFiles(ctx context.Context, request *GenerateRequest) ([]*fileResponse, error) {
 	sourceDaoDir := path.Join(g.config.Dir, "dao")
 
 	entries, err := os.ReadDir(sourceDaoDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	responses := make([]*fileResponse, 0)
+
 	files := make([]string, 0)
 
 	for _, entry := range entries {
@@ -445,21 +487,23 @@ func (g *Gen) generateFiles(ctx context.Context, request *GenerateRequest) error
 
 		response, err := g.generateFile(ctx, request, file)
 		if err != nil {
-			return fmt.Errorf("failed to generate file '%s': %w", file, err)
+			return nil, fmt.Errorf("failed to generate file '%s': %w", file, err)
 		}
 
+		responses = append(responses, response)
+
 		sqlFilename := path.Join(g.config.MigrationsDir, response.model.daoName.snake+"_gen.sql")
 
 		//nolint:gosec // G306: generated file permissions are intentionally permissive
 		if err = os.WriteFile(sqlFilename, response.sql, os.ModePerm); err != nil {
-			return err
+			return nil, err
 		}
 
 		daoGenFilename := path.Join(g.config.DaoDir, response.model.daoName.snake+"_gen.go")
 
 		//nolint:gosec // G306: generated file permissions are intentionally permissive
 		if err = os.WriteFile(daoGenFilename, response.daoGen, os.ModePerm); err != nil {
-			return err
+			return nil, err
 		}
 
 		daoFilename := path.Join(g.config.DaoDir, response.model.daoName.snake+".go")
@@ -467,12 +511,12 @@ func (g *Gen) generateFiles(ctx context.Context, request *GenerateRequest) error
 		if _, ok := util.FileExists(daoFilename); !ok {
 			//nolint:gosec // G306: generated file permissions are intentionally permissive
 			if err = os.WriteFile(daoFilename, response.dao, os.ModePerm); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
-	return nil
+	return responses, nil
 }
 
 func (g *Gen) generateFile(ctx context.Context, request *GenerateRequest, filename string) (*fileResponse, error) {
@@ -1056,6 +1100,15 @@ func (g *Gen) generateFile(ctx context.Context, request *GenerateRequest, filena
 				resultSqlGen = append(resultSqlGen, '\n', '\n')
 				resultSqlGen = append(resultSqlGen, []byte("CREATE UNIQUE INDEX IF NOT EXISTS "+uniqueIndexNameQuoted+" ON "+tableNameQuoted+" ("+fieldNameQuoted+");")...)
 				resultSqlGen = append(resultSqlGen, '\n', '\n')
+			} else if slices.Contains(field.attributes, fieldAttributeIndex) {
+				indexNameQuoted := strconv.Quote(model.daoName.snake + "_" + field.name.snake + "_idx")
+				tableNameQuoted := strconv.Quote(model.daoName.snake)
+				fieldNameQuoted := strconv.Quote(field.name.snake)
+
+				resultSqlGen = append(resultSqlGen, []byte("DROP INDEX IF EXISTS "+indexNameQuoted+";")...)
+				resultSqlGen = append(resultSqlGen, '\n', '\n')
+				resultSqlGen = append(resultSqlGen, []byte("CREATE INDEX IF NOT EXISTS "+indexNameQuoted+" ON "+tableNameQuoted+" ("+fieldNameQuoted+");")...)
+				resultSqlGen = append(resultSqlGen, '\n', '\n')
 			}
 		}
 	}
@@ -1143,6 +1196,7 @@ func (g *Gen) generateFile(ctx context.Context, request *GenerateRequest, filena
 	response := &fileResponse{
 		sourceFileConfig: sourceConfig,
 		model:            modelRequest,
+		table:            newSchemaTable(model, sourceConfig),
 		sql:              resultSqlGen,
 		daoGen:           resultDaoGen,
 		dao:              resultDao,
@@ -1203,6 +1257,10 @@ func (g *Gen) generateModelField(modelRequest *ModelRequest, field sourceFileFie
 		attributes = append(attributes, fieldAttributeUnique)
 	}
 
+	if field.Index {
+		attributes = append(attributes, fieldAttributeIndex)
+	}
+
 	if field.Nullable {
 		attributes = append(attributes, fieldAttributeNullable)
 	}
@@ -0,0 +1,763 @@
+// nolint
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	schemaSnapshotFilename = "schema_snapshot.yaml"
+
+	// migrationNoTransactionMarker is postgres.MigrationNoTransactionMarker
+	migrationNoTransactionMarker = "-- migrate:no-transaction"
+)
+
+var (
+	errDestructiveChanges = errors.New("destructive schema changes need confirmation")
+	errEnumValuesRemoved  = errors.New("enum values can't be removed or reordered in postgres")
+)
+
+var migrationFilenameRegexp = regexp.MustCompile(`^(\d+)_.+\.sql$`)
+
+type schemaColumn struct {
+	Name       string   `json:"name"       yaml:"name"`
+	Type       string   `json:"type"       yaml:"type"`
+	Sequence   bool     `json:"sequence"   yaml:"sequence,omitempty"`
+	Primary    bool     `json:"primary"    yaml:"primary,omitempty"`
+	Unique     bool     `json:"unique"     yaml:"unique,omitempty"`
+	Nullable   bool     `json:"nullable"   yaml:"nullable,omitempty"`
+	Default    string   `json:"default"    yaml:"default,omitempty"`
+	References []string `json:"references" yaml:"references,omitempty"`
+}
+
+type schemaIndex struct {
+	Name   string `json:"name"   yaml:"name"`
+	Column string `json:"column" yaml:"column"`
+	Unique bool   `json:"unique" yaml:"unique,omitempty"`
+}
+
+type schemaTable struct {
+	Name    string         `json:"name"    yaml:"name"`
+	Columns []schemaColumn `json:"columns" yaml:"columns"`
+	Indexes []schemaIndex  `json:"indexes" yaml:"indexes,omitempty"`
+}
+
+// schemaSnapshot is the database schema described by the models, it is stored next
+// to the migrations so the next run knows what the database already has.
+type schemaSnapshot struct {
+	Enums  map[string][]string    `json:"enums"  yaml:"enums"`
+	Tables map[string]schemaTable `json:"tables" yaml:"tables"`
+}
+
+// schemaChange is one statement of a migration with the statement reverting it.
+// noTransaction changes go to a migration of their own running outside of a transaction.
+type schemaChange struct {
+	description   string
+	up            string
+	down          string
+	destructive   bool
+	noTransaction bool
+}
+
+func newSchemaTable(model *entityModel, sourceConfig sourceFileConfig) schemaTable {
+	table := schemaTable{
+		Name:    model.daoName.snake,
+		Columns: make([]schemaColumn, 0, len(model.fields)),
+		Indexes: make([]schemaIndex, 0),
+	}
+
+	for idx, field := range model.fields {
+		sourceField := sourceConfig.Fields[idx]
+
+		table.Columns = append(table.Columns, schemaColumn{
+			Name:       field.name.snake,
+			Type:       field.sqlField.dataType,
+			Sequence:   sourceField.Seq,
+			Primary:    sourceField.Primary,
+			Unique:     sourceField.Unique,
+			Nullable:   sourceField.Nullable,
+			Default:    strings.TrimSpace(sourceField.Default),
+			References: sourceField.References,
+		})
+
+		if sourceField.Unique || sourceField.Index {
+			table.Indexes = append(table.Indexes, schemaIndex{
+				Name:   model.daoName.snake + "_" + field.name.snake + "_idx",
+				Column: field.name.snake,
+				Unique: sourceField.Unique,
+			})
+		}
+	}
+
+	return table
+}
+
+// generateMigrations compares the models with the stored snapshot and writes the
+// difference as the next numbered up and down migrations. The first run only
+// records the snapshot, the schema at that point is the one of the _gen.sql files.
+// Destructive changes, and changes failing on populated tables, fail the run unless
+// Config.ConfirmDestructive is set. New enum values get a no-transaction migration of
+// their own, numbered before the other changes.
+func (g *Gen) generateMigrations(ctx context.Context, current *schemaSnapshot) error {
+	log := g.log.GetLogger(ctx)
+
+	snapshotFilename := path.Join(g.config.MigrationsDir, schemaSnapshotFilename)
+
+	if _, ok := util.FileExists(snapshotFilename); !ok {
+		log.Infof("Recording schema snapshot '%s'", snapshotFilename)
+
+		return writeSchemaSnapshot(snapshotFilename, current)
+	}
+
+	snapshotBuf, err := os.ReadFile(snapshotFilename)
+	if err != nil {
+		return err
+	}
+
+	var previous schemaSnapshot
+
+	if err = yaml.Unmarshal(snapshotBuf, &previous); err != nil {
+		return fmt.Errorf("failed to parse schema snapshot '%s': %w", snapshotFilename, err)
+	}
+
+	changes, err := diffSchema(&previous, current)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	destructive := make([]string, 0)
+
+	for _, change := range changes {
+		if change.destructive {
+			destructive = append(destructive, change.description)
+		}
+	}
+
+	if len(destructive) > 0 && !g.config.ConfirmDestructive {
+		return fmt.Errorf("%w: %s", errDestructiveChanges, strings.Join(destructive, ", "))
+	}
+
+	version, err := nextMigrationVersion(g.config.MigrationsDir)
+	if err != nil {
+		return err
+	}
+
+	// postgres can't use an enum value in the transaction that added it, so the new
+	// values are committed by a migration of their own before the other changes
+	noTransactionChanges := make([]schemaChange, 0)
+	transactionChanges := make([]schemaChange, 0, len(changes))
+
+	for _, change := range changes {
+		if change.noTransaction {
+			noTransactionChanges = append(noTransactionChanges, change)
+		} else {
+			transactionChanges = append(transactionChanges, change)
+		}
+	}
+
+	if len(noTransactionChanges) > 0 {
+		if err = g.writeMigration(ctx, version, "enum_values", noTransactionChanges); err != nil {
+			return err
+		}
+
+		version++
+	}
+
+	if len(transactionChanges) > 0 {
+		if err = g.writeMigration(ctx, version, "schema", transactionChanges); err != nil {
+			return err
+		}
+	}
+
+	return writeSchemaSnapshot(snapshotFilename, current)
+}
+
+func (g *Gen) writeMigration(ctx context.Context, version int, name string, changes []schemaChange) error {
+	upFilename := path.Join(g.config.MigrationsDir, fmt.Sprintf("%04d_%s.up.sql", version, name))
+	downFilename := path.Join(g.config.MigrationsDir, fmt.Sprintf("%04d_%s.down.sql", version, name))
+
+	g.log.GetLogger(ctx).Infof("Generating migration '%s' with %d changes", upFilename, len(changes))
+
+	//nolint:gosec // G306: generated file permissions are intentionally permissive
+	if err := os.WriteFile(upFilename, renderMigration(changes, false), os.ModePerm); err != nil {
+		return err
+	}
+
+	//nolint:gosec // G306: generated file permissions are intentionally permissive
+	return os.WriteFile(downFilename, renderMigration(changes, true), os.ModePerm)
+}
+
+func writeSchemaSnapshot(filename string, snapshot *schemaSnapshot) error {
+	buf, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	buf = append([]byte("# "+disclaimer+"\n\n"), buf...)
+
+	//nolint:gosec // G306: generated file permissions are intentionally permissive
+	return os.WriteFile(filename, buf, os.ModePerm)
+}
+
+func nextMigrationVersion(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+
+	for _, entry := range entries {
+		matches := migrationFilenameRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		entryVersion, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration version '%s': %w", entry.Name(), err)
+		}
+
+		version = max(version, entryVersion)
+	}
+
+	return version + 1, nil
+}
+
+// renderMigration writes the changes in order, or reverted in reverse order for down.
+// The changes are either all noTransaction or none of them.
+func renderMigration(changes []schemaChange, down bool) []byte {
+	buf := []byte("-- " + disclaimer + "\n")
+
+	if len(changes) > 0 && changes[0].noTransaction {
+		buf = append(buf, []byte(migrationNoTransactionMarker+"\n")...)
+	}
+
+	for idx := range changes {
+		change := changes[idx]
+		statement := change.up
+
+		if down {
+			change = changes[len(changes)-1-idx]
+			statement = change.down
+		}
+
+		buf = append(buf, '\n')
+
+		if change.destructive {
+			buf = append(buf, []byte("-- DESTRUCTIVE: "+change.description+"\n")...)
+		} else {
+			buf = append(buf, []byte("-- "+change.description+"\n")...)
+		}
+
+		buf = append(buf, []byte(statement+"\n")...)
+	}
+
+	return buf
+}
+
+// diffSchema lists the changes turning the previous schema into the current one.
+// Renamed tables and columns are seen as a drop and an add.
+func diffSchema(previous *schemaSnapshot, current *schemaSnapshot) ([]schemaChange, error) {
+	changes := make([]schemaChange, 0)
+
+	enumChanges, err := diffEnums(previous.Enums, current.Enums)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = append(changes, enumChanges...)
+
+	for _, name := range sortedKeys(current.Tables) {
+		table := current.Tables[name]
+
+		previousTable, ok := previous.Tables[name]
+		if !ok {
+			changes = append(changes, schemaChange{
+				description: "create table " + quoteIdent(name),
+				up:          createTableSql(table),
+				down:        dropTableSql(table),
+				destructive: false,
+			})
+
+			continue
+		}
+
+		changes = append(changes, diffTable(previousTable, table)...)
+	}
+
+	for _, name := range sortedKeys(previous.Tables) {
+		if _, ok := current.Tables[name]; ok {
+			continue
+		}
+
+		table := previous.Tables[name]
+
+		changes = append(changes, schemaChange{
+			description: "drop table " + quoteIdent(name),
+			up:          dropTableSql(table),
+			down:        createTableSql(table),
+			destructive: true,
+		})
+	}
+
+	for _, name := range sortedKeys(previous.Enums) {
+		if _, ok := current.Enums[name]; ok {
+			continue
+		}
+
+		changes = append(changes, schemaChange{
+			description: "drop type " + name,
+			up:          fmt.Sprintf("DROP TYPE IF EXISTS %s;", name),
+			down:        createEnumSql(name, previous.Enums[name]),
+			destructive: true,
+		})
+	}
+
+	return changes, nil
+}
+
+func diffEnums(previous map[string][]string, current map[string][]string) ([]schemaChange, error) {
+	changes := make([]schemaChange, 0)
+
+	for _, name := range sortedKeys(current) {
+		values := current[name]
+
+		previousValues, ok := previous[name]
+		if !ok {
+			changes = append(changes, schemaChange{
+				description: "create type " + name,
+				up:          createEnumSql(name, values),
+				down:        fmt.Sprintf("DROP TYPE IF EXISTS %s;", name),
+				destructive: false,
+			})
+
+			continue
+		}
+
+		// existing values must keep their order, new ones are inserted after their predecessor
+		kept := slices.DeleteFunc(slices.Clone(values), func(value string) bool {
+			return !slices.Contains(previousValues, value)
+		})
+
+		if !slices.Equal(kept, previousValues) {
+			return nil, fmt.Errorf("%w: %s", errEnumValuesRemoved, name)
+		}
+
+		for idx, value := range values {
+			if slices.Contains(previousValues, value) {
+				continue
+			}
+
+			up := fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", name, quoteLiteral(value))
+
+			if idx > 0 {
+				up += " AFTER " + quoteLiteral(values[idx-1])
+			}
+
+			changes = append(changes, schemaChange{
+				description:   fmt.Sprintf("add value '%s' to type %s", value, name),
+				up:            up + ";",
+				down:          fmt.Sprintf("-- postgres can't drop the enum value '%s' of type %s", value, name),
+				destructive:   false,
+				noTransaction: true,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+//nolint:cyclop,funlen
+func diffTable(previous schemaTable, current schemaTable) []schemaChange {
+	changes := make([]schemaChange, 0)
+
+	tableName := quoteIdent(current.Name)
+
+	previousColumns := make(map[string]schemaColumn, len(previous.Columns))
+
+	for _, column := range previous.Columns {
+		previousColumns[column.Name] = column
+	}
+
+	currentColumns := make(map[string]schemaColumn, len(current.Columns))
+
+	for _, column := range current.Columns {
+		currentColumns[column.Name] = column
+	}
+
+	for _, column := range current.Columns {
+		columnName := tableName + "." + quoteIdent(column.Name)
+
+		previousColumn, ok := previousColumns[column.Name]
+		if !ok {
+			up := ""
+
+			if column.Sequence {
+				up += "CREATE SEQUENCE IF NOT EXISTS " + sequenceName(current.Name, column.Name) + ";\n"
+			}
+
+			up += fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", tableName, columnDefinition(column))
+
+			description := "add column " + columnName
+			failsOnRows := !column.Nullable && column.Default == ""
+
+			if failsOnRows {
+				description = "add not nullable column " + columnName + " without a default, fails when the table has rows"
+			}
+
+			changes = append(changes, schemaChange{
+				description: description,
+				up:          up,
+				down:        fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", tableName, quoteIdent(column.Name)),
+				destructive: failsOnRows,
+			})
+
+			continue
+		}
+
+		createSequenceSql := "CREATE SEQUENCE IF NOT EXISTS " + sequenceName(current.Name, column.Name) + ";"
+		dropSequenceSql := "DROP SEQUENCE IF EXISTS " + sequenceName(current.Name, column.Name) + ";"
+
+		// the sequence exists before a default using it and is dropped after
+		if column.Sequence && !previousColumn.Sequence {
+			changes = append(changes, schemaChange{
+				description: "create sequence " + sequenceName(current.Name, column.Name),
+				up:          createSequenceSql,
+				down:        dropSequenceSql,
+				destructive: false,
+			})
+		}
+
+		if column.Type != previousColumn.Type {
+			changes = append(changes, schemaChange{
+				description: fmt.Sprintf("change type of column %s from %s to %s", columnName, previousColumn.Type, column.Type),
+				up:          alterColumnTypeSql(tableName, column),
+				down:        alterColumnTypeSql(tableName, previousColumn),
+				destructive: true,
+			})
+		}
+
+		if column.Default != previousColumn.Default {
+			changes = append(changes, schemaChange{
+				description: "change default of column " + columnName,
+				up:          alterColumnDefaultSql(tableName, column),
+				down:        alterColumnDefaultSql(tableName, previousColumn),
+				destructive: false,
+			})
+		}
+		if column.Nullable != previousColumn.Nullable {
+			setNotNull := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tableName, quoteIdent(column.Name))
+			dropNotNull := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", tableName, quoteIdent(column.Name))
+
+			switch {
+			case column.Nullable:
+				changes = append(changes, schemaChange{
+					description: "make column " + columnName + " nullable",
+					up:          dropNotNull,
+					down:        setNotNull,
+					destructive: false,
+				})
+			case column.Default != "":
+				// the default is set above, the rows having NULL get it before the constraint
+				backfill := fmt.Sprintf("UPDATE %s SET %s = DEFAULT WHERE %s IS NULL;", tableName, quoteIdent(column.Name), quoteIdent(column.Name))
+
+				changes = append(changes, schemaChange{
+					description: "make column " + columnName + " not nullable",
+					up:          backfill + "\n" + setNotNull,
+					down:        dropNotNull,
+					destructive: false,
+				})
+			default:
+				changes = append(changes, schemaChange{
+					description: "make column " + columnName + " not nullable without a default, fails when it has NULL values",
+					up:          setNotNull,
+					down:        dropNotNull,
+					destructive: true,
+				})
+			}
+		}
+
+		if column.Primary != previousColumn.Primary {
+			addPrimaryKey := fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", tableName, quoteIdent(column.Name))
+			dropPrimaryKey := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", tableName, quoteIdent(current.Name+"_pkey"))
+
+			if column.Primary {
+				changes = append(changes, schemaChange{
+					description: "add primary key " + columnName,
+					up:          addPrimaryKey,
+					down:        dropPrimaryKey,
+					destructive: false,
+				})
+			} else {
+				changes = append(changes, schemaChange{
+					description: "drop primary key " + columnName,
+					up:          dropPrimaryKey,
+					down:        addPrimaryKey,
+					destructive: true,
+				})
+			}
+		}
+
+		if !slices.Equal(column.References, previousColumn.References) {
+			up := foreignKeySql(current.Name, previousColumn, column)
+			down := foreignKeySql(current.Name, column, previousColumn)
+
+			changes = append(changes, schemaChange{
+				description: "change reference of column " + columnName,
+				up:          up,
+				down:        down,
+				destructive: false,
+			})
+		}
+
+		if !column.Sequence && previousColumn.Sequence {
+			changes = append(changes, schemaChange{
+				description: "drop sequence " + sequenceName(current.Name, column.Name),
+				up:          dropSequenceSql,
+				down:        createSequenceSql,
+				destructive: true,
+			})
+		}
+	}
+
+	for _, column := range previous.Columns {
+		if _, ok := currentColumns[column.Name]; ok {
+			continue
+		}
+
+		changes = append(changes, schemaChange{
+			description: "drop column " + tableName + "." + quoteIdent(column.Name),
+			up:          fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", tableName, quoteIdent(column.Name)),
+			down:        fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", tableName, columnDefinition(column)),
+			destructive: true,
+		})
+	}
+
+	for _, index := range current.Indexes {
+		previousIndex, ok := findIndex(previous.Indexes, index.Name)
+		if ok && previousIndex == index {
+			continue
+		}
+
+		if ok {
+			changes = append(changes, schemaChange{
+				description: "recreate index " + quoteIdent(index.Name),
+				up:          dropIndexSql(current.Name, previousIndex) + "\n" + createIndexSql(current.Name, index),
+				down:        dropIndexSql(current.Name, index) + "\n" + createIndexSql(current.Name, previousIndex),
+				destructive: false,
+			})
+
+			continue
+		}
+
+		changes = append(changes, schemaChange{
+			description: "create index " + quoteIdent(index.Name),
+			up:          createIndexSql(current.Name, index),
+			down:        dropIndexSql(current.Name, index),
+			destructive: false,
+		})
+	}
+
+	for _, index := range previous.Indexes {
+		if _, ok := findIndex(current.Indexes, index.Name); ok {
+			continue
+		}
+
+		changes = append(changes, schemaChange{
+			description: "drop index " + quoteIdent(index.Name),
+			up:          dropIndexSql(current.Name, index),
+			down:        createIndexSql(current.Name, index),
+			destructive: false,
+		})
+	}
+
+	return changes
+}
+
+func findIndex(indexes []schemaIndex, name string) (schemaIndex, bool) {
+	for _, index := range indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+
+	return schemaIndex{}, false
+}
+
+func createEnumSql(name string, values []string) string {
+	quotedValues := make([]string, 0, len(values))
+
+	for _, value := range values {
+		quotedValues = append(quotedValues, quoteLiteral(value))
+	}
+
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", name, strings.Join(quotedValues, ", "))
+}
+
+func createTableSql(table schemaTable) string {
+	lines := make([]string, 0)
+
+	for _, column := range table.Columns {
+		if column.Sequence {
+			lines = append(lines, "CREATE SEQUENCE IF NOT EXISTS "+sequenceName(table.Name, column.Name)+";")
+		}
+	}
+
+	definitions := make([]string, 0, len(table.Columns))
+
+	for _, column := range table.Columns {
+		definitions = append(definitions, "\t"+columnDefinition(column))
+	}
+
+	lines = append(lines, "CREATE TABLE IF NOT EXISTS "+quoteIdent(table.Name)+" (\n"+strings.Join(definitions, ",\n")+"\n);")
+
+	for _, index := range table.Indexes {
+		lines = append(lines, createIndexSql(table.Name, index))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func dropTableSql(table schemaTable) string {
+	lines := []string{"DROP TABLE IF EXISTS " + quoteIdent(table.Name) + ";"}
+
+	for _, column := range table.Columns {
+		if column.Sequence {
+			lines = append(lines, "DROP SEQUENCE IF EXISTS "+sequenceName(table.Name, column.Name)+";")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// columnDefinition renders the column like generateModelField does for CREATE TABLE.
+func columnDefinition(column schemaColumn) string {
+	parts := []string{quoteIdent(column.Name), column.Type}
+
+	if column.Nullable {
+		parts = append(parts, "NULL")
+	} else {
+		parts = append(parts, "NOT", "NULL")
+	}
+
+	if column.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+
+	if column.Primary {
+		parts = append(parts, "PRIMARY", "KEY")
+	}
+
+	if len(column.References) == 2 {
+		parts = append(parts, "REFERENCES", fmt.Sprintf("%s(%s)", quoteIdent(column.References[0]), quoteIdent(column.References[1])))
+	}
+
+	if column.Default != "" {
+		parts = append(parts, "DEFAULT", column.Default)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// foreignKeySql replaces the constraint of the inline REFERENCES of previous, named
+// like postgres names it, with the one of column.
+func foreignKeySql(tableName string, previous schemaColumn, column schemaColumn) string {
+	lines := make([]string, 0, 2)
+	constraintName := quoteIdent(tableName + "_" + column.Name + "_fkey")
+
+	if len(previous.References) == 2 {
+		lines = append(lines, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", quoteIdent(tableName), constraintName))
+	}
+
+	if len(column.References) == 2 {
+		lines = append(lines, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(%s);",
+			quoteIdent(tableName),
+			constraintName,
+			quoteIdent(column.Name),
+			quoteIdent(column.References[0]),
+			quoteIdent(column.References[1]),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func alterColumnTypeSql(tableName string, column schemaColumn) string {
+	columnName := quoteIdent(column.Name)
+
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", tableName, columnName, column.Type, columnName, column.Type)
+}
+
+func alterColumnDefaultSql(tableName string, column schemaColumn) string {
+	if column.Default == "" {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", tableName, quoteIdent(column.Name))
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", tableName, quoteIdent(column.Name), column.Default)
+}
+
+func createIndexSql(tableName string, index schemaIndex) string {
+	unique := ""
+
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s);", unique, quoteIdent(index.Name), quoteIdent(tableName), quoteIdent(index.Column))
+}
+
+// dropIndexSql also drops the constraint of the inline UNIQUE of the column.
+func dropIndexSql(tableName string, index schemaIndex) string {
+	sql := "DROP INDEX IF EXISTS " + quoteIdent(index.Name) + ";"
+
+	if index.Unique {
+		constraintName := quoteIdent(tableName + "_" + index.Column + "_key")
+
+		sql += fmt.Sprintf("\nALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", quoteIdent(tableName), constraintName)
+	}
+
+	return sql
+}
+
+func sequenceName(tableName string, columnName string) string {
+	return quoteIdent(tableName + "_" + columnName + "_seq")
+}
+
+func quoteIdent(name string) string {
+	return strconv.Quote(name)
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
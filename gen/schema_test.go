@@ -0,0 +1,228 @@
+package gen
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSchemaSnapshot() *schemaSnapshot {
+	return &schemaSnapshot{
+		Enums: map[string][]string{
+			"user_status": {"active", "blocked"},
+		},
+		Tables: map[string]schemaTable{
+			"users": {
+				Name: "users",
+				Columns: []schemaColumn{
+					{Name: "id", Type: "BIGINT", Primary: true},
+					{Name: "email", Type: "TEXT", Unique: true},
+					{Name: "age", Type: "INT", Nullable: true},
+				},
+				Indexes: []schemaIndex{
+					{Name: "users_email_idx", Column: "email", Unique: true},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	t.Parallel()
+
+	previous := testSchemaSnapshot()
+
+	current := testSchemaSnapshot()
+	current.Enums["user_status"] = []string{"active", "pending", "blocked"}
+	current.Tables["users"] = schemaTable{
+		Name: "users",
+		Columns: []schemaColumn{
+			{Name: "id", Type: "BIGINT", Primary: true},
+			{Name: "email", Type: "TEXT"},
+			{Name: "age", Type: "BIGINT", Default: "0"},
+			{Name: "status", Type: "USER_STATUS", Default: "'active'"},
+		},
+		Indexes: []schemaIndex{
+			{Name: "users_status_idx", Column: "status"},
+		},
+	}
+
+	changes, err := diffSchema(previous, current)
+	require.NoError(t, err)
+
+	ups := make([]string, 0, len(changes))
+	destructive := make([]string, 0)
+
+	for _, change := range changes {
+		ups = append(ups, change.up)
+
+		if change.destructive {
+			destructive = append(destructive, change.description)
+		}
+	}
+
+	require.Equal(t, []string{
+		`ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'pending' AFTER 'active';`,
+		`ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT USING "age"::BIGINT;`,
+		`ALTER TABLE "users" ALTER COLUMN "age" SET DEFAULT 0;`,
+		"UPDATE \"users\" SET \"age\" = DEFAULT WHERE \"age\" IS NULL;\nALTER TABLE \"users\" ALTER COLUMN \"age\" SET NOT NULL;",
+		`ALTER TABLE "users" ADD COLUMN "status" USER_STATUS NOT NULL DEFAULT 'active';`,
+		`CREATE INDEX IF NOT EXISTS "users_status_idx" ON "users" ("status");`,
+		"DROP INDEX IF EXISTS \"users_email_idx\";\nALTER TABLE \"users\" DROP CONSTRAINT IF EXISTS \"users_email_key\";",
+	}, ups)
+
+	require.Equal(t, []string{`change type of column "users"."age" from INT to BIGINT`}, destructive)
+
+	current.Enums["user_status"] = []string{"active"}
+
+	_, err = diffSchema(previous, current)
+	require.ErrorIs(t, err, errEnumValuesRemoved)
+}
+
+func TestDiffTableConstraints(t *testing.T) {
+	t.Parallel()
+
+	previous := schemaTable{
+		Name: "posts",
+		Columns: []schemaColumn{
+			{Name: "id", Type: "BIGINT", Primary: true},
+			{Name: "author_id", Type: "BIGINT", References: []string{"users", "id"}},
+			{Name: "title", Type: "TEXT", Nullable: true},
+		},
+	}
+
+	current := schemaTable{
+		Name: "posts",
+		Columns: []schemaColumn{
+			{Name: "id", Type: "BIGINT", Sequence: true},
+			{Name: "author_id", Type: "BIGINT", References: []string{"authors", "id"}},
+			{Name: "title", Type: "TEXT"},
+			{Name: "body", Type: "TEXT"},
+		},
+	}
+
+	changes := diffTable(previous, current)
+
+	ups := make([]string, 0, len(changes))
+	destructive := make([]string, 0)
+
+	for _, change := range changes {
+		ups = append(ups, change.up)
+
+		if change.destructive {
+			destructive = append(destructive, change.description)
+		}
+	}
+
+	require.Equal(t, []string{
+		`CREATE SEQUENCE IF NOT EXISTS "posts_id_seq";`,
+		`ALTER TABLE "posts" DROP CONSTRAINT IF EXISTS "posts_pkey";`,
+		"ALTER TABLE \"posts\" DROP CONSTRAINT IF EXISTS \"posts_author_id_fkey\";\n" +
+			`ALTER TABLE "posts" ADD CONSTRAINT "posts_author_id_fkey" FOREIGN KEY ("author_id") REFERENCES "authors"("id");`,
+		`ALTER TABLE "posts" ALTER COLUMN "title" SET NOT NULL;`,
+		`ALTER TABLE "posts" ADD COLUMN "body" TEXT NOT NULL;`,
+	}, ups)
+
+	require.Equal(t, []string{
+		`drop primary key "posts"."id"`,
+		`make column "posts"."title" not nullable without a default, fails when it has NULL values`,
+		`add not nullable column "posts"."body" without a default, fails when the table has rows`,
+	}, destructive)
+
+	reverted := diffTable(current, previous)
+
+	require.Equal(t, `ALTER TABLE "posts" ADD PRIMARY KEY ("id");`, reverted[0].up)
+	require.Equal(t, `DROP SEQUENCE IF EXISTS "posts_id_seq";`, reverted[1].up)
+}
+
+func TestGenerateMigrations(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	generator := NewGen(&Config{MigrationsDir: dir})
+
+	ctx := context.Background()
+
+	require.NoError(t, generator.generateMigrations(ctx, testSchemaSnapshot()))
+	require.FileExists(t, path.Join(dir, schemaSnapshotFilename))
+
+	current := testSchemaSnapshot()
+	current.Tables["sessions"] = schemaTable{
+		Name: "sessions",
+		Columns: []schemaColumn{
+			{Name: "id", Type: "UUID", Primary: true},
+		},
+	}
+
+	require.NoError(t, generator.generateMigrations(ctx, current))
+
+	up, err := os.ReadFile(path.Join(dir, "0001_schema.up.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(up), "CREATE TABLE IF NOT EXISTS \"sessions\" (\n\t\"id\" UUID NOT NULL PRIMARY KEY\n);")
+
+	down, err := os.ReadFile(path.Join(dir, "0001_schema.down.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(down), `DROP TABLE IF EXISTS "sessions";`)
+
+	// unchanged models don't produce migrations
+	require.NoError(t, generator.generateMigrations(ctx, current))
+	require.NoFileExists(t, path.Join(dir, "0002_schema.up.sql"))
+
+	dropped := testSchemaSnapshot()
+
+	require.ErrorIs(t, generator.generateMigrations(ctx, dropped), errDestructiveChanges)
+	require.NoFileExists(t, path.Join(dir, "0002_schema.up.sql"))
+
+	generator.config.ConfirmDestructive = true
+
+	require.NoError(t, generator.generateMigrations(ctx, dropped))
+
+	up, err = os.ReadFile(path.Join(dir, "0002_schema.up.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(up), "-- DESTRUCTIVE: drop table \"sessions\"\nDROP TABLE IF EXISTS \"sessions\";")
+
+	// new enum values are committed by their own migration before the columns using them
+	withValue := testSchemaSnapshot()
+	withValue.Enums["user_status"] = []string{"active", "blocked", "deleted"}
+	withValue.Tables["users"].Columns[2] = schemaColumn{Name: "age", Type: "INT", Nullable: true, Default: "'deleted'"}
+
+	require.NoError(t, generator.generateMigrations(ctx, withValue))
+
+	up, err = os.ReadFile(path.Join(dir, "0003_enum_values.up.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(up), "-- migrate:no-transaction\n")
+	require.Contains(t, string(up), "ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'deleted' AFTER 'blocked';")
+	require.FileExists(t, path.Join(dir, "0003_enum_values.down.sql"))
+
+	up, err = os.ReadFile(path.Join(dir, "0004_schema.up.sql"))
+	require.NoError(t, err)
+	require.NotContains(t, string(up), "no-transaction")
+	require.Contains(t, string(up), `ALTER TABLE "users" ALTER COLUMN "age" SET DEFAULT 'deleted';`)
+}
+
+func TestDiffSchemaEnumQuotes(t *testing.T) {
+	t.Parallel()
+
+	previous := testSchemaSnapshot()
+	previous.Enums["user_status"] = []string{"active", "won't"}
+
+	current := testSchemaSnapshot()
+	current.Enums["user_status"] = []string{"active", "won't", "can't"}
+	current.Enums["user_role"] = []string{"o'brien"}
+
+	changes, err := diffSchema(previous, current)
+	require.NoError(t, err)
+
+	ups := make([]string, 0, len(changes))
+
+	for _, change := range changes {
+		ups = append(ups, change.up)
+	}
+
+	require.Contains(t, ups, `CREATE TYPE user_role AS ENUM ('o''brien');`)
+	require.Contains(t, ups, `ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'can''t' AFTER 'won''t';`)
+}
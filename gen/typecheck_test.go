@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// generatedImporter resolves the packages of the module and its dependencies from the
+// export data `go list` builds, local holds the generated packages checked before.
+type generatedImporter struct {
+	local map[string]*types.Package
+	gc    types.Importer
+}
+
+func newGeneratedImporter(fset *token.FileSet) *generatedImporter {
+	return &generatedImporter{
+		local: make(map[string]*types.Package),
+		gc: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			output, err := exec.Command("go", "list", "-export", "-f", "{{.Export}}", path).Output()
+			if err != nil {
+				return nil, err
+			}
+
+			return os.Open(strings.TrimSpace(string(output)))
+		}),
+	}
+}
+
+func (i *generatedImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.local[path]; ok {
+		return pkg, nil
+	}
+
+	return i.gc.Import(path)
+}
+
+// typeCheckGenerated compiles the generated go files of dir as the package importPath,
+// so unused imports and unknown identifiers fail the test.
+func typeCheckGenerated(t *testing.T, fset *token.FileSet, imp *generatedImporter, importPath string, dir string) {
+	t.Helper()
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, filenames)
+
+	files := make([]*ast.File, 0, len(filenames))
+
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filename, nil, parser.AllErrors)
+		require.NoError(t, err)
+
+		files = append(files, file)
+	}
+
+	config := types.Config{
+		Importer: imp,
+	}
+
+	pkg, err := config.Check(importPath, fset, files, nil)
+	require.NoError(t, err)
+
+	imp.local[importPath] = pkg
+}
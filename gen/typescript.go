@@ -0,0 +1,788 @@
+// nolint
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gobeam/stringy"
+)
+
+var errTypeScriptUnsupportedType = errors.New("type is not supported by typescript")
+
+var typeScriptIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generateTypeScript writes the enums, the dao models and a fetch client of the api
+// for the frontend. Api models mirror the json the server renders (proto names, enums
+// as their names), hidden routes are left out the same as in swagger.yaml.
+func (g *Gen) generateTypeScript(
+	ctx context.Context,
+	enumsMap map[string][]string,
+	files []*fileResponse,
+	apiSchema *ApiSchema,
+	spec *openapi3.T,
+) error {
+	log := g.log.GetLogger(ctx)
+
+	log.Infof("Generating typescript '%s'", g.config.TypeScriptDir)
+
+	if err := os.MkdirAll(g.config.TypeScriptDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	enumsTs := generateTypeScriptEnums(enumsMap)
+
+	modelsTs, err := generateTypeScriptModels(files)
+	if err != nil {
+		return err
+	}
+
+	apiTs, err := generateTypeScriptApi(apiSchema, spec)
+	if err != nil {
+		return err
+	}
+
+	for filename, content := range map[string][]byte{
+		"enums.gen.ts":  enumsTs,
+		"models.gen.ts": modelsTs,
+		"api.gen.ts":    apiTs,
+	} {
+		//nolint:gosec // G306: generated file permissions are intentionally permissive
+		if err = os.WriteFile(path.Join(g.config.TypeScriptDir, filename), content, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func generateTypeScriptEnums(enumsMap map[string][]string) []byte {
+	result := []byte("// " + disclaimer + "\n")
+
+	names := make([]string, 0, len(enumsMap))
+
+	for name := range enumsMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		enumName := makeNamed(strings.ToLower(name)).camelCapitalized
+
+		values := make([]string, 0, len(enumsMap[name]))
+
+		for _, value := range enumsMap[name] {
+			values = append(values, strconv.Quote(value))
+		}
+
+		union := strings.Join(values, " | ")
+		if union == "" {
+			union = "never"
+		}
+
+		result = append(result, '\n')
+		result = append(result, []byte(fmt.Sprintf("export type %s = %s;\n", enumName, union))...)
+		result = append(result, '\n')
+		result = append(result, []byte(fmt.Sprintf("export const %sValues: readonly %s[] = [%s];\n", enumName, enumName, strings.Join(values, ", ")))...)
+	}
+
+	return result
+}
+
+func generateTypeScriptModels(files []*fileResponse) ([]byte, error) {
+	sortedFiles := make([]*fileResponse, len(files))
+	copy(sortedFiles, files)
+
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].model.modelName.camelCapitalized < sortedFiles[j].model.modelName.camelCapitalized
+	})
+
+	var modelsTs []byte
+
+	usedEnums := make(map[string]bool)
+
+	for _, file := range sortedFiles {
+		modelsTs = append(modelsTs, '\n')
+		modelsTs = append(modelsTs, []byte(fmt.Sprintf("export interface %s {\n", file.model.modelName.camelCapitalized))...)
+
+		for _, field := range file.sourceFileConfig.Fields {
+			typeStr, err := typeScriptFieldType(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: '%s' in '%s'", err, field.Name, file.sourceFileConfig.Name)
+			}
+
+			if field.Type == "enum" {
+				usedEnums[makeNamed(strings.ToLower(field.Enum)).camelCapitalized] = true
+			}
+
+			modelsTs = append(modelsTs, []byte(fmt.Sprintf("  %s: %s;\n", typeScriptPropertyName(makeNamed(field.Name).snake), typeStr))...)
+		}
+
+		modelsTs = append(modelsTs, []byte("}\n")...)
+	}
+
+	result := []byte("// " + disclaimer + "\n")
+
+	if len(usedEnums) > 0 {
+		enumNames := make([]string, 0, len(usedEnums))
+
+		for enumName := range usedEnums {
+			enumNames = append(enumNames, enumName)
+		}
+
+		sort.Strings(enumNames)
+
+		result = append(result, '\n')
+		result = append(result, []byte(fmt.Sprintf("import type { %s } from \"./enums.gen\";\n", strings.Join(enumNames, ", ")))...)
+	}
+
+	result = append(result, modelsTs...)
+
+	return result, nil
+}
+
+// typeScriptFieldType mirrors the json of the dao model field, nullable fields are
+// pointers there and so null here.
+func typeScriptFieldType(field sourceFileField) (string, error) {
+	var typeStr string
+
+	switch field.Type {
+	case "int64", "int32", "uint64", "uint32", "duration":
+		typeStr = "number"
+	case "string", "text", "uuid", "date", "time", "timestamp":
+		typeStr = "string"
+	case "bool":
+		typeStr = "boolean"
+	case "json", "geometry":
+		typeStr = "unknown"
+	case "enum":
+		if len(field.Enum) == 0 {
+			return "", errInvalidEnumField
+		}
+
+		typeStr = makeNamed(strings.ToLower(field.Enum)).camelCapitalized
+	default:
+		if !strings.HasPrefix(field.Type, "varchar(") {
+			return "", fmt.Errorf("%w: '%s'", errTypeScriptUnsupportedType, field.Type)
+		}
+
+		typeStr = "string"
+	}
+
+	if field.Nullable {
+		typeStr += " | null"
+	}
+
+	if field.Array {
+		if field.Nullable {
+			typeStr = "(" + typeStr + ")"
+		}
+
+		typeStr += "[]"
+	}
+
+	return typeStr, nil
+}
+
+func generateTypeScriptApi(apiSchema *ApiSchema, spec *openapi3.T) ([]byte, error) {
+	result := []byte("// " + disclaimer + "\n")
+
+	// Models
+
+	schemaNames := make([]string, 0, len(spec.Components.Schemas))
+
+	for schemaName := range spec.Components.Schemas {
+		schemaNames = append(schemaNames, schemaName)
+	}
+
+	sort.Strings(schemaNames)
+
+	for _, schemaName := range schemaNames {
+		schemaRef := spec.Components.Schemas[schemaName]
+		typeName := makeNamed(schemaName).camelCapitalized
+
+		result = append(result, '\n')
+
+		if schemaRef.Value != nil && schemaRef.Value.Type.Is(openapi3.TypeObject) && schemaRef.Value.AdditionalProperties.Schema == nil {
+			result = append(result, []byte(fmt.Sprintf("export interface %s {\n", typeName))...)
+
+			for _, property := range typeScriptSchemaProperties(schemaRef.Value) {
+				result = append(result, []byte("  "+property+";\n")...)
+			}
+
+			result = append(result, []byte("}\n")...)
+		} else {
+			result = append(result, []byte(fmt.Sprintf("export type %s = %s;\n", typeName, typeScriptSchemaType(schemaRef)))...)
+		}
+	}
+
+	// Errors
+
+	errorBodies := make([]string, 0)
+
+	for _, clientError := range sortedErrorModels(apiSchema) {
+		if _, ok := spec.Components.Schemas[typeScriptSchemaName(clientError)]; !ok {
+			continue
+		}
+
+		typeName := typeScriptModelName(clientError)
+
+		if !slices.Contains(errorBodies, typeName) {
+			errorBodies = append(errorBodies, typeName)
+		}
+	}
+
+	errorBody := strings.Join(errorBodies, " | ")
+	if errorBody == "" {
+		errorBody = "unknown"
+	}
+
+	result = append(result, '\n')
+	result = append(result, []byte(fmt.Sprintf("export type ApiErrorBody = %s;\n", errorBody))...)
+
+	result = append(result, []byte(`
+export interface ApiClientConfig {
+  baseUrl: string;
+  token?: string | (() => string | null | undefined | Promise<string | null | undefined>);
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+export class ApiError extends globalThis.Error {
+  readonly status: number;
+  readonly response: Response;
+  readonly body: ApiErrorBody | undefined;
+
+  constructor(response: Response, body: ApiErrorBody | undefined) {
+    super(`+"`${response.status} ${response.statusText}`"+`);
+
+    this.name = "ApiError";
+    this.status = response.status;
+    this.response = response;
+    this.body = body;
+  }
+}
+
+interface ApiRequest {
+  method: string;
+  uri: string;
+  query: Record<string, string | undefined>;
+  headers: Record<string, string>;
+  body?: BodyInit;
+  secured: boolean;
+}
+`)...)
+
+	// Params and methods
+
+	var (
+		paramsTs  []byte
+		methodsTs []byte
+	)
+
+	for _, routeEntry := range apiSchema.Api.GetRoutes() {
+		for _, operationEntry := range routeEntry.Operations {
+			operation := operationEntry.Route
+
+			if operation.Hidden {
+				continue
+			}
+
+			method, err := clientHttpMethod(operationEntry.Operation)
+			if err != nil {
+				return nil, fmt.Errorf("%w: '%s'", err, operation.Id)
+			}
+
+			paramsName := ""
+			paramsOptional := true
+
+			var paramsFields []string
+
+			for _, paramEntry := range operation.GetParameters() {
+				param := paramEntry.Parameter
+
+				typeStr, err := typeScriptParameterType(param)
+				if err != nil {
+					return nil, fmt.Errorf("%w: '%s' in '%s'", err, paramEntry.Name, operation.Id)
+				}
+
+				optional := "?"
+
+				if param.Required && !param.Array {
+					optional = ""
+					paramsOptional = false
+				}
+
+				paramsFields = append(paramsFields, fmt.Sprintf("%s%s: %s", makeNamed(paramEntry.Name).camel, optional, typeStr))
+			}
+
+			if operation.RequestModel != "" {
+				paramsFields = append(paramsFields, "request: "+typeScriptModelName(operation.RequestModel))
+				paramsOptional = false
+			}
+
+			if operation.RawBody {
+				paramsFields = append(paramsFields, "rawBody?: BodyInit")
+			}
+
+			if operation.RawHeaders {
+				paramsFields = append(paramsFields, "rawHeaders?: Record<string, string>")
+			}
+
+			for _, fileEntry := range operation.RequestFiles {
+				paramsFields = append(paramsFields, makeNamed(fileEntry.Name).camel+"?: Blob")
+			}
+
+			if len(paramsFields) > 0 {
+				paramsName = operation.Id + "Params"
+
+				paramsTs = append(paramsTs, '\n')
+				paramsTs = append(paramsTs, []byte(fmt.Sprintf("export interface %s {\n", paramsName))...)
+
+				for _, field := range paramsFields {
+					paramsTs = append(paramsTs, []byte("  "+field+";\n")...)
+				}
+
+				paramsTs = append(paramsTs, []byte("}\n")...)
+			}
+
+			responseModel := ""
+
+			for _, modelName := range operation.ResponseModels {
+				if _, ok := apiSchema.Api.Errors[modelName]; ok {
+					continue
+				}
+
+				responseModel = typeScriptModelName(modelName)
+
+				break
+			}
+
+			methodParams := make([]string, 0, 2)
+
+			if paramsName != "" {
+				if paramsOptional {
+					methodParams = append(methodParams, "params: "+paramsName+" = {}")
+				} else {
+					methodParams = append(methodParams, "params: "+paramsName)
+				}
+			}
+
+			methodParams = append(methodParams, "init?: RequestInit")
+
+			methodResult := "Response"
+
+			if responseModel != "" {
+				methodResult = responseModel
+			}
+
+			methodsTs = append(methodsTs, '\n')
+
+			if operation.Title != "" {
+				methodsTs = append(methodsTs, []byte(fmt.Sprintf("  /** %s */\n", strings.ReplaceAll(operation.Title, "*/", "* /")))...)
+			}
+
+			methodsTs = append(methodsTs, []byte(fmt.Sprintf(
+				"  async %s(%s): Promise<%s> {\n",
+				makeNamed(operation.Id).camel,
+				strings.Join(methodParams, ", "),
+				methodResult,
+			))...)
+
+			// Uri
+
+			uriKeyword := "const"
+
+			for _, paramEntry := range operation.GetParameters() {
+				if paramEntry.Parameter.In == ApiRouteParameterInPath {
+					uriKeyword = "let"
+				}
+			}
+
+			methodsTs = append(methodsTs, []byte(fmt.Sprintf("    %s uri = %s;\n", uriKeyword, strconv.Quote(routeEntry.Url)))...)
+
+			var queryFields []string
+
+			for _, paramEntry := range operation.GetParameters() {
+				param := paramEntry.Parameter
+				fieldName := "params." + makeNamed(paramEntry.Name).camel
+
+				value := typeScriptParameterValue(param, fieldName)
+
+				// Unset parameters are left out of the query and empty in the path.
+				unset := "undefined"
+
+				if param.In == ApiRouteParameterInPath {
+					unset = `""`
+				}
+
+				switch {
+				case param.Array:
+					value = fmt.Sprintf("%s?.length ? %s : %s", fieldName, value, unset)
+				case param.Required:
+				case value == fieldName:
+					if param.In == ApiRouteParameterInPath {
+						value = fieldName + " ?? " + unset
+					}
+				default:
+					value = fmt.Sprintf("%s === undefined ? %s : %s", fieldName, unset, value)
+				}
+
+				switch param.In {
+				case ApiRouteParameterInPath:
+					placeholder := regexp.MustCompile(`\{` + regexp.QuoteMeta(paramEntry.Name) + `(\?|:[^}]*)?\}`).FindString(routeEntry.Url)
+					if placeholder == "" {
+						return nil, fmt.Errorf("%w: path parameter '%s' is not in '%s'", errUnknownParameterIn, paramEntry.Name, routeEntry.Url)
+					}
+
+					methodsTs = append(methodsTs, []byte(fmt.Sprintf("    uri = uri.replace(%s, encodeURIComponent(%s));\n", strconv.Quote(placeholder), value))...)
+				case ApiRouteParameterInQuery:
+					queryFields = append(queryFields, fmt.Sprintf("%s: %s", typeScriptPropertyName(paramEntry.Name), value))
+				default:
+					return nil, fmt.Errorf("%w: '%s' in '%s'", errUnknownParameterIn, paramEntry.Name, param.In)
+				}
+			}
+
+			// Body
+
+			headers := []string{`Accept: "application/json"`}
+			body := ""
+
+			switch {
+			case len(operation.RequestFiles) > 0:
+				methodsTs = append(methodsTs, []byte("\n    const formData = new FormData();\n")...)
+
+				for _, fileEntry := range operation.RequestFiles {
+					fieldName := "params." + makeNamed(fileEntry.Name).camel
+
+					methodsTs = append(methodsTs, []byte(fmt.Sprintf(`
+    if (%s !== undefined) {
+      formData.append(%s, %s);
+    }
+`, fieldName, strconv.Quote(fileEntry.Name), fieldName))...)
+				}
+
+				body = "formData"
+			case operation.RequestModel != "":
+				headers = append(headers, `"Content-Type": "application/json"`)
+				body = "JSON.stringify(params.request)"
+			case operation.RawBody:
+				body = "params.rawBody"
+			}
+
+			if operation.RawHeaders {
+				headers = append(headers, "...params.rawHeaders")
+			}
+
+			query := "{}"
+
+			if len(queryFields) > 0 {
+				query = "{ " + strings.Join(queryFields, ", ") + " }"
+			}
+
+			methodsTs = append(methodsTs, '\n')
+			methodsTs = append(methodsTs, []byte(fmt.Sprintf(
+				"    const response = await this.request({ method: %s, uri, query: %s, headers: { %s }%s, secured: %t }, init);\n",
+				strconv.Quote(method),
+				query,
+				strings.Join(headers, ", "),
+				typeScriptRequestBody(body),
+				len(operation.Security) > 0,
+			))...)
+
+			methodsTs = append(methodsTs, '\n')
+
+			if responseModel != "" {
+				methodsTs = append(methodsTs, []byte(fmt.Sprintf("    return (await response.json()) as %s;\n", responseModel))...)
+			} else {
+				methodsTs = append(methodsTs, []byte("    return response;\n")...)
+			}
+
+			methodsTs = append(methodsTs, []byte("  }\n")...)
+		}
+	}
+
+	result = append(result, paramsTs...)
+
+	result = append(result, []byte(`
+export class ApiClient {
+  private readonly config: ApiClientConfig;
+
+  constructor(config: ApiClientConfig) {
+    this.config = config;
+  }
+
+  private async request(request: ApiRequest, init?: RequestInit): Promise<Response> {
+    const search = new URLSearchParams();
+
+    for (const [key, value] of Object.entries(request.query)) {
+      if (value !== undefined) {
+        search.append(key, value);
+      }
+    }
+
+    const headers = new Headers(this.config.headers);
+
+    new Headers(init?.headers).forEach((value, key) => headers.set(key, value));
+
+    for (const [key, value] of Object.entries(request.headers)) {
+      headers.set(key, value);
+    }
+
+    if (request.secured && this.config.token !== undefined) {
+      const token = typeof this.config.token === "function" ? await this.config.token() : this.config.token;
+
+      if (token) {
+        headers.set("Authorization", `+"`Bearer ${token}`"+`);
+      }
+    }
+
+    const query = search.toString();
+    const url = this.config.baseUrl.replace(/\/+$/, "") + request.uri + (query ? `+"`?${query}`"+` : "");
+
+    const response = await (this.config.fetch ?? fetch)(url, { ...init, method: request.method, headers, body: request.body });
+
+    if (!response.ok) {
+      let body: ApiErrorBody | undefined;
+
+      try {
+        body = (await response.json()) as ApiErrorBody;
+      } catch {
+        body = undefined;
+      }
+
+      throw new ApiError(response, body);
+    }
+
+    return response;
+  }
+`)...)
+
+	result = append(result, methodsTs...)
+	result = append(result, []byte("}\n")...)
+
+	return result, nil
+}
+
+func typeScriptRequestBody(body string) string {
+	if body == "" {
+		return ""
+	}
+
+	return ", body: " + body
+}
+
+// typeScriptSchemaName is the name addSchema registers the model under in swagger.yaml.
+func typeScriptSchemaName(modelName string) string {
+	split := strings.Split(modelName, ".")
+
+	return stringy.New(split[len(split)-1]).SnakeCase().ToLower()
+}
+
+func typeScriptModelName(modelName string) string {
+	return makeNamed(typeScriptSchemaName(modelName)).camelCapitalized
+}
+
+func typeScriptPropertyName(name string) string {
+	if typeScriptIdentifierRegexp.MatchString(name) {
+		return name
+	}
+
+	return strconv.Quote(name)
+}
+
+func typeScriptSchemaProperties(schema *openapi3.Schema) []string {
+	propertyNames := make([]string, 0, len(schema.Properties))
+
+	for propertyName := range schema.Properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+
+	sort.Strings(propertyNames)
+
+	properties := make([]string, 0, len(propertyNames))
+
+	for _, propertyName := range propertyNames {
+		property := schema.Properties[propertyName]
+		typeStr := typeScriptSchemaType(property)
+
+		// Unset messages are rendered as null.
+		if property.Ref != "" {
+			typeStr += " | null"
+		}
+
+		optional := "?"
+
+		if slices.Contains(schema.Required, propertyName) {
+			optional = ""
+		}
+
+		properties = append(properties, fmt.Sprintf("%s%s: %s", typeScriptPropertyName(propertyName), optional, typeStr))
+	}
+
+	return properties
+}
+
+func typeScriptSchemaType(schemaRef *openapi3.SchemaRef) string {
+	if schemaRef == nil {
+		return "unknown"
+	}
+
+	if schemaRef.Ref != "" {
+		return makeNamed(strings.TrimPrefix(schemaRef.Ref, "#/components/schemas/")).camelCapitalized
+	}
+
+	schema := schemaRef.Value
+	if schema == nil {
+		return "unknown"
+	}
+
+	if len(schema.OneOf) > 0 {
+		types := make([]string, 0, len(schema.OneOf))
+
+		for _, oneOf := range schema.OneOf {
+			types = append(types, typeScriptSchemaType(oneOf))
+		}
+
+		return strings.Join(types, " | ")
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		return "string"
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return "boolean"
+	case schema.Type.Is(openapi3.TypeInteger):
+		// Proto enums are rendered by their names.
+		if enumVarNames, ok := schema.Extensions["x-enum-varnames"].([]string); ok {
+			values := make([]string, 0, len(enumVarNames))
+
+			for _, enumVarName := range enumVarNames {
+				values = append(values, strconv.Quote(enumVarName))
+			}
+
+			return strings.Join(values, " | ")
+		}
+
+		return "number"
+	case schema.Type.Is(openapi3.TypeNumber):
+		return "number"
+	case schema.Type.Is(openapi3.TypeArray):
+		itemsType := typeScriptSchemaType(schema.Items)
+
+		if strings.Contains(itemsType, " ") {
+			return "Array<" + itemsType + ">"
+		}
+
+		return itemsType + "[]"
+	case schema.Type.Is(openapi3.TypeObject):
+		if schema.AdditionalProperties.Schema != nil {
+			return "Record<string, " + typeScriptSchemaType(schema.AdditionalProperties.Schema) + ">"
+		}
+
+		properties := typeScriptSchemaProperties(schema)
+		if len(properties) == 0 {
+			return "Record<string, unknown>"
+		}
+
+		return "{ " + strings.Join(properties, "; ") + " }"
+	default:
+		return "unknown"
+	}
+}
+
+// typeScriptParameterType mirrors clientParameterType.
+func typeScriptParameterType(param ApiRouteParameter) (string, error) {
+	var typeStr string
+
+	if param.Model != "" {
+		typeStr = "string"
+	} else if param.Format != "" && !parameterIsString(param) {
+		switch param.Format {
+		case "uint64":
+			typeStr = "number"
+		case "uuid":
+			typeStr = "string"
+		case "unix_time":
+			typeStr = "Date"
+		default:
+			return "", fmt.Errorf("%w: '%s'", errUnknownParameterFormat, param.Format)
+		}
+	} else {
+		switch param.Type {
+		case "string":
+			typeStr = "string"
+
+			if len(param.Enum) > 0 {
+				values := make([]string, 0, len(param.Enum))
+
+				for _, enumValue := range param.Enum {
+					values = append(values, strconv.Quote(enumValue.Value))
+				}
+
+				typeStr = strings.Join(values, " | ")
+			}
+		case "bool":
+			typeStr = "boolean"
+		default:
+			return "", fmt.Errorf("%w: '%s'", errUnknownParameterType, param.Type)
+		}
+	}
+
+	if param.Array {
+		if !parameterIsString(param) {
+			return "", errParameterArrayNotSupported
+		}
+
+		if len(param.Enum) > 0 {
+			typeStr = "Array<" + typeStr + ">"
+		} else {
+			typeStr += "[]"
+		}
+	}
+
+	return typeStr, nil
+}
+
+// typeScriptParameterValue renders the expression formatting the field as the string
+// the request handler parses.
+func typeScriptParameterValue(param ApiRouteParameter, fieldName string) string {
+	switch {
+	case param.Array:
+		return fieldName + ".join(\",\")"
+	case param.Format == "unix_time":
+		return "String(Math.floor(" + fieldName + ".getTime() / 1000))"
+	case param.Model != "" || param.Format == "uuid" || parameterIsString(param):
+		return fieldName
+	default:
+		return "String(" + fieldName + ")"
+	}
+}
+
+func sortedErrorModels(apiSchema *ApiSchema) []string {
+	statuses := make([]string, 0, len(apiSchema.Api.Errors))
+
+	for status := range apiSchema.Api.Errors {
+		statuses = append(statuses, status)
+	}
+
+	sort.Strings(statuses)
+
+	models := make([]string, 0, len(statuses))
+
+	for _, status := range statuses {
+		models = append(models, apiSchema.Api.Errors[status])
+	}
+
+	return models
+}
@@ -0,0 +1,150 @@
+package gen
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func testTypeScriptSpec() *openapi3.T {
+	return &openapi3.T{
+		Components: &openapi3.Components{
+			Schemas: map[string]*openapi3.SchemaRef{
+				"empty": {Value: &openapi3.Schema{
+					Type:       &openapi3.Types{openapi3.TypeObject},
+					Properties: map[string]*openapi3.SchemaRef{},
+				}},
+				"string_value": {Value: &openapi3.Schema{
+					Type:     &openapi3.Types{openapi3.TypeObject},
+					Required: []string{"value", "status"},
+					Properties: map[string]*openapi3.SchemaRef{
+						"value": {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}},
+						"status": {Value: &openapi3.Schema{
+							Type:       &openapi3.Types{openapi3.TypeInteger},
+							Enum:       []any{0, 1},
+							Extensions: map[string]any{"x-enum-varnames": []string{"UNKNOWN", "ACTIVE"}},
+						}},
+						"parent": {Ref: "#/components/schemas/empty"},
+						"tags": {Value: &openapi3.Schema{
+							Type:  &openapi3.Types{openapi3.TypeArray},
+							Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}},
+						}},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	generator := NewGen(&Config{
+		TypeScriptDir: dir,
+	})
+
+	files := []*fileResponse{
+		{
+			sourceFileConfig: sourceFileConfig{
+				Name: "users",
+				Fields: []sourceFileField{
+					{Name: "id", Type: "int64"},
+					{Name: "name", Type: "varchar(64)", Nullable: true},
+					{Name: "status", Type: "enum", Enum: "USER_STATUS"},
+					{Name: "tags", Type: "text", Array: true, Nullable: true},
+					{Name: "created_at", Type: "timestamp"},
+				},
+			},
+			model: &ModelRequest{
+				daoName:   makeNamed("users"),
+				modelName: makeNamed("user"),
+			},
+		},
+	}
+
+	schema := testClientApiSchema()
+	schema.Api.Security = map[string]ApiSecurity{"bearer": {Type: ApiSecurityTypeBearer}}
+	route := schema.Api.Routes["/users/{id}"][ApiRouteOperationGet]
+	route.Security = []string{"bearer"}
+	schema.Api.Routes["/users/{id}"][ApiRouteOperationGet] = route
+
+	require.NoError(t, generator.generateTypeScript(
+		context.Background(),
+		map[string][]string{"USER_STATUS": {"active", "blocked"}},
+		files,
+		schema,
+		testTypeScriptSpec(),
+	))
+
+	read := func(filename string) string {
+		content, err := os.ReadFile(path.Join(dir, filename))
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	enums := read("enums.gen.ts")
+	require.Contains(t, enums, `export type UserStatus = "active" | "blocked";`)
+	require.Contains(t, enums, `export const UserStatusValues: readonly UserStatus[] = ["active", "blocked"];`)
+
+	models := read("models.gen.ts")
+	for _, expected := range []string{
+		`import type { UserStatus } from "./enums.gen";`,
+		"export interface User {",
+		"  id: number;",
+		"  name: string | null;",
+		"  status: UserStatus;",
+		"  tags: (string | null)[];",
+		"  created_at: string;",
+	} {
+		require.Contains(t, models, expected)
+	}
+
+	api := read("api.gen.ts")
+	for _, expected := range []string{
+		"export interface StringValue {",
+		"  parent?: Empty | null;",
+		`  status: "UNKNOWN" | "ACTIVE";`,
+		"  tags?: string[];",
+		"export type ApiErrorBody = StringValue;",
+		"export interface GetUserParams {\n  fields?: string[];\n  full?: boolean;\n  id: string;\n}",
+		"  async getUser(params: GetUserParams, init?: RequestInit): Promise<StringValue> {",
+		`    uri = uri.replace("{id}", encodeURIComponent(params.id));`,
+		`query: { fields: params.fields?.length ? params.fields.join(",") : undefined, full: params.full === undefined ? undefined : String(params.full) }`,
+		"secured: true }, init);",
+		`headers: { Accept: "application/json", "Content-Type": "application/json" }, body: JSON.stringify(params.request), secured: false }`,
+		"  async uploadAvatar(params: UploadAvatarParams = {}, init?: RequestInit): Promise<Response> {",
+		`      formData.append("avatar", params.avatar);`,
+		"headers.set(\"Authorization\", `Bearer ${token}`);",
+	} {
+		require.Contains(t, api, expected)
+	}
+}
+
+func TestTypeScriptParameterType(t *testing.T) {
+	t.Parallel()
+
+	typeStr, err := typeScriptParameterType(ApiRouteParameter{
+		Type:  "string",
+		Array: true,
+		Enum:  []ApiRouteParameterEnumValue{{Name: "a", Value: "a"}, {Name: "b", Value: "b"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `Array<"a" | "b">`, typeStr)
+
+	typeStr, err = typeScriptParameterType(ApiRouteParameter{Type: "string", Array: true})
+	require.NoError(t, err)
+	require.Equal(t, "string[]", typeStr)
+
+	_, err = typeScriptParameterType(ApiRouteParameter{Type: "bool", Array: true})
+	require.ErrorIs(t, err, errParameterArrayNotSupported)
+
+	_, err = typeScriptParameterType(ApiRouteParameter{Type: "string", Format: "uuid", Array: true})
+	require.ErrorIs(t, err, errParameterArrayNotSupported)
+}
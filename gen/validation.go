@@ -0,0 +1,489 @@
+// nolint
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gobeam/stringy"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	errConstraintNotApplicable  = errors.New("constraint is not applicable")
+	errUnknownConstraintFormat  = errors.New("unknown format for constraint")
+	errInvalidConstraintPattern = errors.New("invalid constraint pattern")
+)
+
+var validationFormats = map[string]string{
+	"email": "http.ValidationFormatEmail",
+	"uuid":  "http.ValidationFormatUuid",
+	"uri":   "http.ValidationFormatUri",
+}
+
+// applySchemaConstraints documents the constraints in swagger.yaml, the value
+// constraints of arrays go to their items. Refs are left as they are.
+func applySchemaConstraints(schemaRef *openapi3.SchemaRef, constraints ApiConstraints, format string, enum []any) {
+	schema := schemaRef.Value
+	if schema == nil {
+		return
+	}
+
+	value := schema
+
+	if schema.Type.Is(openapi3.TypeArray) {
+		if constraints.MinLength != nil {
+			schema.MinItems = uint64(*constraints.MinLength)
+		}
+
+		if constraints.MaxLength != nil {
+			schema.MaxItems = new(uint64(*constraints.MaxLength))
+		}
+
+		value = schema.Items.Value
+		if value == nil {
+			return
+		}
+	} else {
+		if constraints.MinLength != nil {
+			value.MinLength = uint64(*constraints.MinLength)
+		}
+
+		if constraints.MaxLength != nil {
+			value.MaxLength = new(uint64(*constraints.MaxLength))
+		}
+	}
+
+	if constraints.Min != nil {
+		value.Min = constraints.Min
+	}
+
+	if constraints.Max != nil {
+		value.Max = constraints.Max
+	}
+
+	if constraints.Pattern != "" {
+		value.Pattern = constraints.Pattern
+	}
+
+	if format != "" {
+		value.Format = format
+	}
+
+	if len(enum) > 0 {
+		value.Enum = enum
+	}
+}
+
+// validationGen renders the validator calls of the request handlers, patterns are
+// compiled once into package variables.
+type validationGen struct {
+	patterns     map[string]string
+	patternsDecl []byte
+	funcs        map[string]string
+	funcsDecl    []byte
+}
+
+func newValidationGen() *validationGen {
+	return &validationGen{
+		patterns:     make(map[string]string),
+		patternsDecl: nil,
+		funcs:        make(map[string]string),
+		funcsDecl:    nil,
+	}
+}
+
+func (v *validationGen) pattern(pattern string) (string, error) {
+	if name, ok := v.patterns[pattern]; ok {
+		return name, nil
+	}
+
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("%w: %w", errInvalidConstraintPattern, err)
+	}
+
+	name := "validationPattern" + strconv.Itoa(len(v.patterns)+1)
+
+	v.patterns[pattern] = name
+	v.patternsDecl = append(v.patternsDecl, []byte(fmt.Sprintf("var %s = regexp.MustCompile(%s)\n", name, strconv.Quote(pattern)))...)
+
+	return name, nil
+}
+
+func (v *validationGen) lengthChecks(indent string, field string, length string, constraints ApiConstraints) []byte {
+	var result []byte
+
+	if constraints.MinLength != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.MinItems(%s, %s, %d)\n", indent, strconv.Quote(field), length, *constraints.MinLength))...)
+	}
+
+	if constraints.MaxLength != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.MaxItems(%s, %s, %d)\n", indent, strconv.Quote(field), length, *constraints.MaxLength))...)
+	}
+
+	return result
+}
+
+func (v *validationGen) stringChecks(
+	indent string,
+	field string,
+	value string,
+	constraints ApiConstraints,
+	format string,
+	enum []string,
+) ([]byte, error) {
+	if constraints.Min != nil || constraints.Max != nil {
+		return nil, fmt.Errorf("%w: min and max to string '%s'", errConstraintNotApplicable, field)
+	}
+
+	quotedField := strconv.Quote(field)
+
+	var result []byte
+
+	if constraints.MinLength != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.MinLength(%s, %s, %d)\n", indent, quotedField, value, *constraints.MinLength))...)
+	}
+
+	if constraints.MaxLength != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.MaxLength(%s, %s, %d)\n", indent, quotedField, value, *constraints.MaxLength))...)
+	}
+
+	if constraints.Pattern != "" {
+		patternName, err := v.pattern(constraints.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: '%s'", err, field)
+		}
+
+		result = append(result, []byte(fmt.Sprintf("%svalidator.Pattern(%s, %s, %s)\n", indent, quotedField, value, patternName))...)
+	}
+
+	if len(enum) > 0 {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.Enum(%s, %s, %s)\n", indent, quotedField, value, quoteStrings(enum)))...)
+	}
+
+	if format != "" {
+		formatConst, ok := validationFormats[format]
+		if !ok {
+			return nil, fmt.Errorf("%w: '%s' for '%s'", errUnknownConstraintFormat, format, field)
+		}
+
+		result = append(result, []byte(fmt.Sprintf("%svalidator.Format(%s, %s, %s)\n", indent, quotedField, value, formatConst))...)
+	}
+
+	return result, nil
+}
+
+func (v *validationGen) numberChecks(indent string, field string, value string, constraints ApiConstraints) ([]byte, error) {
+	if constraints.MinLength != nil || constraints.MaxLength != nil || constraints.Pattern != "" {
+		return nil, fmt.Errorf("%w: string constraints to number '%s'", errConstraintNotApplicable, field)
+	}
+
+	var result []byte
+
+	if constraints.Min != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.Min(%s, float64(%s), %s)\n", indent, strconv.Quote(field), value, formatFloat(*constraints.Min)))...)
+	}
+
+	if constraints.Max != nil {
+		result = append(result, []byte(fmt.Sprintf("%svalidator.Max(%s, float64(%s), %s)\n", indent, strconv.Quote(field), value, formatFloat(*constraints.Max)))...)
+	}
+
+	return result, nil
+}
+
+// parameterIsString tells whether the parameter is passed to the controller as a string.
+func parameterIsString(param ApiRouteParameter) bool {
+	return param.Model == "" && param.Type == "string" && (param.Format == "" || param.Format == "email" || param.Format == "uri")
+}
+
+// parameterChecks renders the checks of a parsed parameter value, array elements are
+// checked one by one after their count.
+func (v *validationGen) parameterChecks(indent string, name string, param ApiRouteParameter, value string) ([]byte, error) {
+	constraints := param.ApiConstraints
+
+	switch {
+	case parameterIsString(param):
+		enum := make([]string, 0, len(param.Enum))
+
+		for _, enumValue := range param.Enum {
+			enum = append(enum, enumValue.Value)
+		}
+
+		if !param.Array {
+			return v.stringChecks(indent, name, value, constraints, param.Format, enum)
+		}
+
+		result := v.lengthChecks(indent, name, "len("+value+")", constraints)
+
+		itemConstraints := constraints
+		itemConstraints.MinLength = nil
+		itemConstraints.MaxLength = nil
+
+		itemChecks, err := v.stringChecks(indent+"  ", name, "item", itemConstraints, param.Format, enum)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(itemChecks) > 0 {
+			if len(result) > 0 {
+				result = append(result, '\n')
+			}
+
+			result = append(result, []byte(fmt.Sprintf("%sfor _, item := range %s {\n", indent, value))...)
+			result = append(result, itemChecks...)
+			result = append(result, []byte(indent+"}\n")...)
+		}
+
+		return result, nil
+	case param.Model == "" && param.Format == "uint64":
+		return v.numberChecks(indent, name, value, constraints)
+	case constraints != ApiConstraints{}:
+		return nil, fmt.Errorf("%w: to parameter '%s'", errConstraintNotApplicable, name)
+	default:
+		return nil, nil
+	}
+}
+
+func validationFuncName(modelName string) string {
+	return "validate" + stringy.New(nonAlphanumericRegexp.ReplaceAllString(modelName, "_")).PascalCase().Get()
+}
+
+// modelValidator renders the function checking the constrained fields of a request
+// model once and returns its name, unset fields are only checked for being required.
+func (v *validationGen) modelValidator(modelName string, apiModel ApiModel, constraints map[string]ApiFieldConstraints) (string, error) {
+	if funcName, ok := v.funcs[modelName]; ok {
+		return funcName, nil
+	}
+
+	descriptor := apiModel.Reflect.ProtoReflect().Descriptor()
+
+	fieldNames := make([]string, 0, len(constraints))
+
+	for fieldName := range constraints {
+		fieldNames = append(fieldNames, fieldName)
+	}
+
+	sort.Strings(fieldNames)
+
+	var body []byte
+
+	for _, fieldName := range fieldNames {
+		fieldConstraints := constraints[fieldName]
+
+		fieldDesc := descriptor.Fields().ByName(protoreflect.Name(fieldName))
+		if fieldDesc == nil {
+			return "", fmt.Errorf("%w: '%s' in model '%s'", errFieldNotFound, fieldName, modelName)
+		}
+
+		goName, ok := protoGoFieldName(apiModel.Reflect, fieldName)
+		if !ok {
+			return "", fmt.Errorf("%w: oneof field '%s' in model '%s'", errConstraintNotApplicable, fieldName, modelName)
+		}
+
+		fieldChecks, err := v.fieldChecks(fieldName, fieldDesc, goName, fieldConstraints)
+		if err != nil {
+			return "", fmt.Errorf("%w in model '%s'", err, modelName)
+		}
+
+		if len(fieldChecks) > 0 {
+			if len(body) > 0 {
+				body = append(body, '\n')
+			}
+
+			body = append(body, fieldChecks...)
+		}
+	}
+
+	funcName := validationFuncName(modelName)
+
+	v.funcs[modelName] = funcName
+
+	v.funcsDecl = append(v.funcsDecl, []byte(fmt.Sprintf("\nfunc %s(validator *http.Validator, request *%s) {\n", funcName, modelName))...)
+	v.funcsDecl = append(v.funcsDecl, body...)
+	v.funcsDecl = append(v.funcsDecl, []byte("}\n")...)
+
+	return funcName, nil
+}
+
+func (v *validationGen) fieldChecks(
+	fieldName string,
+	fieldDesc protoreflect.FieldDescriptor,
+	goName string,
+	constraints ApiFieldConstraints,
+) ([]byte, error) {
+	getter := "request.Get" + goName + "()"
+	kind := fieldDesc.Kind()
+
+	var presence string
+
+	switch {
+	case fieldDesc.IsList() || fieldDesc.IsMap() || kind == protoreflect.BytesKind:
+		presence = "len(" + getter + ") > 0"
+	case fieldDesc.HasPresence():
+		presence = "request." + goName + " != nil"
+	case kind == protoreflect.StringKind:
+		presence = getter + ` != ""`
+	case kind == protoreflect.BoolKind:
+		if constraints.Required {
+			return nil, fmt.Errorf("%w: required to bool '%s' without presence", errConstraintNotApplicable, fieldName)
+		}
+
+		presence = getter
+	default:
+		presence = getter + " != 0"
+	}
+
+	indent := "    "
+
+	var (
+		checks []byte
+		err    error
+	)
+
+	valueConstraints := constraints.ApiConstraints
+	valueConstraints.MinLength = nil
+	valueConstraints.MaxLength = nil
+
+	hasValueConstraints := valueConstraints != ApiConstraints{} || constraints.Format != "" || len(constraints.Enum) > 0
+
+	valueChecks := func(indent string, value string) ([]byte, error) {
+		switch kind {
+		case protoreflect.StringKind:
+			if fieldDesc.IsList() {
+				return v.stringChecks(indent, fieldName, value, valueConstraints, constraints.Format, constraints.Enum)
+			}
+
+			return v.stringChecks(indent, fieldName, value, constraints.ApiConstraints, constraints.Format, constraints.Enum)
+		case protoreflect.EnumKind:
+			if valueConstraints != (ApiConstraints{}) || constraints.Format != "" {
+				return nil, fmt.Errorf("%w: to enum '%s'", errConstraintNotApplicable, fieldName)
+			}
+
+			if len(constraints.Enum) == 0 {
+				return nil, nil
+			}
+
+			return []byte(fmt.Sprintf("%svalidator.Enum(%s, %s.String(), %s)\n", indent, strconv.Quote(fieldName), value, quoteStrings(constraints.Enum))), nil
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+			protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+			protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+			protoreflect.FloatKind, protoreflect.DoubleKind:
+			if constraints.Format != "" || len(constraints.Enum) > 0 {
+				return nil, fmt.Errorf("%w: format and enum to number '%s'", errConstraintNotApplicable, fieldName)
+			}
+
+			if fieldDesc.IsList() {
+				return v.numberChecks(indent, fieldName, value, valueConstraints)
+			}
+
+			return v.numberChecks(indent, fieldName, value, constraints.ApiConstraints)
+		default:
+			if hasValueConstraints || (!fieldDesc.IsList() && (constraints.MinLength != nil || constraints.MaxLength != nil)) {
+				return nil, fmt.Errorf("%w: to '%s' of kind %s", errConstraintNotApplicable, fieldName, kind)
+			}
+
+			return nil, nil
+		}
+	}
+
+	switch {
+	case fieldDesc.IsMap():
+		if hasValueConstraints {
+			return nil, fmt.Errorf("%w: value constraints to map '%s'", errConstraintNotApplicable, fieldName)
+		}
+
+		checks = v.lengthChecks(indent, fieldName, "len("+getter+")", constraints.ApiConstraints)
+	case fieldDesc.IsList():
+		checks = v.lengthChecks(indent, fieldName, "len("+getter+")", constraints.ApiConstraints)
+
+		if hasValueConstraints {
+			elementChecks, err := valueChecks(indent+"  ", "item")
+			if err != nil {
+				return nil, err
+			}
+
+			if len(checks) > 0 {
+				checks = append(checks, '\n')
+			}
+
+			checks = append(checks, []byte(fmt.Sprintf("%sfor _, item := range %s {\n", indent, getter))...)
+			checks = append(checks, elementChecks...)
+			checks = append(checks, []byte(indent+"}\n")...)
+		}
+	case kind == protoreflect.BytesKind:
+		if hasValueConstraints {
+			return nil, fmt.Errorf("%w: value constraints to bytes '%s'", errConstraintNotApplicable, fieldName)
+		}
+
+		checks = v.lengthChecks(indent, fieldName, "len("+getter+")", constraints.ApiConstraints)
+	default:
+		checks, err = valueChecks(indent, getter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []byte
+
+	switch {
+	case constraints.Required && len(checks) > 0:
+		result = append(result, []byte(fmt.Sprintf("  if validator.Required(%s, %s) {\n", strconv.Quote(fieldName), presence))...)
+		result = append(result, checks...)
+		result = append(result, []byte("  }\n")...)
+	case constraints.Required:
+		result = append(result, []byte(fmt.Sprintf("  validator.Required(%s, %s)\n", strconv.Quote(fieldName), presence))...)
+	case len(checks) > 0:
+		result = append(result, []byte(fmt.Sprintf("  if %s {\n", presence))...)
+		result = append(result, checks...)
+		result = append(result, []byte("  }\n")...)
+	}
+
+	return result, nil
+}
+
+// protoGoFieldName finds the field of the generated struct by its proto name, oneof
+// fields live in wrappers and are not found.
+func protoGoFieldName(message any, fieldName string) (string, bool) {
+	refType := reflect.TypeOf(message)
+
+	if refType.Kind() == reflect.Ptr {
+		refType = refType.Elem()
+	}
+
+	if refType.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	for i := range refType.NumField() {
+		field := refType.Field(i)
+
+		for _, part := range strings.Split(field.Tag.Get("protobuf"), ",") {
+			if part == "name="+fieldName {
+				return field.Name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func quoteStrings(values []string) string {
+	quoted := make([]string, 0, len(values))
+
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,194 @@
+package gen
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func testValidationApiSchema() *ApiSchema {
+	return &ApiSchema{
+		Api: Api{
+			ControllerImports: []ApiImport{
+				{"", "google.golang.org/protobuf/types/known/wrapperspb"},
+			},
+			RouterImports: []ApiImport{
+				{"", "fmt"},
+				{"", "strings"},
+				{"", "github.com/pixality-inc/golang-core/http"},
+				{"", "example.com/api/controllers"},
+				{"", "google.golang.org/protobuf/types/known/wrapperspb"},
+			},
+			Routes: map[string]map[ApiRouteOperation]ApiRoute{
+				"/users/{id}": {
+					ApiRouteOperationPut: {
+						Id: "UpdateUser",
+						Parameters: map[string]ApiRouteParameter{
+							"id": {In: ApiRouteParameterInPath, Type: "string", Format: "uuid", Required: true},
+							"limit": {
+								ApiConstraints: ApiConstraints{Min: new(1.0), Max: new(100.0)},
+								In:             ApiRouteParameterInQuery,
+								Type:           "integer",
+								Format:         "uint64",
+							},
+							"tags": {
+								ApiConstraints: ApiConstraints{MaxLength: new(3), Pattern: "^[a-z]+$"},
+								In:             ApiRouteParameterInQuery,
+								Type:           "string",
+								Array:          true,
+							},
+							"email": {
+								In:       ApiRouteParameterInQuery,
+								Type:     "string",
+								Format:   "email",
+								Required: true,
+							},
+						},
+						RequestModel:   "wrapperspb.StringValue",
+						ResponseModels: []string{"wrapperspb.StringValue"},
+					},
+				},
+			},
+			Constraints: map[string]map[string]ApiFieldConstraints{
+				"wrapperspb.StringValue": {
+					"value": {
+						ApiConstraints: ApiConstraints{MinLength: new(2), Pattern: "^[a-z]+$"},
+						Required:       true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApplySchemaConstraints(t *testing.T) {
+	t.Parallel()
+
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:  &openapi3.Types{openapi3.TypeArray},
+		Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}},
+	}}
+
+	applySchemaConstraints(schema, ApiConstraints{MinLength: new(1), MaxLength: new(3), Pattern: "^[a-z]+$"}, "email", []any{"a"})
+
+	require.Equal(t, uint64(1), schema.Value.MinItems)
+	require.Equal(t, uint64(3), *schema.Value.MaxItems)
+	require.Equal(t, uint64(0), schema.Value.Items.Value.MinLength)
+	require.Equal(t, "^[a-z]+$", schema.Value.Items.Value.Pattern)
+	require.Equal(t, "email", schema.Value.Items.Value.Format)
+	require.Equal(t, []any{"a"}, schema.Value.Items.Value.Enum)
+
+	schema = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}}}
+
+	applySchemaConstraints(schema, ApiConstraints{Min: new(1.0), Max: new(100.0)}, "", nil)
+
+	require.Equal(t, 1.0, *schema.Value.Min)
+	require.Equal(t, 100.0, *schema.Value.Max)
+}
+
+func TestGenerateWebValidation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(path.Join(dir, "controllers"), os.ModePerm))
+
+	generator := NewGen(&Config{
+		ApiDir:         dir,
+		ApiPackageName: "api",
+	})
+
+	apiModels := ApiModels{
+		"wrapperspb.StringValue": NewApiModel(&wrapperspb.StringValue{}, &wrapperspb.StringValue{}),
+	}
+
+	require.NoError(t, generator.generateWeb(context.Background(), testValidationApiSchema(), nil, apiModels))
+
+	routerFilename := path.Join(dir, "request_handler_gen.go")
+
+	typeCheckWeb(t, dir)
+
+	content, err := os.ReadFile(routerFilename)
+	require.NoError(t, err)
+
+	require.NotContains(t, string(content), `"fmt"`)
+
+	for _, expected := range []string{
+		`"regexp"`,
+		"  validator := http.NewValidator()",
+		`    if validator.Required("email", param != "") {`,
+		`      validator.Format("email", param, http.ValidationFormatEmail)`,
+		`        validator.Malformed("id", err)`,
+		`        validator.Min("limit", float64(paramValue), 1)`,
+		`        validator.Max("limit", float64(paramValue), 100)`,
+		`      validator.MaxItems("tags", len(paramValue), 3)`,
+		"      for _, item := range paramValue {\n        validator.Pattern(\"tags\", item, validationPattern1)\n      }",
+		`      validator.Malformed("body", err)`,
+		"      validateWrapperspbStringValue(validator, &request)",
+		"  if err := validator.Err(); err != nil {\n    http.HandleError(ctx, err)\n\n    return\n  }\n\n  response, err := controller.UpdateUser(ctx, params)",
+		"var validationPattern1 = regexp.MustCompile(\"^[a-z]+$\")\n",
+		"func validateWrapperspbStringValue(validator *http.Validator, request *wrapperspb.StringValue) {\n" +
+			"  if validator.Required(\"value\", request.GetValue() != \"\") {\n" +
+			"    validator.MinLength(\"value\", request.GetValue(), 2)\n" +
+			"    validator.Pattern(\"value\", request.GetValue(), validationPattern1)\n" +
+			"  }\n}",
+	} {
+		require.Contains(t, string(content), expected)
+	}
+
+	schema := testValidationApiSchema()
+	schema.Api.ControllerImports = append(schema.Api.ControllerImports, []string{"", "google.golang.org/protobuf/proto"})
+	schema.Api.RouterImports = append(
+		schema.Api.RouterImports,
+		[]string{"", "github.com/pixality-inc/golang-core/logger"},
+		[]string{"", "google.golang.org/protobuf/proto"},
+	)
+	schema.Api.Routes["/avatars"] = map[ApiRouteOperation]ApiRoute{
+		ApiRouteOperationPost: {
+			Id:           "UploadAvatar",
+			RequestFiles: []ApiRouteRequestFile{{Name: "avatar"}},
+			IsHttp:       true,
+		},
+	}
+
+	require.NoError(t, generator.generateWeb(context.Background(), schema, nil, apiModels))
+
+	typeCheckWeb(t, dir)
+
+	content, err = os.ReadFile(routerFilename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"fmt"`)
+
+	schema = testValidationApiSchema()
+	route := schema.Api.Routes["/users/{id}"][ApiRouteOperationPut]
+	route.Parameters["id"] = ApiRouteParameter{
+		ApiConstraints: ApiConstraints{MaxLength: new(3)},
+		In:             ApiRouteParameterInPath,
+		Type:           "string",
+		Format:         "uuid",
+		Required:       true,
+	}
+
+	require.ErrorIs(t, generator.generateWeb(context.Background(), schema, nil, apiModels), errConstraintNotApplicable)
+
+	schema = testValidationApiSchema()
+	schema.Api.Constraints["wrapperspb.StringValue"]["value"] = ApiFieldConstraints{Format: "ipv4"}
+
+	require.ErrorIs(t, generator.generateWeb(context.Background(), schema, nil, apiModels), errUnknownConstraintFormat)
+}
+
+func typeCheckWeb(t *testing.T, dir string) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	imp := newGeneratedImporter(fset)
+
+	typeCheckGenerated(t, fset, imp, "example.com/api/controllers", path.Join(dir, "controllers"))
+	typeCheckGenerated(t, fset, imp, "example.com/api", dir)
+}
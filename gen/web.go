@@ -187,7 +187,7 @@ func (g *Gen) generateWeb(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 
 				if param.Model != "" {
 					typeStr = param.Model
-				} else if param.Format != "" {
+				} else if param.Format != "" && !parameterIsString(param) {
 					switch param.Format {
 					case "uint64":
 						typeStr = "uint64"
@@ -264,15 +264,9 @@ func (g *Gen) generateWeb(ctx context.Context, apiSchema *ApiSchema, apiEnums Ap
 	// Router
 
 	{
-		var routerImports [][]string
-
-		routerImports = append(routerImports, []string{"", "slices"})
-		routerImports = append(routerImports, []string{"", "context"})
-		routerImports = append(routerImports, []string{"", "github.com/valyala/fasthttp"})
+		var routerGen []byte
 
-		routerImports = append(routerImports, apiSchema.Api.RouterImports...)
-
-		routerGen := generateFile(g.config.ApiPackageName, routerImports)
+		validation := newValidationGen()
 
 		routerGen = append(routerGen, '\n', '\n')
 
@@ -372,6 +366,12 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 					}
 				}
 
+				if len(method.Operation.Parameters) > 0 || method.Operation.RequestModel != "" {
+					routerGen = append(routerGen, '\n')
+
+					routerGen = append(routerGen, []byte("  validator := http.NewValidator()\n")...)
+				}
+
 				if len(method.Operation.Parameters) > 0 {
 					routerGen = append(routerGen, '\n')
 
@@ -390,20 +390,8 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 
 						switch param.In {
 						case ApiRouteParameterInPath:
-							if param.Required {
-								routerGen = append(routerGen, []byte(fmt.Sprintf(`    param, ok := ctx.UserValue(%s).(string)
+							routerGen = append(routerGen, []byte(fmt.Sprintf(`    param, _ := ctx.UserValue(%s).(string)
 `, paramNameStr))...)
-
-								routerGen = append(routerGen, []byte(fmt.Sprintf(`    if !ok {
-      http.HandleError(ctx, fmt.Errorf("%%w: no required parameter '%s' found", http.ErrBadRequest))
-
-      return
-    }
-`, paramName))...)
-							} else {
-								routerGen = append(routerGen, []byte(fmt.Sprintf(`    param, _ := ctx.UserValue(%s).(string)
-`, paramNameStr))...)
-							}
 						case ApiRouteParameterInQuery:
 							routerGen = append(routerGen, []byte(fmt.Sprintf(`    param := string(ctx.FormValue(%s))
 `, paramNameStr))...)
@@ -418,39 +406,41 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 `)...)
 
 						if param.Required {
-							routerGen = append(routerGen, []byte(fmt.Sprintf(`    if param == "" {
-      http.HandleError(ctx, fmt.Errorf("%%w: no required parameter '%s' found", http.ErrBadRequest))
-
-      return
-    }
-
-`, paramName))...)
-						}
-
-						routerGen = append(routerGen, []byte(`    if param != "" {
+							routerGen = append(routerGen, []byte(fmt.Sprintf(`    if validator.Required(%s, param != "") {
+`, paramNameStr))...)
+						} else {
+							routerGen = append(routerGen, []byte(`    if param != "" {
 `)...)
+						}
 
 						checkErrAndSetValue := []byte(fmt.Sprintf(`      if err != nil {
-        http.HandleError(ctx, fmt.Errorf("%%w: parameter '%s' is malformed: %%w", http.ErrBadRequest, err))
+        validator.Malformed(%s, err)
+      } else {
+`, paramNameStr))
 
-        return
-      }
+						if !parameterIsString(param) {
+							valueChecks, err := validation.parameterChecks("        ", paramName, param, "paramValue")
+							if err != nil {
+								return fmt.Errorf("%w in '%s'", err, method.Operation.Id)
+							}
 
-`, paramName))
+							if len(valueChecks) > 0 {
+								checkErrAndSetValue = append(checkErrAndSetValue, valueChecks...)
+								checkErrAndSetValue = append(checkErrAndSetValue, '\n')
+							}
+						}
 
 						if param.Required {
-							checkErrAndSetValue = append(checkErrAndSetValue, []byte(fmt.Sprintf(`      params.%s = paramValue
+							checkErrAndSetValue = append(checkErrAndSetValue, []byte(fmt.Sprintf(`        params.%s = paramValue
+      }
 `, modelParamName))...)
 						} else {
-							checkErrAndSetValue = append(checkErrAndSetValue, []byte(fmt.Sprintf(`      if param == "" {
-        params.%s = nil
-      } else {
-        params.%s = &paramValue
+							checkErrAndSetValue = append(checkErrAndSetValue, []byte(fmt.Sprintf(`        params.%s = &paramValue
       }
-`, modelParamName, modelParamName))...)
+`, modelParamName))...)
 						}
 
-						if param.Array && (param.Model != "" || param.Format != "") {
+						if param.Array && !parameterIsString(param) {
 							// @todo support other arrays
 							return fmt.Errorf("%w: '%s' in '%s'", errParameterArrayNotSupported, paramName, method.Operation.Id)
 						}
@@ -463,7 +453,7 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 							routerGen = append(routerGen, []byte(fmt.Sprintf(`      paramValue, err := %s(param)
 `, param.ModelGetter))...)
 							routerGen = append(routerGen, checkErrAndSetValue...)
-						} else if param.Format != "" {
+						} else if param.Format != "" && !parameterIsString(param) {
 							switch param.Format {
 							case "uuid":
 								routerGen = append(routerGen, []byte(`      paramValue, err := http.ParseUUID(param)
@@ -498,19 +488,43 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 
         paramValue = append(paramValue, part)
       }
+
 `)...)
+
+									stringChecks, err := validation.parameterChecks("      ", paramName, param, "paramValue")
+									if err != nil {
+										return fmt.Errorf("%w in '%s'", err, method.Operation.Id)
+									}
+
+									if len(stringChecks) > 0 {
+										routerGen = append(routerGen, stringChecks...)
+										routerGen = append(routerGen, '\n')
+									}
+
 									routerGen = append(routerGen, []byte(fmt.Sprintf(`      params.%s = paramValue
-`, modelParamName))...)
-								} else if param.Required {
-									routerGen = append(routerGen, []byte(fmt.Sprintf(`      params.%s = param
 `, modelParamName))...)
 								} else {
-									routerGen = append(routerGen, []byte(fmt.Sprintf(`      if param == "" {
+									stringChecks, err := validation.parameterChecks("      ", paramName, param, "param")
+									if err != nil {
+										return fmt.Errorf("%w in '%s'", err, method.Operation.Id)
+									}
+
+									if len(stringChecks) > 0 {
+										routerGen = append(routerGen, stringChecks...)
+										routerGen = append(routerGen, '\n')
+									}
+
+									if param.Required {
+										routerGen = append(routerGen, []byte(fmt.Sprintf(`      params.%s = param
+`, modelParamName))...)
+									} else {
+										routerGen = append(routerGen, []byte(fmt.Sprintf(`      if param == "" {
         params.%s = nil
       } else {
         params.%s = &param
       }
 `, modelParamName, modelParamName))...)
+									}
 								}
 							case "bool":
 								routerGen = append(routerGen, []byte(`      paramValue, err := http.ParseBool(param)
@@ -533,20 +547,48 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 				}
 
 				if method.Operation.RequestModel != "" {
+					requestModel := method.Operation.RequestModel
+
 					routerGen = append(routerGen, '\n')
 
 					routerGen = append(routerGen, []byte(fmt.Sprintf(`  {
     var request %s
 
     if err := http.ReadBody(ctx, &request); err != nil {
-      http.HandleError(ctx, fmt.Errorf("%%w: invalid request body: %%w", http.ErrBadRequest, err))
+      validator.Malformed("body", err)
+    } else {
+`, requestModel))...)
+
+					if constraints, ok := apiSchema.Api.Constraints[requestModel]; ok {
+						apiModel, ok := apiModels[requestModel]
+						if !ok {
+							return fmt.Errorf("%w: '%s'", errNoSchemaFound, requestModel)
+						}
 
-      return
+						funcName, err := validation.modelValidator(requestModel, apiModel, constraints)
+						if err != nil {
+							return err
+						}
+
+						routerGen = append(routerGen, []byte(fmt.Sprintf(`      %s(validator, &request)
+
+`, funcName))...)
+					}
+
+					routerGen = append(routerGen, []byte(`      params.Request = &request
     }
+  }
+`)...)
+				}
 
-    params.Request = &request
+				if len(method.Operation.Parameters) > 0 || method.Operation.RequestModel != "" {
+					routerGen = append(routerGen, []byte(`
+  if err := validator.Err(); err != nil {
+    http.HandleError(ctx, err)
+
+    return
   }
-`, method.Operation.RequestModel))...)
+`)...)
 				}
 
 				if method.Operation.RawBody {
@@ -634,6 +676,41 @@ func handleWithController(controller controllers.Controller, handler func(ctx *f
 			routerGen = append(routerGen, '\n')
 		}
 
+		if len(validation.patternsDecl) > 0 {
+			routerGen = append(routerGen, validation.patternsDecl...)
+		}
+
+		if len(validation.funcsDecl) > 0 {
+			routerGen = append(routerGen, validation.funcsDecl...)
+		}
+
+		var routerImports [][]string
+
+		routerImports = append(routerImports, []string{"", "slices"})
+		routerImports = append(routerImports, []string{"", "context"})
+		routerImports = append(routerImports, []string{"", "github.com/valyala/fasthttp"})
+
+		// fmt and regexp are only used by some of the generated code, an unused import
+		// would not compile, so they are added here whether the api lists them or not.
+		generatedImports := map[string]bool{
+			"fmt":    hasFile,
+			"regexp": len(validation.patterns) > 0,
+		}
+
+		for _, routerImport := range apiSchema.Api.RouterImports {
+			if _, ok := generatedImports[routerImport[len(routerImport)-1]]; !ok {
+				routerImports = append(routerImports, routerImport)
+			}
+		}
+
+		for _, importPath := range []string{"fmt", "regexp"} {
+			if generatedImports[importPath] {
+				routerImports = append(routerImports, []string{"", importPath})
+			}
+		}
+
+		routerGen = append(generateFile(g.config.ApiPackageName, routerImports), routerGen...)
+
 		//nolint:gosec // G306: generated file permissions are intentionally permissive
 		if err := os.WriteFile(routerFilename, routerGen, os.ModePerm); err != nil {
 			return err
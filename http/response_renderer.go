@@ -81,7 +81,21 @@ func (r *ResponseRendererImpl) Error(ctx *fasthttp.RequestCtx, err error) {
 		statusCode = fasthttp.StatusInternalServerError
 	}
 
-	errorMessage := r.protoRenderer.Error(statusCode, err)
+	var (
+		errorMessage  proto.Message
+		validationErr *ValidationError
+	)
+
+	if errors.As(err, &validationErr) {
+		if validationRenderer, ok := r.protoRenderer.(ValidationProtocolRenderer); ok {
+			errorMessage = validationRenderer.ValidationError(validationErr)
+		} else {
+			errorMessage = validationErr.Proto()
+		}
+	} else {
+		errorMessage = r.protoRenderer.Error(statusCode, err)
+	}
+
 	if err := renderResponse(ctx, statusCode, errorMessage); err != nil {
 		r.log.GetLogger(ctx).WithError(err).Error("output error")
 	}
@@ -9,6 +9,8 @@ type Router interface {
 	OPTIONS(path string, handle fasthttp.RequestHandler)
 	GET(path string, handle fasthttp.RequestHandler)
 	POST(path string, handle fasthttp.RequestHandler)
+	PUT(path string, handle fasthttp.RequestHandler)
+	PATCH(path string, handle fasthttp.RequestHandler)
 	DELETE(path string, handle fasthttp.RequestHandler)
 	Handle() fasthttp.RequestHandler
 }
@@ -35,6 +37,14 @@ func (r *RouterImpl) POST(path string, handle fasthttp.RequestHandler) {
 	r.router.POST(path, handle)
 }
 
+func (r *RouterImpl) PUT(path string, handle fasthttp.RequestHandler) {
+	r.router.PUT(path, handle)
+}
+
+func (r *RouterImpl) PATCH(path string, handle fasthttp.RequestHandler) {
+	r.router.PATCH(path, handle)
+}
+
 func (r *RouterImpl) DELETE(path string, handle fasthttp.RequestHandler) {
 	r.router.DELETE(path, handle)
 }
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	ValidationRuleRequired  = "required"
+	ValidationRuleMalformed = "malformed"
+	ValidationRuleMin       = "min"
+	ValidationRuleMax       = "max"
+	ValidationRuleMinLength = "minLength"
+	ValidationRuleMaxLength = "maxLength"
+	ValidationRulePattern   = "pattern"
+	ValidationRuleEnum      = "enum"
+	ValidationRuleFormat    = "format"
+)
+
+const (
+	ValidationFormatEmail = "email"
+	ValidationFormatUuid  = "uuid"
+	ValidationFormatUri   = "uri"
+)
+
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// ValidationError lists every invalid field of a request, it is a bad request.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+
+	for _, violation := range e.Violations {
+		messages = append(messages, fmt.Sprintf("%s: %s", violation.Field, violation.Message))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrBadRequest, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrBadRequest
+}
+
+// Proto renders the violations when the protocol renderer is not a ValidationProtocolRenderer.
+func (e *ValidationError) Proto() proto.Message {
+	violations := make([]any, 0, len(e.Violations))
+
+	for _, violation := range e.Violations {
+		violations = append(violations, map[string]any{
+			"field":   violation.Field,
+			"rule":    violation.Rule,
+			"message": violation.Message,
+		})
+	}
+
+	result, err := structpb.NewStruct(map[string]any{
+		"error":      ErrBadRequest.Error(),
+		"violations": violations,
+	})
+	if err != nil {
+		// Only strings are put in, so this never happens.
+		panic(err)
+	}
+
+	return result
+}
+
+// ValidationProtocolRenderer is implemented by protocol renderers rendering the
+// violations of a ValidationError in their own error model.
+type ValidationProtocolRenderer interface {
+	ValidationError(err *ValidationError) proto.Message
+}
+
+// Validator collects the violations of a request, the generated request handlers check
+// every parameter and body field before reporting them all at once.
+type Validator struct {
+	violations []Violation
+}
+
+func NewValidator() *Validator {
+	return &Validator{
+		violations: make([]Violation, 0),
+	}
+}
+
+func (v *Validator) Add(field string, rule string, message string) {
+	v.violations = append(v.violations, Violation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	})
+}
+
+func (v *Validator) Required(field string, present bool) bool {
+	if !present {
+		v.Add(field, ValidationRuleRequired, "is required")
+	}
+
+	return present
+}
+
+func (v *Validator) Malformed(field string, err error) {
+	v.Add(field, ValidationRuleMalformed, fmt.Sprintf("is malformed: %v", err))
+}
+
+func (v *Validator) Min(field string, value float64, minValue float64) {
+	if value < minValue {
+		v.Add(field, ValidationRuleMin, fmt.Sprintf("must be at least %v", minValue))
+	}
+}
+
+func (v *Validator) Max(field string, value float64, maxValue float64) {
+	if value > maxValue {
+		v.Add(field, ValidationRuleMax, fmt.Sprintf("must be at most %v", maxValue))
+	}
+}
+
+func (v *Validator) MinLength(field string, value string, minLength int) {
+	v.MinItems(field, utf8.RuneCountInString(value), minLength)
+}
+
+func (v *Validator) MaxLength(field string, value string, maxLength int) {
+	v.MaxItems(field, utf8.RuneCountInString(value), maxLength)
+}
+
+func (v *Validator) MinItems(field string, length int, minLength int) {
+	if length < minLength {
+		v.Add(field, ValidationRuleMinLength, fmt.Sprintf("must have a length of at least %d", minLength))
+	}
+}
+
+func (v *Validator) MaxItems(field string, length int, maxLength int) {
+	if length > maxLength {
+		v.Add(field, ValidationRuleMaxLength, fmt.Sprintf("must have a length of at most %d", maxLength))
+	}
+}
+
+func (v *Validator) Pattern(field string, value string, pattern *regexp.Regexp) {
+	if !pattern.MatchString(value) {
+		v.Add(field, ValidationRulePattern, fmt.Sprintf("must match %s", pattern))
+	}
+}
+
+func (v *Validator) Enum(field string, value string, values ...string) {
+	if !slices.Contains(values, value) {
+		v.Add(field, ValidationRuleEnum, fmt.Sprintf("must be one of %s", strings.Join(values, ", ")))
+	}
+}
+
+func (v *Validator) Format(field string, value string, format string) {
+	valid := true
+
+	switch format {
+	case ValidationFormatEmail:
+		address, err := mail.ParseAddress(value)
+		valid = err == nil && address.Address == value
+	case ValidationFormatUuid:
+		_, err := uuid.Parse(value)
+		valid = err == nil
+	case ValidationFormatUri:
+		uri, err := url.Parse(value)
+		valid = err == nil && uri.Scheme != "" && (uri.Host != "" || uri.Opaque != "")
+	}
+
+	if !valid {
+		v.Add(field, ValidationRuleFormat, fmt.Sprintf("must be a valid %s", format))
+	}
+}
+
+// Err returns a *ValidationError when there are violations.
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Violations: v.violations,
+	}
+}
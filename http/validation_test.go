@@ -0,0 +1,102 @@
+package http
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testValidationProtoRenderer struct {
+	testProtoRenderer
+}
+
+func (r *testValidationProtoRenderer) ValidationError(err *ValidationError) proto.Message {
+	return wrapperspb.String(err.Violations[0].Field)
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	validator := NewValidator()
+
+	require.NoError(t, validator.Err())
+
+	validator.Required("id", true)
+	validator.Required("name", false)
+	validator.Min("page", 0, 1)
+	validator.Max("limit", 101, 100)
+	validator.MinLength("title", "ab", 3)
+	validator.MaxLength("title", "абв", 3)
+	validator.MaxItems("tags", 3, 2)
+	validator.Pattern("slug", "Not A Slug", regexp.MustCompile(`^[a-z-]+$`))
+	validator.Enum("order", "up", "asc", "desc")
+	validator.Format("email", "John <john@example.com>", ValidationFormatEmail)
+	validator.Format("email", "john@example.com", ValidationFormatEmail)
+	validator.Format("id", "123", ValidationFormatUuid)
+	validator.Format("site", "/relative", ValidationFormatUri)
+	validator.Format("site", "https://example.com", ValidationFormatUri)
+	validator.Malformed("at", errRender)
+
+	err := validator.Err()
+	require.ErrorIs(t, err, ErrBadRequest)
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+
+	rules := make([]string, 0, len(validationErr.Violations))
+
+	for _, violation := range validationErr.Violations {
+		rules = append(rules, violation.Field+":"+violation.Rule)
+	}
+
+	assert.Equal(t, []string{
+		"name:required",
+		"page:min",
+		"limit:max",
+		"title:minLength",
+		"tags:maxLength",
+		"slug:pattern",
+		"order:enum",
+		"email:format",
+		"id:format",
+		"site:format",
+		"at:malformed",
+	}, rules)
+}
+
+func TestResponseRendererValidationError(t *testing.T) {
+	t.Parallel()
+
+	validator := NewValidator()
+	validator.Required("name", false)
+	validator.Enum("order", "up", "asc", "desc")
+
+	err := validator.Err()
+
+	ctx := newRenderCtx(mediaTypeJSON)
+
+	NewResponseRenderer(&testProtoRenderer{}).Error(ctx, err)
+
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+	assert.JSONEq(t, `{
+		"error": "bad request",
+		"violations": [
+			{"field": "name", "rule": "required", "message": "is required"},
+			{"field": "order", "rule": "enum", "message": "must be one of asc, desc"}
+		]
+	}`, string(ctx.Response.Body()))
+
+	ctx = newRenderCtx(mediaTypeJSON)
+
+	NewResponseRenderer(&testValidationProtoRenderer{}).Error(ctx, errors.Join(errRender, err))
+
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+	assert.JSONEq(t, `"name"`, string(ctx.Response.Body()))
+}
@@ -489,6 +489,8 @@ func (c *ClientImpl) performRequest(ctx context.Context, method, uri string, cfg
 		return nil, err
 	}
 
+	conditional := isConditionalRequest(&req.Header)
+
 	req.SetTimeout(c.config.Timeout())
 
 	var err error
@@ -517,12 +519,16 @@ func (c *ClientImpl) performRequest(ctx context.Context, method, uri string, cfg
 		response.Headers[headerKey] = append(response.Headers[headerKey], headerValue)
 	})
 
-	c.logRequest(ctx, method, url, response, err, requestTimeTracker)
+	c.logRequest(ctx, method, url, response, conditional, err, requestTimeTracker)
 
 	if err != nil {
 		return response, err
 	}
 
+	if conditional && response.GetStatusCode() == http.StatusNotModified {
+		return response, nil
+	}
+
 	return c.handleStatusCode(response)
 }
 
@@ -530,6 +536,7 @@ func (c *ClientImpl) logRequest(
 	ctx context.Context,
 	method, url string,
 	response Response,
+	conditional bool,
 	err error,
 	tracker *timetrack.TimeTracker,
 ) {
@@ -537,11 +544,13 @@ func (c *ClientImpl) logRequest(
 
 	log := c.log.GetLogger(ctx)
 
+	success := err == nil && (response == nil || isSuccessStatusCode(response.GetStatusCode(), conditional))
+
 	fields := map[string]any{
 		"logger":         c.config.Name(),
 		"method":         method,
 		"url":            url,
-		"success":        err == nil && (response == nil || (response.GetStatusCode() >= 200 && response.GetStatusCode() < 300)),
+		"success":        success,
 		"execution_time": tracker.Duration().Milliseconds(),
 	}
 
@@ -559,13 +568,23 @@ func (c *ClientImpl) logRequest(
 	switch {
 	case err != nil:
 		log.WithError(err).Error(url)
-	case response != nil && (response.GetStatusCode() < 200 || response.GetStatusCode() >= 300):
+	case !success:
 		log.Warn(url)
 	default:
 		log.Debug(url)
 	}
 }
 
+// isConditionalRequest reports whether the request carries cache validators,
+// a 304 Not Modified is a successful answer to it.
+func isConditionalRequest(header *fasthttp.RequestHeader) bool {
+	return len(header.Peek("If-None-Match")) > 0 || len(header.Peek("If-Modified-Since")) > 0
+}
+
+func isSuccessStatusCode(statusCode int, conditional bool) bool {
+	return (statusCode >= 200 && statusCode < 300) || (conditional && statusCode == http.StatusNotModified)
+}
+
 func (c *ClientImpl) handleStatusCode(response Response) (Response, error) {
 	statusCode := response.GetStatusCode()
 	body := response.GetBody()
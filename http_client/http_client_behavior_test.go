@@ -206,6 +206,24 @@ func TestClientImpl_NoFollowRedirects(t *testing.T) {
 	assert.Equal(t, http.StatusFound, resp.GetStatusCode())
 }
 
+func TestClientImpl_NotModified(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, newTestConfig(server.URL))
+
+	resp, err := client.Get(context.Background(), "/cached", WithHeaders(Headers{"If-None-Match": {`"v1"`}}))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.GetStatusCode())
+
+	_, err = client.Get(context.Background(), "/cached")
+	require.ErrorIs(t, err, ErrNon200HttpCode)
+}
+
 func TestClientImpl_RequestIdPropagation(t *testing.T) {
 	t.Parallel()
 
@@ -79,6 +79,10 @@ func WithHeaders(headers Headers) RequestOption {
 	}
 }
 
+func WithBearerToken(token string) RequestOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
 func WithQueryParam(key, value string) RequestOption {
 	return func(cfg *RequestConfig) {
 		if cfg.QueryParams == nil {
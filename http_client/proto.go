@@ -0,0 +1,67 @@
+package http_client
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoFormat is the media type proto messages are sent and accepted as, the same
+// ones the http package of the server negotiates.
+type ProtoFormat string
+
+const (
+	ProtoFormatJson     ProtoFormat = "application/json"
+	ProtoFormatProtobuf ProtoFormat = "application/protobuf"
+)
+
+var protoJsonMarshaller = protojson.MarshalOptions{
+	UseProtoNames:   true,
+	EmitUnpopulated: false,
+}
+
+var protoJsonUnmarshaller = protojson.UnmarshalOptions{
+	DiscardUnknown: true,
+}
+
+func MarshalProto(message proto.Message, format ProtoFormat) ([]byte, error) {
+	if format == ProtoFormatProtobuf {
+		return proto.Marshal(message)
+	}
+
+	return protoJsonMarshaller.Marshal(message)
+}
+
+// UnmarshalProto decodes the response body by its Content-Type, json is the default.
+func UnmarshalProto(response Response, message proto.Message) error {
+	contentType := ""
+
+	for key, values := range response.GetHeaders() {
+		if strings.EqualFold(key, "Content-Type") && len(values) > 0 {
+			contentType = values[0]
+
+			break
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+
+	switch mediaType {
+	case string(ProtoFormatProtobuf), "application/x-protobuf":
+		err = proto.Unmarshal(response.GetBody(), message)
+	default:
+		err = protoJsonUnmarshaller.Unmarshal(response.GetBody(), message)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to decode %T: %w", message, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package http_client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProto_MarshalUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []ProtoFormat{ProtoFormatJson, ProtoFormatProtobuf} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			body, err := MarshalProto(wrapperspb.String("hello"), format)
+			require.NoError(t, err)
+
+			response := &ResponseImpl{
+				StatusCode: 200,
+				Headers:    Headers{"Content-Type": []string{string(format) + "; charset=utf-8"}},
+				Body:       body,
+			}
+
+			var message wrapperspb.StringValue
+
+			require.NoError(t, UnmarshalProto(response, &message))
+			require.Equal(t, "hello", message.GetValue())
+		})
+	}
+
+	response := &ResponseImpl{
+		StatusCode: 200,
+		Headers:    Headers{},
+		Body:       []byte("not json"),
+	}
+
+	require.Error(t, UnmarshalProto(response, &wrapperspb.StringValue{}))
+}
@@ -2,7 +2,12 @@ package lazy
 
 import (
 	"context"
+	"runtime/debug"
 	"sync"
+	"time"
+
+	"github.com/pixality-inc/golang-core/concurrency"
+	"github.com/pixality-inc/golang-core/retry"
 )
 
 type Func[T any] func(ctx context.Context) (T, error)
@@ -11,24 +16,149 @@ type Lazy[T any] interface {
 	Get(ctx context.Context) (T, error)
 }
 
+type Resettable[T any] interface {
+	Lazy[T]
+	Reset()
+}
+
 type Impl[T any] struct {
 	function Func[T]
+	options  *Options
+	state    *loadState[T]
+	mutex    sync.Mutex
+}
+
+// loadState is a single load attempt, done is closed once value and err are set.
+type loadState[T any] struct {
+	done     chan struct{}
 	value    T
 	err      error
-	once     sync.Once
+	loadedAt time.Time
+	failures int
 }
 
-func New[T any](function Func[T]) Lazy[T] {
+// New returns a lazy value loaded on the first Get.
+// The load is detached from the caller's cancellation, so a canceled caller only stops waiting:
+// the load keeps running and its result is shared with later callers.
+// By default both the value and the error are cached forever, see WithTTL and WithRetryPolicy.
+// A panicking function yields a *concurrency.PanicError, cached like any other error.
+func New[T any](function Func[T], options ...Option) *Impl[T] {
 	return &Impl[T]{
 		function: function,
-		once:     sync.Once{},
+		options:  applyOptions(options...),
+		state:    nil,
+		mutex:    sync.Mutex{},
 	}
 }
 
+// NewRetryable returns a lazy value that loads again after a failure, respecting the policy backoff.
+func NewRetryable[T any](function Func[T], policy retry.Policy, options ...Option) *Impl[T] {
+	return New(function, append([]Option{WithRetryPolicy(policy)}, options...)...)
+}
+
+// NewExpiring returns a lazy value that loads again once the loaded value is older than ttl.
+func NewExpiring[T any](function Func[T], ttl time.Duration, options ...Option) *Impl[T] {
+	return New(function, append([]Option{WithTTL(ttl)}, options...)...)
+}
+
 func (l *Impl[T]) Get(ctx context.Context) (T, error) {
-	l.once.Do(func() {
-		l.value, l.err = l.function(ctx)
-	})
+	state := l.acquire(ctx)
+
+	select {
+	case <-state.done:
+		return state.value, state.err
+
+	case <-ctx.Done():
+		var defaultValue T
+
+		return defaultValue, ctx.Err()
+	}
+}
+
+// Reset drops the loaded value or error, the next Get loads again.
+// Callers already waiting for an in-flight load still receive its result.
+func (l *Impl[T]) Reset() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.state = nil
+}
+
+func (l *Impl[T]) acquire(ctx context.Context) *loadState[T] {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := l.state
+
+	if current != nil && !l.shouldReload(current) {
+		return current
+	}
+
+	next := &loadState[T]{
+		done: make(chan struct{}),
+	}
+
+	if current != nil {
+		next.failures = current.failures
+	}
+
+	l.state = next
+
+	go l.load(context.WithoutCancel(ctx), next)
+
+	return next
+}
+
+func (l *Impl[T]) load(ctx context.Context, state *loadState[T]) {
+	defer close(state.done)
+
+	state.value, state.err = l.call(ctx)
+	state.loadedAt = l.options.clock.Now()
+
+	if state.err != nil {
+		state.failures++
+	} else {
+		state.failures = 0
+	}
+}
+
+// call runs the function, a panic is returned as a *concurrency.PanicError:
+// the load runs in its own goroutine, where a panic would crash the process.
+func (l *Impl[T]) call(ctx context.Context) (value T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			var defaultValue T
+
+			value = defaultValue
+			err = &concurrency.PanicError{
+				Value: recovered,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return l.function(ctx)
+}
+
+func (l *Impl[T]) shouldReload(state *loadState[T]) bool {
+	select {
+	case <-state.done:
+	default:
+		return false
+	}
+
+	if state.err == nil {
+		return l.options.ttl > 0 && l.options.clock.Since(state.loadedAt) >= l.options.ttl
+	}
+
+	policy := l.options.retryPolicy
+	if policy == nil || !policy.Enabled() {
+		return false
+	}
+
+	if policy.MaxAttempts() > 0 && state.failures >= policy.MaxAttempts() {
+		return false
+	}
 
-	return l.value, l.err
+	return l.options.clock.Since(state.loadedAt) >= retry.CalculateBackoff(state.failures-1, policy)
 }
@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/concurrency"
+	"github.com/pixality-inc/golang-core/retry"
 )
 
 var (
@@ -76,6 +79,35 @@ func TestLazyCachesError(t *testing.T) {
 	require.Equal(t, int64(1), calls.Load())
 }
 
+func TestLazyRecoversPanic(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	laz := New(func(ctx context.Context) (int, error) {
+		calls.Add(1)
+
+		panic(errLazyTest)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	value, err := laz.Get(ctx)
+	require.ErrorIs(t, err, concurrency.ErrPanic)
+	require.ErrorIs(t, err, errLazyTest)
+	require.Zero(t, value)
+
+	var panicErr *concurrency.PanicError
+
+	require.ErrorAs(t, err, &panicErr)
+	require.NotEmpty(t, panicErr.Stack)
+
+	_, err = laz.Get(ctx)
+	require.ErrorIs(t, err, concurrency.ErrPanic)
+	require.Equal(t, int64(1), calls.Load())
+}
+
 func TestLazyPassesGetContextToBody(t *testing.T) {
 	t.Parallel()
 
@@ -155,6 +187,239 @@ func TestLazyConcurrentGetCallsBodyOnce(t *testing.T) {
 	require.Equal(t, int64(1), calls.Load())
 }
 
+type manualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{
+		now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *manualClock) Sleep(duration time.Duration) {
+	c.Advance(duration)
+}
+
+func (c *manualClock) Since(value time.Time) time.Duration {
+	return c.Now().Sub(value)
+}
+
+func (c *manualClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+func (c *manualClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+func TestLazyCanceledCallerDoesNotPoisonValue(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	laz := New(func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		return 42, nil
+	})
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := laz.Get(firstCtx)
+		firstErr <- err
+	}()
+
+	waitForClosed(t, started)
+	cancelFirst()
+
+	select {
+	case err := <-firstErr:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for canceled caller")
+	}
+
+	close(release)
+
+	value, err := laz.Get(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 42, value)
+}
+
+func TestLazyCallerDeadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	laz := New(func(ctx context.Context) (int, error) {
+		<-release
+
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := laz.Get(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLazyRetryableRetriesAfterBackoff(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	clk := newManualClock()
+
+	policy := retry.NewPolicy(
+		retry.WithEnabled(true),
+		retry.WithMaxAttempts(0),
+		retry.WithInitialInterval(time.Second),
+		retry.WithBackoffCoefficient(2),
+	)
+
+	laz := NewRetryable(func(ctx context.Context) (int, error) {
+		if calls.Add(1) < 3 {
+			return 0, errLazyTest
+		}
+
+		return 7, nil
+	}, policy, WithClock(clk))
+
+	_, err := laz.Get(context.Background())
+	require.ErrorIs(t, err, errLazyTest)
+
+	_, err = laz.Get(context.Background())
+	require.ErrorIs(t, err, errLazyTest)
+	require.Equal(t, int64(1), calls.Load())
+
+	clk.Advance(time.Second)
+
+	_, err = laz.Get(context.Background())
+	require.ErrorIs(t, err, errLazyTest)
+	require.Equal(t, int64(2), calls.Load())
+
+	clk.Advance(time.Second)
+
+	_, err = laz.Get(context.Background())
+	require.ErrorIs(t, err, errLazyTest)
+	require.Equal(t, int64(2), calls.Load())
+
+	clk.Advance(time.Second)
+
+	value, err := laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 7, value)
+	require.Equal(t, int64(3), calls.Load())
+}
+
+func TestLazyRetryableStopsAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	clk := newManualClock()
+
+	policy := retry.NewPolicy(
+		retry.WithEnabled(true),
+		retry.WithMaxAttempts(2),
+		retry.WithInitialInterval(time.Second),
+	)
+
+	laz := NewRetryable(func(ctx context.Context) (int, error) {
+		calls.Add(1)
+
+		return 0, errLazyTest
+	}, policy, WithClock(clk))
+
+	for range 4 {
+		_, err := laz.Get(context.Background())
+		require.ErrorIs(t, err, errLazyTest)
+
+		clk.Advance(time.Minute)
+	}
+
+	require.Equal(t, int64(2), calls.Load())
+
+	laz.Reset()
+
+	_, err := laz.Get(context.Background())
+	require.ErrorIs(t, err, errLazyTest)
+	require.Equal(t, int64(3), calls.Load())
+}
+
+func TestLazyExpiringReloadsAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	clk := newManualClock()
+
+	laz := NewExpiring(func(ctx context.Context) (int, error) {
+		return int(calls.Add(1)), nil
+	}, time.Minute, WithClock(clk))
+
+	value, err := laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	clk.Advance(30 * time.Second)
+
+	value, err = laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	clk.Advance(30 * time.Second)
+
+	value, err = laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestLazyReset(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	laz := New(func(ctx context.Context) (int, error) {
+		return int(calls.Add(1)), nil
+	})
+
+	value, err := laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	laz.Reset()
+
+	value, err = laz.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
 func waitForClosed(t *testing.T, ch <-chan struct{}) {
 	t.Helper()
 
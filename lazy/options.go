@@ -0,0 +1,56 @@
+package lazy
+
+import (
+	"time"
+
+	"github.com/pixality-inc/golang-core/clock"
+	"github.com/pixality-inc/golang-core/retry"
+)
+
+type Options struct {
+	ttl         time.Duration
+	retryPolicy retry.Policy
+	clock       clock.Clock
+}
+
+func NewDefaultOptions() *Options {
+	return &Options{
+		ttl:         0,
+		retryPolicy: nil,
+		clock:       clock.New(),
+	}
+}
+
+type Option func(options *Options)
+
+// WithTTL expires a successfully loaded value after ttl, the next Get loads it again.
+func WithTTL(ttl time.Duration) Option {
+	return func(options *Options) {
+		options.ttl = ttl
+	}
+}
+
+// WithRetryPolicy makes a failed load retryable: the error is returned until the backoff
+// for the current failure count elapses, then the next Get loads again.
+// A policy with MaxAttempts > 0 caches the error permanently (until Reset) after that many failures.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(options *Options) {
+		options.retryPolicy = policy
+	}
+}
+
+func WithClock(clk clock.Clock) Option {
+	return func(options *Options) {
+		options.clock = clk
+	}
+}
+
+func applyOptions(options ...Option) *Options {
+	opts := NewDefaultOptions()
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
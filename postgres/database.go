@@ -27,6 +27,7 @@ type Database interface {
 	Executor() (QueryExecutor, error)
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 	BeginTxFunc(ctx context.Context, opts pgx.TxOptions, f func(pgx.Tx) error) error
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
 	IsConnected() bool
 	GetCircuitBreaker() circuit_breaker.CircuitBreaker
 }
@@ -125,6 +126,22 @@ func (d *DatabaseImpl) BeginTxFunc(ctx context.Context, opts pgx.TxOptions, txFu
 	})
 }
 
+// Acquire returns a dedicated connection of the pool for session state like advisory
+// locks, the caller must release it.
+func (d *DatabaseImpl) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	return circuit_breaker.ExecuteWithResult(
+		d.circuitBreaker,
+		func() (*pgxpool.Conn, error) {
+			return d.pool.Acquire(ctx)
+		},
+		nil,
+	)
+}
+
 func (d *DatabaseImpl) Close() {
 	if d.pool != nil {
 		d.pool.Close()
@@ -0,0 +1,132 @@
+//nolint:recvcheck
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/pixality-inc/golang-core/encryption"
+	"github.com/pixality-inc/golang-core/json"
+)
+
+var (
+	ErrNoColumnEncryption    = errors.New("column encryption is not configured, set the Envelope of the column")
+	errExpectedBytesToDecode = errors.New("expected []byte to decrypt")
+)
+
+// Encrypted
+
+// Encrypted stores Data as JSON encrypted with a per-record data key (bytea column).
+// Envelope has to be set before Scan and Value, scan into NewEncrypted(envelope, zero) for reads.
+// The driver interfaces carry no context, so the envelope resolves keys with context.Background().
+// A NULL column scans into the zero value of T.
+type Encrypted[T any] struct {
+	Data     T
+	Envelope *encryption.Envelope
+}
+
+func NewEncrypted[T any](envelope *encryption.Envelope, data T) Encrypted[T] {
+	return Encrypted[T]{
+		Data:     data,
+		Envelope: envelope,
+	}
+}
+
+func NewEncryptedRef[T any](envelope *encryption.Envelope, data *T) *Encrypted[T] {
+	if data == nil {
+		return nil
+	}
+
+	e := NewEncrypted[T](envelope, *data)
+
+	return &e
+}
+
+func (e *Encrypted[T]) Scan(value any) error {
+	return scanEncrypted(e.Envelope, value, &e.Data)
+}
+
+func (e Encrypted[T]) Value() (driver.Value, error) {
+	return encryptedValue(e.Envelope, e.Data, (*encryption.Envelope).Encrypt)
+}
+
+// Deterministic Encrypted
+
+// DeterministicEncrypted stores Data as deterministically encrypted JSON (bytea column).
+// Equal values produce equal ciphertexts, so the column can be used in equality lookups and unique indexes.
+// Like Encrypted, it needs its Envelope set before Scan and Value.
+// A NULL column scans into the zero value of T.
+type DeterministicEncrypted[T any] struct {
+	Data     T
+	Envelope *encryption.Envelope
+}
+
+func NewDeterministicEncrypted[T any](envelope *encryption.Envelope, data T) DeterministicEncrypted[T] {
+	return DeterministicEncrypted[T]{
+		Data:     data,
+		Envelope: envelope,
+	}
+}
+
+func NewDeterministicEncryptedRef[T any](envelope *encryption.Envelope, data *T) *DeterministicEncrypted[T] {
+	if data == nil {
+		return nil
+	}
+
+	e := NewDeterministicEncrypted[T](envelope, *data)
+
+	return &e
+}
+
+func (e *DeterministicEncrypted[T]) Scan(value any) error {
+	return scanEncrypted(e.Envelope, value, &e.Data)
+}
+
+func (e DeterministicEncrypted[T]) Value() (driver.Value, error) {
+	return encryptedValue(e.Envelope, e.Data, (*encryption.Envelope).EncryptDeterministic)
+}
+
+func scanEncrypted[T any](envelope *encryption.Envelope, value any, out *T) error {
+	if value == nil {
+		var zero T
+
+		*out = zero
+
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: got %T", errExpectedBytesToDecode, value)
+	}
+
+	if envelope == nil {
+		return fmt.Errorf("scan encrypted column: %w", ErrNoColumnEncryption)
+	}
+
+	plaintext, err := envelope.Decrypt(context.Background(), data, nil)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, out)
+}
+
+func encryptedValue[T any](
+	envelope *encryption.Envelope,
+	data T,
+	encrypt func(envelope *encryption.Envelope, ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error),
+) (driver.Value, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("encrypt column value: %w", ErrNoColumnEncryption)
+	}
+
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return encrypt(envelope, context.Background(), plaintext, nil)
+}
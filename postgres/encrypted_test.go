@@ -0,0 +1,123 @@
+package postgres_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pixality-inc/golang-core/encryption"
+	"github.com/pixality-inc/golang-core/postgres"
+)
+
+type encryptedDocument struct {
+	Number  string `json:"number"`
+	Country string `json:"country"`
+}
+
+func newTestEnvelope(t *testing.T) *encryption.Envelope {
+	t.Helper()
+
+	provider, err := encryption.NewStaticKeyProvider("v1", map[string][]byte{
+		"v1": bytes.Repeat([]byte{7}, encryption.KeySize),
+	})
+	require.NoError(t, err)
+
+	return encryption.NewEnvelope(provider)
+}
+
+func TestEncryptedValueAndScan(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t)
+	document := encryptedDocument{Number: "AB123456", Country: "DE"}
+
+	value, err := postgres.NewEncrypted(envelope, document).Value()
+	require.NoError(t, err)
+
+	ciphertext, ok := value.([]byte)
+	require.True(t, ok)
+	require.NotContains(t, string(ciphertext), "AB123456")
+
+	scanned := postgres.NewEncrypted(envelope, encryptedDocument{})
+
+	require.NoError(t, scanned.Scan(ciphertext))
+	require.Equal(t, document, scanned.Data)
+
+	require.Error(t, scanned.Scan("not bytes"))
+}
+
+func TestDeterministicEncryptedValue(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t)
+
+	first, err := postgres.NewDeterministicEncrypted(envelope, "+1 555 0100").Value()
+	require.NoError(t, err)
+
+	second, err := postgres.NewDeterministicEncrypted(envelope, "+1 555 0100").Value()
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+
+	scanned := postgres.DeterministicEncrypted[string]{Envelope: envelope}
+
+	require.NoError(t, scanned.Scan(first))
+	require.Equal(t, "+1 555 0100", scanned.Data)
+}
+
+func TestNewEncryptedRef(t *testing.T) {
+	t.Parallel()
+
+	envelope := newTestEnvelope(t)
+
+	require.Nil(t, postgres.NewEncryptedRef[string](envelope, nil))
+	require.Nil(t, postgres.NewDeterministicEncryptedRef[string](envelope, nil))
+
+	value := "value"
+
+	require.Equal(t, "value", postgres.NewEncryptedRef(envelope, &value).Data)
+	require.Same(t, envelope, postgres.NewEncryptedRef(envelope, &value).Envelope)
+	require.Equal(t, "value", postgres.NewDeterministicEncryptedRef(envelope, &value).Data)
+}
+
+func TestEncryptedScanNull(t *testing.T) {
+	t.Parallel()
+
+	scanned := postgres.NewEncrypted(nil, encryptedDocument{Number: "AB123456"})
+
+	require.NoError(t, scanned.Scan(nil))
+	require.Equal(t, encryptedDocument{}, scanned.Data)
+
+	deterministic := postgres.NewDeterministicEncrypted(nil, "+1 555 0100")
+
+	require.NoError(t, deterministic.Scan(nil))
+	require.Empty(t, deterministic.Data)
+}
+
+func TestEncryptedWithoutColumnEncryption(t *testing.T) {
+	t.Parallel()
+
+	_, err := postgres.NewEncrypted(nil, "value").Value()
+	require.ErrorIs(t, err, postgres.ErrNoColumnEncryption)
+
+	var scanned postgres.Encrypted[string]
+
+	require.ErrorIs(t, scanned.Scan([]byte("ciphertext")), postgres.ErrNoColumnEncryption)
+}
+
+func TestEncryptedWithOtherEnvelope(t *testing.T) {
+	t.Parallel()
+
+	value, err := postgres.NewEncrypted(newTestEnvelope(t), "value").Value()
+	require.NoError(t, err)
+
+	provider, err := encryption.NewStaticKeyProvider("v2", map[string][]byte{
+		"v2": bytes.Repeat([]byte{8}, encryption.KeySize),
+	})
+	require.NoError(t, err)
+
+	scanned := postgres.NewEncrypted(encryption.NewEnvelope(provider), "")
+
+	require.Error(t, scanned.Scan(value))
+}
@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MigrationNoTransactionMarker on a line of its own makes the migration file run
+// outside of a transaction, e.g. for CREATE INDEX CONCURRENTLY. Its statements run
+// one by one and the ones run before a failing statement are not reverted, so they
+// should be idempotent (IF NOT EXISTS) for the migration to be retried.
+const MigrationNoTransactionMarker = "-- migrate:no-transaction"
+
+var (
+	ErrMigrationDuplicateVersion = errors.New("duplicate migration version")
+	ErrMigrationNoUp             = errors.New("migration has no up file")
+)
+
+// migrationFilenameRegexp matches 0001_name.sql, 0001_name.up.sql and 0001_name.down.sql,
+// other files of the directory like gen's _gen.sql are not migrations.
+var migrationFilenameRegexp = regexp.MustCompile(`^(\d+)_(.+?)(\.up|\.down)?\.sql$`)
+
+type MigrationScript struct {
+	Sql           string
+	NoTransaction bool
+}
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Checksum string
+	Up       MigrationScript
+	Down     *MigrationScript
+}
+
+// MigrationsFromDir reads the migrations of the directory ordered by version.
+func MigrationsFromDir(dir string) ([]Migration, error) {
+	return MigrationsFromFS(os.DirFS(dir), ".")
+}
+
+// MigrationsFromFS reads the migrations of the directory of fsys, e.g. an embed.FS,
+// ordered by version.
+func MigrationsFromFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsMap := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := migrationFilenameRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version '%s': %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrationsMap[version]
+		if !ok {
+			migration = &Migration{
+				Version:  version,
+				Name:     matches[2],
+				Checksum: "",
+				Up:       MigrationScript{},
+				Down:     nil,
+			}
+
+			migrationsMap[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d: %s and %s", ErrMigrationDuplicateVersion, version, migration.Name, matches[2])
+		}
+
+		script := newMigrationScript(string(content))
+
+		if matches[3] == ".down" {
+			if migration.Down != nil {
+				return nil, fmt.Errorf("%w: %d: %s", ErrMigrationDuplicateVersion, version, entry.Name())
+			}
+
+			migration.Down = &script
+
+			continue
+		}
+
+		if hasUp[version] {
+			return nil, fmt.Errorf("%w: %d: %s", ErrMigrationDuplicateVersion, version, entry.Name())
+		}
+
+		hasUp[version] = true
+		migration.Up = script
+		migration.Checksum = checksum(content)
+	}
+
+	migrations := make([]Migration, 0, len(migrationsMap))
+
+	for version, migration := range migrationsMap {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationNoUp, version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func newMigrationScript(sql string) MigrationScript {
+	noTransaction := false
+
+	for line := range strings.Lines(sql) {
+		if strings.TrimSpace(line) == MigrationNoTransactionMarker {
+			noTransaction = true
+
+			break
+		}
+	}
+
+	return MigrationScript{
+		Sql:           sql,
+		NoTransaction: noTransaction,
+	}
+}
+
+// statements splits the script on the semicolons outside of string literals, quoted
+// identifiers, dollar quoted bodies and comments. Statements like CREATE INDEX
+// CONCURRENTLY can't run in the implicit transaction of a multi statement query, so
+// scripts outside of a transaction run one by one.
+func (s MigrationScript) statements() []string {
+	statements := make([]string, 0)
+
+	sql := s.Sql
+	start := 0
+	hasCode := false
+
+	for idx := 0; idx < len(sql); {
+		switch {
+		case strings.HasPrefix(sql[idx:], "--"):
+			idx = skipLineComment(sql, idx)
+
+		case strings.HasPrefix(sql[idx:], "/*"):
+			idx = skipBlockComment(sql, idx)
+
+		case sql[idx] == '\'':
+			hasCode = true
+			idx = skipQuoted(sql, idx, isEscapeString(sql, idx))
+
+		case sql[idx] == '"':
+			hasCode = true
+			idx = skipQuoted(sql, idx, false)
+
+		case sql[idx] == '$':
+			hasCode = true
+			idx = skipDollarQuoted(sql, idx)
+
+		case sql[idx] == ';':
+			idx++
+
+			if hasCode {
+				statements = append(statements, strings.TrimSpace(sql[start:idx]))
+			}
+
+			start = idx
+			hasCode = false
+
+		default:
+			if !unicode.IsSpace(rune(sql[idx])) {
+				hasCode = true
+			}
+
+			idx++
+		}
+	}
+
+	if hasCode {
+		statements = append(statements, strings.TrimSpace(sql[start:]))
+	}
+
+	return statements
+}
+
+func skipLineComment(sql string, idx int) int {
+	end := strings.IndexByte(sql[idx:], '\n')
+	if end < 0 {
+		return len(sql)
+	}
+
+	return idx + end + 1
+}
+
+// skipBlockComment skips a /* */ comment, they nest in postgres.
+func skipBlockComment(sql string, idx int) int {
+	depth := 0
+
+	for idx < len(sql) {
+		switch {
+		case strings.HasPrefix(sql[idx:], "/*"):
+			depth++
+			idx += 2
+
+		case strings.HasPrefix(sql[idx:], "*/"):
+			depth--
+			idx += 2
+
+			if depth == 0 {
+				return idx
+			}
+
+		default:
+			idx++
+		}
+	}
+
+	return len(sql)
+}
+
+// skipQuoted skips a literal or an identifier quoted by sql[idx], a doubled quote
+// is part of the value and escapes also takes backslash escapes into account.
+func skipQuoted(sql string, idx int, escapes bool) int {
+	quote := sql[idx]
+
+	for idx++; idx < len(sql); idx++ {
+		switch {
+		case escapes && sql[idx] == '\\':
+			idx++
+
+		case sql[idx] == quote:
+			if idx+1 < len(sql) && sql[idx+1] == quote {
+				idx++
+
+				continue
+			}
+
+			return idx + 1
+		}
+	}
+
+	return len(sql)
+}
+
+// isEscapeString reports whether the literal starting at idx is an E'...' string.
+func isEscapeString(sql string, idx int) bool {
+	if idx == 0 || (sql[idx-1] != 'E' && sql[idx-1] != 'e') {
+		return false
+	}
+
+	return idx == 1 || !isIdentifierChar(sql[idx-2])
+}
+
+// skipDollarQuoted skips a $tag$ body $tag$ string, other dollar signs like
+// positional parameters are skipped on their own.
+func skipDollarQuoted(sql string, idx int) int {
+	if idx > 0 && isIdentifierChar(sql[idx-1]) {
+		return idx + 1
+	}
+
+	end := idx + 1
+
+	for end < len(sql) && isIdentifierChar(sql[end]) && (end > idx+1 || !isDigit(sql[end])) {
+		end++
+	}
+
+	if end >= len(sql) || sql[end] != '$' {
+		return idx + 1
+	}
+
+	tag := sql[idx : end+1]
+
+	closing := strings.Index(sql[end+1:], tag)
+	if closing < 0 {
+		return len(sql)
+	}
+
+	return end + 1 + closing + len(tag)
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char >= 0x80
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
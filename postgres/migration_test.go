@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsFromFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"migrations/0002_index.up.sql": &fstest.MapFile{
+			Data: []byte("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY a_idx ON a (b);\nCREATE INDEX CONCURRENTLY a_c_idx\n\tON a (c);\n"),
+		},
+		"migrations/0001_init.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE a (b INT);")},
+		"migrations/0001_init.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE a;")},
+		"migrations/0003_seed.sql":      &fstest.MapFile{Data: []byte("INSERT INTO a VALUES (1);")},
+		"migrations/enums_gen.sql":      &fstest.MapFile{Data: []byte("CREATE TYPE e AS ENUM ('a');")},
+		"migrations/schema.yaml":        &fstest.MapFile{Data: []byte("tables: {}")},
+	}
+
+	migrations, err := MigrationsFromFS(fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+
+	require.Equal(t, int64(1), migrations[0].Version)
+	require.Equal(t, "init", migrations[0].Name)
+	require.False(t, migrations[0].Up.NoTransaction)
+	require.NotNil(t, migrations[0].Down)
+	require.Equal(t, "DROP TABLE a;", migrations[0].Down.Sql)
+	require.Len(t, migrations[0].Checksum, 64)
+
+	require.Equal(t, "index", migrations[1].Name)
+	require.True(t, migrations[1].Up.NoTransaction)
+	require.Nil(t, migrations[1].Down)
+	require.Equal(t, []string{
+		"-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY a_idx ON a (b);",
+		"CREATE INDEX CONCURRENTLY a_c_idx\n\tON a (c);",
+	}, migrations[1].Up.statements())
+
+	require.Equal(t, "seed", migrations[2].Name)
+
+	fsys["migrations/0003_other.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+
+	_, err = MigrationsFromFS(fsys, "migrations")
+	require.ErrorIs(t, err, ErrMigrationDuplicateVersion)
+
+	_, err = MigrationsFromFS(fstest.MapFS{
+		"0004_drop.down.sql": &fstest.MapFile{Data: []byte("SELECT 1;")},
+	}, ".")
+	require.ErrorIs(t, err, ErrMigrationNoUp)
+}
+
+func TestMigrationScriptStatements(t *testing.T) {
+	t.Parallel()
+
+	script := newMigrationScript(`-- migrate:no-transaction
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = NOW();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION noop() RETURNS void AS $body$ SELECT 1; $body$ LANGUAGE sql;
+INSERT INTO notes (text, raw) VALUES ('a; b', E'it\'s; here'), ('it''s;', $1);
+COMMENT ON TABLE "odd;name" IS 'x'; /* a; /* nested; */ comment; */
+-- trailing; comment
+ALTER TYPE status ADD VALUE 'c'`)
+
+	require.True(t, script.NoTransaction)
+	require.Equal(t, []string{
+		"-- migrate:no-transaction\nCREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = NOW();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;",
+		"CREATE FUNCTION noop() RETURNS void AS $body$ SELECT 1; $body$ LANGUAGE sql;",
+		"INSERT INTO notes (text, raw) VALUES ('a; b', E'it\\'s; here'), ('it''s;', $1);",
+		`COMMENT ON TABLE "odd;name" IS 'x';`,
+		"/* a; /* nested; */ comment; */\n-- trailing; comment\nALTER TYPE status ADD VALUE 'c'",
+	}, script.statements())
+}
+
+func TestMigrationsFromDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_init.up.sql"), []byte("SELECT 1;"), 0o600))
+
+	migrations, err := MigrationsFromDir(dir)
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	require.Equal(t, checksum([]byte("SELECT 1;")), migrations[0].Checksum)
+}
+
+func TestVerifyMigrations(t *testing.T) {
+	t.Parallel()
+
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	migrations := []Migration{
+		{Version: 1, Name: "init", Checksum: "a"},
+		{Version: 2, Name: "index", Checksum: "b"},
+	}
+
+	applied := map[int64]appliedMigration{
+		1: {Version: 1, Name: "init", Checksum: "a", AppliedAt: appliedAt},
+	}
+
+	require.NoError(t, verifyMigrations(migrations, applied))
+	require.Equal(t, []MigrationStatus{
+		{Version: 1, Name: "init", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Name: "index"},
+	}, migrationsStatus(migrations, applied))
+
+	applied[2] = appliedMigration{Version: 2, Name: "index", Checksum: "edited", AppliedAt: appliedAt}
+
+	require.ErrorIs(t, verifyMigrations(migrations, applied), ErrMigrationChecksumMismatch)
+
+	applied[2] = appliedMigration{Version: 2, Name: "index", Checksum: "b", AppliedAt: appliedAt}
+	applied[3] = appliedMigration{Version: 3, Name: "seed", Checksum: "c", AppliedAt: appliedAt}
+
+	require.ErrorIs(t, verifyMigrations(migrations, applied), ErrMigrationMissing)
+	require.True(t, migrationsStatus(migrations, applied)[2].Missing)
+}
@@ -0,0 +1,342 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/pixality-inc/golang-core/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const DefaultMigrationsTable = "schema_migrations"
+
+var (
+	ErrMigrationChecksumMismatch = errors.New("applied migration has been edited")
+	ErrMigrationMissing          = errors.New("applied migration file is missing")
+	ErrMigrationOutOfOrder       = errors.New("migration is older than the last applied one")
+	ErrMigrationNoDown           = errors.New("migration has no down file")
+)
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the file differs from the applied one.
+	Modified bool
+	// Missing is set when an applied migration has no file anymore.
+	Missing bool
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type MigratorOption func(migrator *Migrator)
+
+func WithMigrationsTable(tableName string) MigratorOption {
+	return func(migrator *Migrator) {
+		migrator.tableName = tableName
+	}
+}
+
+// WithMigrationsLockId sets the key of the advisory lock, by default it is derived
+// from the table name.
+func WithMigrationsLockId(lockId int64) MigratorOption {
+	return func(migrator *Migrator) {
+		migrator.lockId = lockId
+	}
+}
+
+// Migrator applies versioned migrations and records them with their checksums. Every
+// command holds a pg advisory lock, so replicas starting together run them once.
+type Migrator struct {
+	log        logger.Loggable
+	database   Database
+	migrations []Migration
+	tableName  string
+	lockId     int64
+}
+
+func NewMigrator(database Database, migrations []Migration, options ...MigratorOption) *Migrator {
+	migrator := &Migrator{
+		log:        logger.NewLoggableImplWithService("migrator"),
+		database:   database,
+		migrations: migrations,
+		tableName:  DefaultMigrationsTable,
+		lockId:     0,
+	}
+
+	for _, opt := range options {
+		opt(migrator)
+	}
+
+	if migrator.lockId == 0 {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(migrator.tableName))
+
+		migrator.lockId = int64(hash.Sum64()) //nolint:gosec // the lock key only has to be stable
+	}
+
+	return migrator
+}
+
+// Up applies the pending migrations in version order.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		lastVersion := int64(0)
+
+		for version := range applied {
+			lastVersion = max(lastVersion, version)
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if migration.Version < lastVersion {
+				return fmt.Errorf("%w: %d_%s < %d", ErrMigrationOutOfOrder, migration.Version, migration.Name, lastVersion)
+			}
+
+			m.log.GetLogger(ctx).Infof("Applying migration %d_%s", migration.Version, migration.Name)
+
+			record := func(ctx context.Context, executor QueryExecutor) error {
+				_, err := executor.Exec(
+					ctx,
+					"INSERT INTO "+quoteIdent(m.tableName)+" (version, name, checksum) VALUES ($1, $2, $3)",
+					migration.Version,
+					migration.Name,
+					migration.Checksum,
+				)
+
+				return err
+			}
+
+			if err := m.execute(ctx, conn, migration.Up, record); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DownTo reverts the applied migrations newer than version, newest first.
+func (m *Migrator) DownTo(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		for idx := len(m.migrations) - 1; idx >= 0; idx-- {
+			migration := m.migrations[idx]
+
+			if migration.Version <= version {
+				break
+			}
+
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if migration.Down == nil {
+				return fmt.Errorf("%w: %d_%s", ErrMigrationNoDown, migration.Version, migration.Name)
+			}
+
+			m.log.GetLogger(ctx).Infof("Reverting migration %d_%s", migration.Version, migration.Name)
+
+			record := func(ctx context.Context, executor QueryExecutor) error {
+				_, err := executor.Exec(ctx, "DELETE FROM "+quoteIdent(m.tableName)+" WHERE version = $1", migration.Version)
+
+				return err
+			}
+
+			if err := m.execute(ctx, conn, *migration.Down, record); err != nil {
+				return fmt.Errorf("migration %d_%s revert failed: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status lists the migrations with their state, it doesn't fail on edited or missing files.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.database.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrationsStatus(m.migrations, applied), nil
+}
+
+func (m *Migrator) withLock(
+	ctx context.Context,
+	fn func(conn *pgxpool.Conn, applied map[int64]appliedMigration) error,
+) (err error) {
+	conn, err := m.database.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", m.lockId); err != nil {
+		return fmt.Errorf("failed to take migrations lock: %w", err)
+	}
+
+	defer func() {
+		// the lock is released with the session if the unlock fails
+		if _, unlockErr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", m.lockId); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release migrations lock: %w", unlockErr))
+		}
+	}()
+
+	if err = m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyMigrations(m.migrations, applied); err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+quoteIdent(m.tableName)+` (
+	version    BIGINT      NOT NULL PRIMARY KEY,
+	name       TEXT        NOT NULL,
+	checksum   TEXT        NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`)
+
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, "SELECT version, name, checksum, applied_at FROM "+quoteIdent(m.tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+
+	for rows.Next() {
+		var migration appliedMigration
+
+		if err = rows.Scan(&migration.Version, &migration.Name, &migration.Checksum, &migration.AppliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[migration.Version] = migration
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) execute(
+	ctx context.Context,
+	conn *pgxpool.Conn,
+	script MigrationScript,
+	record func(ctx context.Context, executor QueryExecutor) error,
+) error {
+	if script.NoTransaction {
+		statements := script.statements()
+
+		for idx, statement := range statements {
+			if _, err := conn.Exec(ctx, statement); err != nil {
+				m.log.GetLogger(ctx).WithError(err).Errorf("Migration statement %d of %d failed:\n%s", idx+1, len(statements), statement)
+
+				return fmt.Errorf("statement %d of %d: %w", idx+1, len(statements), err)
+			}
+		}
+
+		return record(ctx, conn)
+	}
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, script.Sql); err != nil {
+			return err
+		}
+
+		return record(ctx, tx)
+	})
+}
+
+// verifyMigrations fails when an applied migration has been edited or deleted.
+func verifyMigrations(migrations []Migration, applied map[int64]appliedMigration) error {
+	for _, status := range migrationsStatus(migrations, applied) {
+		if status.Missing {
+			return fmt.Errorf("%w: %d_%s", ErrMigrationMissing, status.Version, status.Name)
+		}
+
+		if status.Modified {
+			return fmt.Errorf("%w: %d_%s", ErrMigrationChecksumMismatch, status.Version, status.Name)
+		}
+	}
+
+	return nil
+}
+
+func migrationsStatus(migrations []Migration, applied map[int64]appliedMigration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+
+	for _, migration := range migrations {
+		known[migration.Version] = true
+
+		appliedMigration, ok := applied[migration.Version]
+
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: appliedMigration.AppliedAt,
+			Modified:  ok && appliedMigration.Checksum != migration.Checksum,
+			Missing:   false,
+		})
+	}
+
+	for version, appliedMigration := range applied {
+		if known[version] {
+			continue
+		}
+
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      appliedMigration.Name,
+			Applied:   true,
+			AppliedAt: appliedMigration.AppliedAt,
+			Modified:  false,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses
+}